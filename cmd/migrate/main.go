@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/KnoblauchPilze/go-game/database/users/migrations"
+	"github.com/KnoblauchPilze/go-game/pkg/db"
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
+	"github.com/KnoblauchPilze/go-game/pkg/migration"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Manage the schema of the users database",
+}
+
+var upCmd = &cobra.Command{
+	Use:   "up [steps]",
+	Short: "Apply the next migrations, all of them by default",
+	Args:  cobra.RangeArgs(0, 1),
+	RunE:  upCmdBody,
+}
+
+var downCmd = &cobra.Command{
+	Use:   "down [steps]",
+	Short: "Revert the last migrations, all of them by default",
+	Args:  cobra.RangeArgs(0, 1),
+	RunE:  downCmdBody,
+}
+
+var gotoCmd = &cobra.Command{
+	Use:   "goto version",
+	Short: "Apply or revert migrations to reach the version",
+	Args:  cobra.ExactArgs(1),
+	RunE:  gotoCmdBody,
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Display the applied and pending migrations",
+	Args:  cobra.NoArgs,
+	RunE:  statusCmdBody,
+}
+
+func main() {
+	logger.Configure(logger.Configuration{
+		Service: "migrate",
+		Level:   logrus.InfoLevel,
+	})
+
+	migrateCmd.AddCommand(upCmd, downCmd, gotoCmd, statusCmd)
+	// The scripts running the migrations rely on the exit status.
+	if err := migrateCmd.Execute(); err != nil {
+		logger.Errorf("migrate command failed (err: %v)", err)
+		os.Exit(1)
+	}
+}
+
+func upCmdBody(cmd *cobra.Command, args []string) error {
+	steps, err := parseOptionalUint(args)
+	if err != nil {
+		return err
+	}
+
+	return withRunner(func(ctx context.Context, runner migration.Runner) error {
+		return runner.Up(ctx, steps)
+	})
+}
+
+func downCmdBody(cmd *cobra.Command, args []string) error {
+	steps, err := parseOptionalUint(args)
+	if err != nil {
+		return err
+	}
+
+	return withRunner(func(ctx context.Context, runner migration.Runner) error {
+		return runner.Down(ctx, steps)
+	})
+}
+
+func gotoCmdBody(cmd *cobra.Command, args []string) error {
+	version, err := parseOptionalUint(args)
+	if err != nil {
+		return err
+	}
+
+	return withRunner(func(ctx context.Context, runner migration.Runner) error {
+		return runner.Goto(ctx, version)
+	})
+}
+
+func statusCmdBody(cmd *cobra.Command, args []string) error {
+	return withRunner(func(ctx context.Context, runner migration.Runner) error {
+		status, err := runner.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		logger.Infof("version: %d (latest: %d)", status.Version, status.Latest)
+		logger.Infof("applied: %v", status.Applied)
+		logger.Infof("pending: %v", status.Pending)
+		return nil
+	})
+}
+
+func parseOptionalUint(args []string) (uint, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseUint(args[0], 10, 0)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", args[0])
+	}
+
+	return uint(value), nil
+}
+
+func withRunner(fn func(ctx context.Context, runner migration.Runner) error) error {
+	if err := loadConfiguration(); err != nil {
+		return err
+	}
+
+	ms, err := migration.Load(migrations.Files)
+	if err != nil {
+		return err
+	}
+
+	dbConf, err := createDbConfig()
+	if err != nil {
+		return err
+	}
+
+	database := db.NewPostgresDatabase(dbConf)
+	ctx := context.Background()
+	if err := database.Connect(ctx); err != nil {
+		return err
+	}
+	defer database.Disconnect(ctx)
+
+	return fn(ctx, migration.NewRunner(database, ms))
+}
+
+func loadConfiguration() error {
+	viper.SetConfigType("yaml")
+	viper.AddConfigPath("../../configs/users")
+
+	viper.SetConfigName("db-dev")
+	return viper.ReadInConfig()
+}
+
+func createDbConfig() (db.Config, error) {
+	dbConf := db.NewConfig()
+	dbConf.DbHost = viper.GetString("Database.Host")
+	dbConf.DbPort = viper.GetUint16("Database.Port")
+	dbConf.DbName = viper.GetString("Database.Name")
+	dbConf.DbUser = viper.GetString("Database.User")
+	dbConf.DbPassword = viper.GetString("Database.Password")
+	dbConf.DbPasswordFile = viper.GetString("Database.PasswordFile")
+	dbConf.DbSslMode = viper.GetString("Database.SslMode")
+	dbConf.DbSslRootCert = viper.GetString("Database.SslRootCert")
+	dbConf.DbSslCert = viper.GetString("Database.SslCert")
+	dbConf.DbSslKey = viper.GetString("Database.SslKey")
+	dbConf.DbApplicationName = viper.GetString("Database.ApplicationName")
+	dbConf.DbStatementTimeout = viper.GetDuration("Database.StatementTimeout")
+	dbConf.DbSearchPath = viper.GetString("Database.SearchPath")
+	dbConf.DbConnectionsPoolSize = viper.GetUint("Database.ConnectionsPoolSize")
+	dbConf.DbConnectionTimeout = viper.GetDuration("Database.ConnectionTimeout")
+
+	if url := viper.GetString("Database.Url"); len(url) > 0 {
+		var err error
+		if dbConf, err = dbConf.WithConnectionString(url); err != nil {
+			return dbConf, err
+		}
+	}
+
+	return dbConf.WithEnvironment()
+}
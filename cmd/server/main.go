@@ -9,9 +9,13 @@ import (
 	"syscall"
 
 	"github.com/KnoblauchPilze/go-game/cmd/server/routes"
+	"github.com/KnoblauchPilze/go-game/database/users/migrations"
 	"github.com/KnoblauchPilze/go-game/pkg/db"
+	"github.com/KnoblauchPilze/go-game/pkg/db/memory"
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
 	"github.com/KnoblauchPilze/go-game/pkg/logger"
 	"github.com/KnoblauchPilze/go-game/pkg/middleware"
+	"github.com/KnoblauchPilze/go-game/pkg/migration"
 	"github.com/KnoblauchPilze/go-game/pkg/users"
 	"github.com/go-chi/chi/v5"
 	cmiddleware "github.com/go-chi/chi/v5/middleware"
@@ -21,6 +25,18 @@ import (
 
 const defaultServerPort = 3000
 
+const postgresDatabaseType = "postgres"
+const memoryDatabaseType = "memory"
+
+const checkMigrationsMode = "check"
+const autoMigrationsMode = "auto"
+const ignoreMigrationsMode = "ignore"
+
+type replicaConfiguration struct {
+	Host string
+	Port uint16
+}
+
 func main() {
 	logger.Configure(logger.Configuration{
 		Service: "server",
@@ -33,7 +49,11 @@ func main() {
 	}
 
 	port := viper.GetUint16("Server.Port")
-	database := createDb()
+	database, err := createDb()
+	if err != nil {
+		logger.Errorf("failed to create the db (err: %v)", err)
+		return
+	}
 	qe := db.NewQueryExecutor(database)
 	repo := users.NewDbRepository(qe)
 	r := createServerRouter(repo)
@@ -44,6 +64,11 @@ func main() {
 	}
 	defer database.Disconnect(context.Background())
 
+	if err := migrateDb(context.Background(), database); err != nil {
+		logger.Errorf("failed to check the db schema (err: %v)", err)
+		return
+	}
+
 	logger.Infof("server pid: %d", os.Getpid())
 	logger.Infof("starting server on port %d...", port)
 	http.ListenAndServe(fmt.Sprintf(":%d", port), r)
@@ -57,6 +82,8 @@ func loadConfiguration() error {
 
 	// https://github.com/spf13/viper#establishing-defaults
 	viper.SetDefault("Server.Port", defaultServerPort)
+	viper.SetDefault("Database.Type", postgresDatabaseType)
+	viper.SetDefault("Database.Migrations", checkMigrationsMode)
 
 	viper.SetConfigName("server-dev")
 	if err := viper.ReadInConfig(); err != nil {
@@ -72,18 +99,127 @@ func loadConfiguration() error {
 	return nil
 }
 
-func createDb() db.Database {
+func createDb() (db.Database, error) {
 	dbConf := db.NewConfig()
 	dbConf.DbHost = viper.GetString("Database.Host")
 	dbConf.DbPort = viper.GetUint16("Database.Port")
 	dbConf.DbName = viper.GetString("Database.Name")
 	dbConf.DbUser = viper.GetString("Database.User")
 	dbConf.DbPassword = viper.GetString("Database.Password")
+	dbConf.DbPasswordFile = viper.GetString("Database.PasswordFile")
+	dbConf.DbSslMode = viper.GetString("Database.SslMode")
+	dbConf.DbSslRootCert = viper.GetString("Database.SslRootCert")
+	dbConf.DbSslCert = viper.GetString("Database.SslCert")
+	dbConf.DbSslKey = viper.GetString("Database.SslKey")
+	dbConf.DbApplicationName = viper.GetString("Database.ApplicationName")
+	dbConf.DbStatementTimeout = viper.GetDuration("Database.StatementTimeout")
+	dbConf.DbSearchPath = viper.GetString("Database.SearchPath")
 	dbConf.DbConnectionsPoolSize = viper.GetUint("Database.ConnectionsPoolSize")
 	dbConf.DbConnectionTimeout = viper.GetDuration("Database.ConnectionTimeout")
 	dbConf.DbQueryTimeout = viper.GetDuration("Database.QueryTimeout")
+	dbConf.DbHealthCheckInterval = viper.GetDuration("Database.HealthCheckInterval")
+	dbConf.DbReconnectMinBackoff = viper.GetDuration("Database.ReconnectMinBackoff")
+	dbConf.DbReconnectMaxBackoff = viper.GetDuration("Database.ReconnectMaxBackoff")
+	dbConf.DbCircuitBreakerThreshold = viper.GetUint("Database.CircuitBreakerThreshold")
+	dbConf.DbRetryAttempts = viper.GetUint("Database.RetryAttempts")
+	dbConf.DbRetryBackoff = viper.GetDuration("Database.RetryBackoff")
+	dbConf.DbStatementCacheSize = viper.GetUint("Database.StatementCacheSize")
+	if threshold := viper.GetDuration("Database.SlowQueryThreshold"); threshold > 0 {
+		dbConf.Hooks = append(dbConf.Hooks, db.NewSlowQueryHook(threshold))
+	}
+
+	var replicas []replicaConfiguration
+	if err := viper.UnmarshalKey("Database.Replicas", &replicas); err != nil {
+		return nil, err
+	}
+	for _, replica := range replicas {
+		dbConf.DbReplicas = append(dbConf.DbReplicas, db.ReplicaConfig{
+			DbHost: replica.Host,
+			DbPort: replica.Port,
+		})
+	}
+	dbConf.DbReplicaEjectionTime = viper.GetDuration("Database.ReplicaEjectionTime")
+	dbConf.DbExplainVerboseQueries = viper.GetBool("Database.ExplainVerboseQueries")
+	dbConf.DbExplainThreshold = viper.GetDuration("Database.ExplainThreshold")
+	dbConf.DbExplainAnalyze = viper.GetBool("Database.ExplainAnalyze")
+	dbConf.DbExplainAnalyzeWrites = viper.GetBool("Database.ExplainAnalyzeWrites")
+
+	dbConf, err := overrideDbConfig(dbConf)
+	if err != nil {
+		return nil, err
+	}
 
-	return db.NewPostgresDatabase(dbConf)
+	// The in-memory database is meant for local development: the data
+	// is lost when the server stops.
+	switch dbType := viper.GetString("Database.Type"); dbType {
+	case postgresDatabaseType:
+		if len(dbConf.DbReplicas) > 0 {
+			return db.NewRoutingDatabase(dbConf), nil
+		}
+		return db.NewPostgresDatabase(dbConf), nil
+	case memoryDatabaseType:
+		return newMemoryDatabase(dbConf)
+	default:
+		return nil, fmt.Errorf("unsupported database type %q", dbType)
+	}
+}
+
+// The tables are the ones the migrations create in postgres.
+func newMemoryDatabase(dbConf db.Config) (db.Database, error) {
+	ms, err := migration.Load(migrations.Files)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := migration.MemorySchema(ms)
+	if err != nil {
+		return nil, err
+	}
+
+	return memory.NewDatabase(dbConf, tables...), nil
+}
+
+// The connection string and then the environment take precedence over
+// the individual properties of the configuration file.
+func overrideDbConfig(dbConf db.Config) (db.Config, error) {
+	if url := viper.GetString("Database.Url"); len(url) > 0 {
+		var err error
+		if dbConf, err = dbConf.WithConnectionString(url); err != nil {
+			return dbConf, err
+		}
+	}
+
+	return dbConf.WithEnvironment()
+}
+
+// The in-memory database is created from the migrations directly.
+func migrateDb(ctx context.Context, database db.Database) error {
+	if viper.GetString("Database.Type") != postgresDatabaseType {
+		return nil
+	}
+
+	ms, err := migration.Load(migrations.Files)
+	if err != nil {
+		return err
+	}
+	runner := migration.NewRunner(database, ms)
+
+	switch mode := viper.GetString("Database.Migrations"); mode {
+	case checkMigrationsMode:
+		status, err := runner.Status(ctx)
+		if err != nil {
+			return err
+		}
+		if !status.UpToDate() {
+			return errors.WrapCode(errors.Newf("pending migrations %v", status.Pending), errors.ErrSchemaOutdated)
+		}
+		return nil
+	case autoMigrationsMode:
+		return runner.Up(ctx, 0)
+	case ignoreMigrationsMode:
+		return nil
+	default:
+		return fmt.Errorf("unsupported migrations mode %q", mode)
+	}
 }
 
 func createServerRouter(repo users.Repository) *chi.Mux {
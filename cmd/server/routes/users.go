@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/KnoblauchPilze/go-game/pkg/dtos"
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
 	"github.com/KnoblauchPilze/go-game/pkg/rest"
 	"github.com/KnoblauchPilze/go-game/pkg/users"
 	"github.com/go-chi/chi/v5"
@@ -46,13 +47,17 @@ func createUser(repo users.Repository) http.HandlerFunc {
 			return
 		}
 
-		id, err := repo.Create(r.Context(), dto.Convert())
+		user, err := repo.Create(r.Context(), dto.Convert())
+		if errors.IsErrorWithCode(err, errors.ErrUserAlreadyExists) {
+			rest.FailWithErrorAndCode(r.Context(), err, http.StatusConflict, w)
+			return
+		}
 		if err != nil {
 			rest.FailWithErrorAndCode(r.Context(), err, http.StatusBadRequest, w)
 			return
 		}
 
-		rest.WriteDetails(r.Context(), id, w)
+		rest.WriteDetails(r.Context(), user.Id, w)
 	}
 }
 
@@ -0,0 +1,9 @@
+package migrations
+
+import "embed"
+
+// The migrations of the users database, embedded so that the server
+// can check and upgrade its own schema.
+//
+//go:embed *.sql
+var Files embed.FS
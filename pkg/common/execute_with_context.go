@@ -28,36 +28,49 @@ func executeWithNoTimeout(process Process) error {
 	return process.WorkFunc()
 }
 
+// The process and the timeout race to settle its state: whoever
+// wins decides the outcome. A process which completes after the
+// timeout always runs its clean up, while one which completes
+// first always reports its own result.
+const (
+	processRunning int32 = iota
+	processDone
+	processAbandoned
+)
+
 func executeWithTimeout(process Process, ctx context.Context, timeout time.Duration) error {
 	// https://medium.com/geekculture/timeout-context-in-go-e88af0abd08d
 	decoratedCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
-	processDone := make(chan bool, 1)
+	result := make(chan error, 1)
 	start := time.Now()
-	var cancelled atomic.Bool
+	var state atomic.Int32
 
 	// https://go.dev/doc/articles/race_detector
-	var errP error
 	go func() {
-		errP = process.WorkFunc()
-		processDone <- true
-		if cancelled.Load() {
-			if process.CleanUpIfFailFunc != nil {
-				process.CleanUpIfFailFunc()
-			}
+		err := process.WorkFunc()
+		if state.CompareAndSwap(processRunning, processDone) {
+			result <- err
+			return
+		}
+
+		if process.CleanUpIfFailFunc != nil {
+			process.CleanUpIfFailFunc()
 		}
 	}()
 
-	var err error
 	select {
 	case <-decoratedCtx.Done():
-		err = decoratedCtx.Err()
-		cancelled.Store(true)
-		errorLog(ctx, "process didn't finish after %+v (err: %+v)", timeout, err)
-	case <-processDone:
-		err = errP
+		if state.CompareAndSwap(processRunning, processAbandoned) {
+			err := decoratedCtx.Err()
+			errorLog(ctx, "process didn't finish after %+v (err: %+v)", timeout, err)
+			return err
+		}
+
+		// The process completed while the timeout expired.
+		return <-result
+	case err := <-result:
 		traceLog(ctx, "executed process after %+v", time.Since(start))
+		return err
 	}
-
-	return err
 }
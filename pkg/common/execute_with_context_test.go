@@ -139,3 +139,49 @@ func newProcessWithSleepAndError() Process {
 		},
 	}
 }
+
+func TestExecuteWithTimeout_ExpectNoCleanUpOnSuccess(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := context.TODO()
+	timeout := 2 * defaultSleep
+
+	p := newProcessWithSleep()
+	var cleanUpCalled atomic.Int32
+	p.CleanUpIfFailFunc = func() {
+		cleanUpCalled.Add(1)
+	}
+	err := executeWithTimeout(p, ctx, timeout)
+	assert.Nil(err)
+	assert.Equal(int32(0), cleanUpCalled.Load())
+}
+
+func TestExecuteWithTimeout_ExpectCleanUpOnlyOnTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := context.TODO()
+
+	for id := 0; id < 50; id++ {
+		var cleanUpCalled atomic.Int32
+		done := make(chan struct{})
+		p := Process{
+			WorkFunc: func() error {
+				time.Sleep(time.Millisecond)
+				return nil
+			},
+			CleanUpIfFailFunc: func() {
+				cleanUpCalled.Add(1)
+				close(done)
+			},
+		}
+
+		err := executeWithTimeout(p, ctx, time.Millisecond)
+		if err == context.DeadlineExceeded {
+			<-done
+			assert.Equal(int32(1), cleanUpCalled.Load())
+		} else {
+			assert.Nil(err)
+			assert.Equal(int32(0), cleanUpCalled.Load())
+		}
+	}
+}
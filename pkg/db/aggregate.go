@@ -0,0 +1,66 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+type AggregateFunction int
+
+const (
+	Count AggregateFunction = iota
+	Sum
+	Avg
+	Min
+	Max
+)
+
+// https://www.postgresql.org/docs/current/functions-aggregate.html
+var aggregateFunctionsToSql = map[AggregateFunction]string{
+	Count: "COUNT",
+	Sum:   "SUM",
+	Avg:   "AVG",
+	Min:   "MIN",
+	Max:   "MAX",
+}
+
+const allColumns = "*"
+
+type Aggregate struct {
+	Function AggregateFunction
+	Column   string
+	Distinct bool
+	Alias    string
+}
+
+func (a Aggregate) valid() error {
+	if _, ok := aggregateFunctionsToSql[a.Function]; !ok {
+		return errors.NewCode(errors.ErrInvalidSqlAggregate)
+	}
+	if len(a.Column) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlColumn)
+	}
+	if a.Column == allColumns && (a.Function != Count || a.Distinct) {
+		return errors.NewCode(errors.ErrInvalidSqlAggregate)
+	}
+
+	return nil
+}
+
+func (a Aggregate) Expression() string {
+	column := a.Column
+	if a.Distinct {
+		column = "DISTINCT " + column
+	}
+
+	return fmt.Sprintf("%s(%s)", aggregateFunctionsToSql[a.Function], column)
+}
+
+func (a Aggregate) toSql() string {
+	if len(a.Alias) == 0 {
+		return a.Expression()
+	}
+
+	return fmt.Sprintf("%s AS %s", a.Expression(), a.Alias)
+}
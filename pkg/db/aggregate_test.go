@@ -0,0 +1,53 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregate_Valid(t *testing.T) {
+	assert := assert.New(t)
+
+	a := Aggregate{Function: AggregateFunction(12), Column: "column"}
+	assert.True(errors.IsErrorWithCode(a.valid(), errors.ErrInvalidSqlAggregate))
+
+	a = Aggregate{Function: Sum}
+	assert.True(errors.IsErrorWithCode(a.valid(), errors.ErrInvalidSqlColumn))
+
+	a = Aggregate{Function: Sum, Column: "*"}
+	assert.True(errors.IsErrorWithCode(a.valid(), errors.ErrInvalidSqlAggregate))
+
+	a = Aggregate{Function: Count, Column: "*", Distinct: true}
+	assert.True(errors.IsErrorWithCode(a.valid(), errors.ErrInvalidSqlAggregate))
+
+	a = Aggregate{Function: Count, Column: "*"}
+	assert.Nil(a.valid())
+
+	a = Aggregate{Function: Max, Column: "score", Alias: "best"}
+	assert.Nil(a.valid())
+}
+
+func TestAggregate_Expression(t *testing.T) {
+	assert := assert.New(t)
+
+	a := Aggregate{Function: Count, Column: "*"}
+	assert.Equal("COUNT(*)", a.Expression())
+
+	a = Aggregate{Function: Count, Column: "user_id", Distinct: true}
+	assert.Equal("COUNT(DISTINCT user_id)", a.Expression())
+
+	a = Aggregate{Function: Avg, Column: "score", Alias: "average"}
+	assert.Equal("AVG(score)", a.Expression())
+}
+
+func TestAggregate_ToSql(t *testing.T) {
+	assert := assert.New(t)
+
+	a := Aggregate{Function: Min, Column: "score"}
+	assert.Equal("MIN(score)", a.toSql())
+
+	a.Alias = "worst"
+	assert.Equal("MIN(score) AS worst", a.toSql())
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+type BetweenFilterBuilder interface {
+	FilterBuilder
+
+	SetKey(key string) error
+	SetBounds(low interface{}, high interface{}) error
+	SetNegated(negated bool)
+}
+
+type betweenFilterBuilder struct {
+	key     string
+	low     interface{}
+	high    interface{}
+	negated bool
+}
+
+func NewBetweenFilterBuilder() BetweenFilterBuilder {
+	return &betweenFilterBuilder{}
+}
+
+func (b *betweenFilterBuilder) SetKey(key string) error {
+	if len(key) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlComparisonKey)
+	}
+
+	b.key = key
+	return nil
+}
+
+func (b *betweenFilterBuilder) SetBounds(low interface{}, high interface{}) error {
+	if low == nil || high == nil {
+		return errors.NewCode(errors.ErrInvalidSqlComparisonValue)
+	}
+
+	b.low = low
+	b.high = high
+	return nil
+}
+
+func (b *betweenFilterBuilder) SetNegated(negated bool) {
+	b.negated = negated
+}
+
+func (b *betweenFilterBuilder) Build() (Filter, error) {
+	if len(b.key) == 0 {
+		return filterImpl{}, errors.WrapCode(errors.NewCode(errors.ErrInvalidSqlComparisonKey), errors.ErrSqlTranslationFailed)
+	}
+	if b.low == nil || b.high == nil {
+		return filterImpl{}, errors.WrapCode(errors.NewCode(errors.ErrNoValuesInSqlComparison), errors.ErrSqlTranslationFailed)
+	}
+
+	var args sqlArgs
+	low, err := args.add(b.low)
+	if err != nil {
+		return filterImpl{}, errors.WrapCode(err, errors.ErrSqlTranslationFailed)
+	}
+	high, err := args.add(b.high)
+	if err != nil {
+		return filterImpl{}, errors.WrapCode(err, errors.ErrSqlTranslationFailed)
+	}
+
+	// https://www.postgresql.org/docs/current/functions-comparison.html
+	operator := "BETWEEN"
+	if b.negated {
+		operator = "NOT BETWEEN"
+	}
+
+	filter := filterImpl{
+		sqlCode: fmt.Sprintf("%s %s %s AND %s", b.key, operator, low, high),
+		args:    args.values,
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBetweenFilterBuilder_SetKey(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBetweenFilterBuilder()
+
+	err := b.SetKey("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlComparisonKey))
+
+	err = b.SetKey("key")
+	assert.Nil(err)
+}
+
+func TestBetweenFilterBuilder_SetBounds(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBetweenFilterBuilder()
+
+	err := b.SetBounds(nil, 2)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlComparisonValue))
+
+	err = b.SetBounds(1, nil)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlComparisonValue))
+
+	err = b.SetBounds(1, 2)
+	assert.Nil(err)
+}
+
+func TestBetweenFilterBuilder_Build_NoKey(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBetweenFilterBuilder()
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrInvalidSqlComparisonKey))
+}
+
+func TestBetweenFilterBuilder_Build_NoBounds(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBetweenFilterBuilder()
+	b.SetKey("key")
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrNoValuesInSqlComparison))
+}
+
+func TestBetweenFilterBuilder_Build_ArgWithError(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBetweenFilterBuilder()
+	b.SetKey("key")
+	b.SetBounds(1, mockUnmarshalable{})
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(strings.Contains(cause.Error(), errDefault.Error()))
+}
+
+func TestBetweenFilterBuilder_Build(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBetweenFilterBuilder()
+	b.SetKey("key")
+	b.SetBounds(1, 2)
+
+	filter, err := b.Build()
+	assert.Nil(err)
+	assert.True(filter.Valid())
+	assert.Equal("key BETWEEN $1 AND $2", filter.ToSql())
+	assert.Equal([]interface{}{1, 2}, filter.Args())
+}
+
+func TestBetweenFilterBuilder_Build_Negated(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBetweenFilterBuilder()
+	b.SetKey("key")
+	b.SetBounds(1, 2)
+	b.SetNegated(true)
+
+	filter, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("key NOT BETWEEN $1 AND $2", filter.ToSql())
+}
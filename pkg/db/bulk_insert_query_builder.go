@@ -0,0 +1,156 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+// https://www.postgresql.org/docs/current/protocol-message-formats.html
+// The Bind message uses a 16-bit integer to count the parameters.
+const maxArgsPerSqlQuery = 65535
+
+type BulkInsertQueryBuilder interface {
+	QueryBuilder
+	CopyFromBuilder
+
+	SetTable(table string) error
+	SetColumns(columns ...string) error
+	AddRow(values ...interface{}) error
+	AddReturning(column string) error
+	SetVerbose(verbose bool)
+}
+
+type bulkInsertQueryBuilder struct {
+	table     string
+	columns   []string
+	rows      [][]interface{}
+	returning returningClause
+	verbose   bool
+}
+
+func NewBulkInsertQueryBuilder() BulkInsertQueryBuilder {
+	return &bulkInsertQueryBuilder{}
+}
+
+func (b *bulkInsertQueryBuilder) SetTable(table string) error {
+	if len(table) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlTable)
+	}
+
+	b.table = table
+	return nil
+}
+
+func (b *bulkInsertQueryBuilder) SetColumns(columns ...string) error {
+	keys := make(map[string]bool)
+	for _, column := range columns {
+		if len(column) == 0 {
+			return errors.NewCode(errors.ErrInvalidSqlColumn)
+		}
+		if _, ok := keys[column]; ok {
+			return errors.NewCode(errors.ErrDuplicatedSqlColumn)
+		}
+
+		keys[column] = true
+	}
+
+	b.columns = columns
+	b.rows = nil
+	return nil
+}
+
+func (b *bulkInsertQueryBuilder) AddRow(values ...interface{}) error {
+	if len(b.columns) == 0 {
+		return errors.NewCode(errors.ErrNoColumnInSqlInsertQuery)
+	}
+	if len(values) != len(b.columns) {
+		return errors.NewCode(errors.ErrInvalidSqlBulkRow)
+	}
+
+	b.rows = append(b.rows, values)
+	return nil
+}
+
+func (b *bulkInsertQueryBuilder) AddReturning(column string) error {
+	return b.returning.add(column)
+}
+
+func (b *bulkInsertQueryBuilder) SetVerbose(verbose bool) {
+	b.verbose = verbose
+}
+
+func (b *bulkInsertQueryBuilder) Build() (Query, error) {
+	if err := b.validate(); err != nil {
+		return queryImpl{}, errors.WrapCode(err, errors.ErrSqlTranslationFailed)
+	}
+	if len(b.rows)*len(b.columns) > maxArgsPerSqlQuery {
+		return queryImpl{}, errors.WrapCode(errors.NewCode(errors.ErrTooManyArgsInSqlQuery), errors.ErrSqlTranslationFailed)
+	}
+
+	var args sqlArgs
+	values := make([]string, 0, len(b.rows))
+	for _, row := range b.rows {
+		placeholders, err := args.addAll(row)
+		if err != nil {
+			return queryImpl{}, errors.WrapCode(err, errors.ErrSqlTranslationFailed)
+		}
+
+		values = append(values, fmt.Sprintf("(%s)", placeholders))
+	}
+
+	// https://www.postgresql.org/docs/current/dml-insert.html
+	sqlQuery := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", b.table, strings.Join(b.columns, ", "), strings.Join(values, ", "))
+	sqlQuery += b.returning.toSql()
+
+	query := queryImpl{
+		sqlCode: sqlQuery,
+		args:    args.values,
+		verbose: b.verbose,
+	}
+
+	return query, nil
+}
+
+func (b *bulkInsertQueryBuilder) BuildCopyFrom() (CopyFrom, error) {
+	if err := b.validate(); err != nil {
+		return copyFromImpl{}, errors.WrapCode(err, errors.ErrSqlTranslationFailed)
+	}
+	if len(b.returning.columns) > 0 {
+		return copyFromImpl{}, errors.WrapCode(errors.NewCode(errors.ErrReturningNotSupportedInSqlCopy), errors.ErrSqlTranslationFailed)
+	}
+
+	rows := make([][]interface{}, 0, len(b.rows))
+	for _, row := range b.rows {
+		values := make([]interface{}, 0, len(row))
+		for _, value := range row {
+			values = append(values, argToCopyArg(value))
+		}
+
+		rows = append(rows, values)
+	}
+
+	copy := copyFromImpl{
+		table:   b.table,
+		columns: b.columns,
+		rows:    rows,
+		verbose: b.verbose,
+	}
+
+	return copy, nil
+}
+
+func (b *bulkInsertQueryBuilder) validate() error {
+	if len(b.table) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlTable)
+	}
+	if len(b.columns) == 0 {
+		return errors.NewCode(errors.ErrNoColumnInSqlInsertQuery)
+	}
+	if len(b.rows) == 0 {
+		return errors.NewCode(errors.ErrNoRowInSqlBulkInsertQuery)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkInsertQueryBuilder_SetTable(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBulkInsertQueryBuilder()
+
+	err := b.SetTable("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlTable))
+
+	err = b.SetTable("haha")
+	assert.Nil(err)
+}
+
+func TestBulkInsertQueryBuilder_SetColumns(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBulkInsertQueryBuilder()
+
+	err := b.SetColumns("a", "")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlColumn))
+
+	err = b.SetColumns("a", "a")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDuplicatedSqlColumn))
+
+	err = b.SetColumns("a", "b")
+	assert.Nil(err)
+}
+
+func TestBulkInsertQueryBuilder_AddRow(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBulkInsertQueryBuilder()
+
+	err := b.AddRow(1, 2)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrNoColumnInSqlInsertQuery))
+
+	b.SetColumns("a", "b")
+	err = b.AddRow(1)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlBulkRow))
+
+	err = b.AddRow(1, 2)
+	assert.Nil(err)
+}
+
+func TestBulkInsertQueryBuilder_Build_NoTable(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBulkInsertQueryBuilder()
+	b.SetColumns("a")
+	b.AddRow(1)
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrInvalidSqlTable))
+}
+
+func TestBulkInsertQueryBuilder_Build_NoColumn(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBulkInsertQueryBuilder()
+	b.SetTable("table")
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrNoColumnInSqlInsertQuery))
+}
+
+func TestBulkInsertQueryBuilder_Build_NoRow(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBulkInsertQueryBuilder()
+	b.SetTable("table")
+	b.SetColumns("a")
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrNoRowInSqlBulkInsertQuery))
+}
+
+func TestBulkInsertQueryBuilder_Build(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBulkInsertQueryBuilder()
+	b.SetTable("table")
+	b.SetColumns("a", "b")
+	b.AddRow("haha", 1)
+	b.AddRow("hihi", 2)
+	b.AddRow("hoho", 3)
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.True(query.Valid())
+	assert.Equal("INSERT INTO table (a, b) VALUES ($1, $2), ($3, $4), ($5, $6)", query.ToSql())
+	assert.Equal([]interface{}{"haha", 1, "hihi", 2, "hoho", 3}, query.Args())
+}
+
+func TestBulkInsertQueryBuilder_Build_WithReturning(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBulkInsertQueryBuilder()
+	b.SetTable("table")
+	b.SetColumns("a")
+	b.AddRow("haha")
+	b.AddRow("hihi")
+	b.AddReturning("id")
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("INSERT INTO table (a) VALUES ($1), ($2) RETURNING id", query.ToSql())
+}
+
+func TestBulkInsertQueryBuilder_Build_TooManyArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBulkInsertQueryBuilder()
+	b.SetTable("table")
+	b.SetColumns("a", "b")
+	for id := 0; id < maxArgsPerSqlQuery/2+1; id++ {
+		b.AddRow(id, id)
+	}
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrTooManyArgsInSqlQuery))
+}
+
+func TestBulkInsertQueryBuilder_Build_ArgWithError(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBulkInsertQueryBuilder()
+	b.SetTable("table")
+	b.SetColumns("a")
+	b.AddRow(mockUnmarshalable{})
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(strings.Contains(cause.Error(), errDefault.Error()))
+}
+
+func TestBulkInsertQueryBuilder_BuildCopyFrom(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBulkInsertQueryBuilder()
+	b.SetTable("table")
+	b.SetColumns("a", "b")
+	b.AddRow("haha", mockConvertible{value: 1})
+	b.AddRow("hihi", mockConvertible{value: 2})
+	b.SetVerbose(true)
+
+	copy, err := b.BuildCopyFrom()
+	assert.Nil(err)
+	assert.True(copy.Valid())
+	assert.Equal("table", copy.Table())
+	assert.Equal([]string{"a", "b"}, copy.Columns())
+	assert.Equal([][]interface{}{{"haha", 1}, {"hihi", 2}}, copy.Rows())
+	assert.True(copy.Verbose())
+}
+
+func TestBulkInsertQueryBuilder_BuildCopyFrom_NoRow(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBulkInsertQueryBuilder()
+	b.SetTable("table")
+	b.SetColumns("a")
+
+	_, err := b.BuildCopyFrom()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrNoRowInSqlBulkInsertQuery))
+}
+
+func TestBulkInsertQueryBuilder_BuildCopyFrom_WithReturning(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBulkInsertQueryBuilder()
+	b.SetTable("table")
+	b.SetColumns("a")
+	b.AddRow("haha")
+	b.AddReturning("id")
+
+	_, err := b.BuildCopyFrom()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrReturningNotSupportedInSqlCopy))
+}
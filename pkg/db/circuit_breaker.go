@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+const defaultCircuitBreakerThreshold = 5
+
+// The circuit is opened when the database is considered down: either
+// the health check failed or too many consecutive queries could not
+// reach it. While open the queries fail fast and the connection is
+// reestablished in the background, which closes the circuit again.
+type circuitBreaker struct {
+	threshold uint32
+	failures  atomic.Uint32
+	open      atomic.Bool
+	tripped   chan struct{}
+}
+
+func newCircuitBreaker(threshold uint) *circuitBreaker {
+	if threshold == 0 {
+		threshold = defaultCircuitBreakerThreshold
+	}
+
+	return &circuitBreaker{
+		threshold: uint32(threshold),
+		tripped:   make(chan struct{}, 1),
+	}
+}
+
+func (b *circuitBreaker) isOpen() bool {
+	return b.open.Load()
+}
+
+// Any answer of the server, even an error, shows that the database is
+// reachable. The queries cancelled by the caller don't tell anything.
+func (b *circuitBreaker) report(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	if !isConnectionError(ctx, err) {
+		b.failures.Store(0)
+		return
+	}
+
+	if b.failures.Add(1) >= b.threshold {
+		b.trip()
+	}
+}
+
+func (b *circuitBreaker) trip() {
+	if b.open.Swap(true) {
+		return
+	}
+
+	select {
+	case b.tripped <- struct{}{}:
+	default:
+	}
+}
+
+func (b *circuitBreaker) reset() {
+	b.failures.Store(0)
+	b.open.Store(false)
+}
+
+// Reports the queries returning rows once the rows are closed: the
+// errors might only come while iterating over them.
+type circuitBreakerHook struct {
+	breaker *circuitBreaker
+}
+
+func (h circuitBreakerHook) BeforeQuery(ctx context.Context, event QueryEvent) context.Context {
+	return ctx
+}
+
+func (h circuitBreakerHook) AfterQuery(ctx context.Context, event QueryEvent) {
+	h.breaker.report(ctx, event.Err)
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker_DefaultThreshold(t *testing.T) {
+	assert := assert.New(t)
+
+	b := newCircuitBreaker(0)
+	assert.Equal(uint32(defaultCircuitBreakerThreshold), b.threshold)
+	assert.False(b.isOpen())
+}
+
+func TestCircuitBreaker_Report(t *testing.T) {
+	assert := assert.New(t)
+
+	b := newCircuitBreaker(2)
+	b.report(context.Background(), io.EOF)
+	assert.False(b.isOpen())
+
+	b.report(context.Background(), nil)
+	b.report(context.Background(), io.EOF)
+	assert.False(b.isOpen())
+
+	b.report(context.Background(), io.EOF)
+	assert.True(b.isOpen())
+	assert.Equal(1, len(b.tripped))
+
+	b.report(context.Background(), io.EOF)
+	assert.Equal(1, len(b.tripped))
+
+	b.reset()
+	assert.False(b.isOpen())
+	assert.Equal(uint32(0), b.failures.Load())
+}
+
+func TestCircuitBreaker_Report_CancelledContext(t *testing.T) {
+	assert := assert.New(t)
+
+	b := newCircuitBreaker(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	b.report(ctx, io.EOF)
+	assert.False(b.isOpen())
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+type ColumnComparisonFilterBuilder interface {
+	FilterBuilder
+
+	SetColumns(left string, right string) error
+	SetOperator(op ComparisonOperator) error
+}
+
+type columnComparisonFilterBuilder struct {
+	left     string
+	right    string
+	operator ComparisonOperator
+}
+
+func NewColumnComparisonFilterBuilder() ColumnComparisonFilterBuilder {
+	return &columnComparisonFilterBuilder{
+		operator: Equal,
+	}
+}
+
+func (b *columnComparisonFilterBuilder) SetColumns(left string, right string) error {
+	if len(left) == 0 || len(right) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlComparisonKey)
+	}
+
+	b.left = left
+	b.right = right
+	return nil
+}
+
+func (b *columnComparisonFilterBuilder) SetOperator(op ComparisonOperator) error {
+	if _, ok := comparisonOperatorsToSql[op]; !ok {
+		return errors.NewCode(errors.ErrInvalidSqlComparisonOperator)
+	}
+
+	b.operator = op
+	return nil
+}
+
+func (b *columnComparisonFilterBuilder) Build() (Filter, error) {
+	if len(b.left) == 0 || len(b.right) == 0 {
+		return filterImpl{}, errors.WrapCode(errors.NewCode(errors.ErrInvalidSqlComparisonKey), errors.ErrSqlTranslationFailed)
+	}
+
+	filter := filterImpl{
+		sqlCode: fmt.Sprintf("%s %s %s", b.left, comparisonOperatorsToSql[b.operator], b.right),
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnComparisonFilterBuilder_SetColumns(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewColumnComparisonFilterBuilder()
+
+	err := b.SetColumns("", "right")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlComparisonKey))
+
+	err = b.SetColumns("left", "")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlComparisonKey))
+
+	err = b.SetColumns("left", "right")
+	assert.Nil(err)
+}
+
+func TestColumnComparisonFilterBuilder_SetOperator(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewColumnComparisonFilterBuilder()
+
+	err := b.SetOperator(ComparisonOperator(-1))
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlComparisonOperator))
+
+	err = b.SetOperator(LessThan)
+	assert.Nil(err)
+}
+
+func TestColumnComparisonFilterBuilder_Build_NoColumns(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewColumnComparisonFilterBuilder()
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrInvalidSqlComparisonKey))
+}
+
+func TestColumnComparisonFilterBuilder_Build(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewColumnComparisonFilterBuilder()
+	b.SetColumns("u.id", "p.user_id")
+
+	filter, err := b.Build()
+	assert.Nil(err)
+	assert.True(filter.Valid())
+	assert.Equal("u.id = p.user_id", filter.ToSql())
+	assert.Nil(filter.Args())
+
+	b.SetOperator(NotEqual)
+	filter, err = b.Build()
+	assert.Nil(err)
+	assert.Equal("u.id <> p.user_id", filter.ToSql())
+}
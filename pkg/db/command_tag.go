@@ -1,6 +1,7 @@
 package db
 
 import (
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -8,29 +9,55 @@ import (
 	"github.com/jackc/pgx"
 )
 
-var deleteCommandTag = "DELETE"
+// https://www.postgresql.org/docs/current/protocol-message-formats.html#PROTOCOL-MESSAGE-FORMATS-COMMANDCOMPLETE
 var insertCommandTag = "INSERT"
+var deleteCommandTag = "DELETE"
+var updateCommandTag = "UPDATE"
+var selectCommandTag = "SELECT"
+var mergeCommandTag = "MERGE"
+var moveCommandTag = "MOVE"
+var fetchCommandTag = "FETCH"
+var copyCommandTag = "COPY"
+
+var rowsCountCommandTags = map[string]bool{
+	deleteCommandTag: true,
+	updateCommandTag: true,
+	selectCommandTag: true,
+	mergeCommandTag:  true,
+	moveCommandTag:   true,
+	fetchCommandTag:  true,
+	copyCommandTag:   true,
+}
+
+// Utility commands (e.g. `CREATE TABLE` or `BEGIN`) only report the
+// name of the command without any rows count.
+var utilityCommandTagRegex = regexp.MustCompile(`^[A-Z]+( [A-Z]+)*$`)
 
 func extractAffectedRowsFromCommandTag(tag pgx.CommandTag) (int, error) {
 	pieces := strings.Split(string(tag), " ")
-	if len(pieces) != 2 && len(pieces) != 3 {
-		return 0, errors.NewCode(errors.ErrInvalidSqlCommandTag)
+
+	if pieces[0] == insertCommandTag {
+		if len(pieces) != 3 {
+			return 0, errors.NewCode(errors.ErrInvalidSqlCommandTag)
+		}
+		return extractRows(pieces[2])
 	}
 
-	switch pieces[0] {
-	case deleteCommandTag:
+	if _, ok := rowsCountCommandTags[pieces[0]]; ok {
 		if len(pieces) != 2 {
 			return 0, errors.NewCode(errors.ErrInvalidSqlCommandTag)
 		}
 		return extractRows(pieces[1])
-	case insertCommandTag:
-		if len(pieces) != 3 {
-			return 0, errors.NewCode(errors.ErrInvalidSqlCommandTag)
-		}
-		return extractRows(pieces[2])
-	default:
-		return 0, errors.NewCode(errors.ErrUnknownSqlCommandTag)
 	}
+
+	if utilityCommandTagRegex.MatchString(string(tag)) {
+		return 0, nil
+	}
+
+	if len(pieces) != 2 && len(pieces) != 3 {
+		return 0, errors.NewCode(errors.ErrInvalidSqlCommandTag)
+	}
+	return 0, errors.NewCode(errors.ErrUnknownSqlCommandTag)
 }
 
 func extractRows(rows string) (int, error) {
@@ -72,3 +72,59 @@ func TestExtractAffectedRowsFromCommandTag_Deleted(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal(24, n)
 }
+
+func TestExtractAffectedRowsFromCommandTag_Copy(t *testing.T) {
+	assert := assert.New(t)
+
+	tag := pgx.CommandTag("COPY 0 12")
+	_, err := extractAffectedRowsFromCommandTag(tag)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlCommandTag))
+
+	tag = pgx.CommandTag("COPY 1200")
+	n, err := extractAffectedRowsFromCommandTag(tag)
+	assert.Nil(err)
+	assert.Equal(1200, n)
+}
+
+func TestExtractAffectedRowsFromCommandTag_RowsCount(t *testing.T) {
+	assert := assert.New(t)
+
+	tags := map[string]int{
+		"UPDATE 12": 12,
+		"SELECT 0":  0,
+		"MERGE 3":   3,
+		"MOVE 5":    5,
+		"FETCH 7":   7,
+	}
+
+	for tag, expected := range tags {
+		n, err := extractAffectedRowsFromCommandTag(pgx.CommandTag(tag))
+		assert.Nil(err, tag)
+		assert.Equal(expected, n, tag)
+	}
+
+	tag := pgx.CommandTag("UPDATE")
+	_, err := extractAffectedRowsFromCommandTag(tag)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlCommandTag))
+
+	tag = pgx.CommandTag("SELECT not-a-number")
+	_, err = extractAffectedRowsFromCommandTag(tag)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlCommandTag))
+}
+
+func TestExtractAffectedRowsFromCommandTag_Utility(t *testing.T) {
+	assert := assert.New(t)
+
+	tags := []string{
+		"BEGIN",
+		"CREATE TABLE",
+		"ALTER DEFAULT PRIVILEGES",
+		"CREATE TEXT SEARCH CONFIGURATION",
+	}
+
+	for _, tag := range tags {
+		n, err := extractAffectedRowsFromCommandTag(pgx.CommandTag(tag))
+		assert.Nil(err, tag)
+		assert.Equal(0, n, tag)
+	}
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+type ComparisonOperator int
+
+const (
+	Equal ComparisonOperator = iota
+	NotEqual
+	LessThan
+	LessThanOrEqual
+	GreaterThan
+	GreaterThanOrEqual
+)
+
+// https://www.postgresql.org/docs/current/functions-comparison.html
+var comparisonOperatorsToSql = map[ComparisonOperator]string{
+	Equal:              "=",
+	NotEqual:           "<>",
+	LessThan:           "<",
+	LessThanOrEqual:    "<=",
+	GreaterThan:        ">",
+	GreaterThanOrEqual: ">=",
+}
+
+type ComparisonFilterBuilder interface {
+	FilterBuilder
+
+	SetKey(key string) error
+	SetOperator(op ComparisonOperator) error
+	SetValue(value interface{}) error
+}
+
+type comparisonFilterBuilder struct {
+	key      string
+	operator ComparisonOperator
+	value    interface{}
+}
+
+func NewComparisonFilterBuilder() ComparisonFilterBuilder {
+	return &comparisonFilterBuilder{
+		operator: Equal,
+	}
+}
+
+func (b *comparisonFilterBuilder) SetKey(key string) error {
+	if len(key) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlComparisonKey)
+	}
+
+	b.key = key
+	return nil
+}
+
+func (b *comparisonFilterBuilder) SetOperator(op ComparisonOperator) error {
+	if _, ok := comparisonOperatorsToSql[op]; !ok {
+		return errors.NewCode(errors.ErrInvalidSqlComparisonOperator)
+	}
+
+	b.operator = op
+	return nil
+}
+
+func (b *comparisonFilterBuilder) SetValue(value interface{}) error {
+	if value == nil {
+		return errors.NewCode(errors.ErrInvalidSqlComparisonValue)
+	}
+
+	b.value = value
+	return nil
+}
+
+func (b *comparisonFilterBuilder) Build() (Filter, error) {
+	if len(b.key) == 0 {
+		return filterImpl{}, errors.WrapCode(errors.NewCode(errors.ErrInvalidSqlComparisonKey), errors.ErrSqlTranslationFailed)
+	}
+	if b.value == nil {
+		return filterImpl{}, errors.WrapCode(errors.NewCode(errors.ErrNoValuesInSqlComparison), errors.ErrSqlTranslationFailed)
+	}
+
+	var args sqlArgs
+	placeholder, err := args.add(b.value)
+	if err != nil {
+		return filterImpl{}, errors.WrapCode(err, errors.ErrSqlTranslationFailed)
+	}
+
+	sqlFilter := fmt.Sprintf("%s %s %s", b.key, comparisonOperatorsToSql[b.operator], placeholder)
+
+	filter := filterImpl{
+		sqlCode: sqlFilter,
+		args:    args.values,
+	}
+
+	return filter, nil
+}
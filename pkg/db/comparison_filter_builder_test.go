@@ -0,0 +1,120 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComparisonFilterBuilder_SetKey(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewComparisonFilterBuilder()
+
+	err := b.SetKey("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlComparisonKey))
+
+	err = b.SetKey("key")
+	assert.Nil(err)
+}
+
+func TestComparisonFilterBuilder_SetOperator(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewComparisonFilterBuilder()
+
+	err := b.SetOperator(ComparisonOperator(-1))
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlComparisonOperator))
+
+	err = b.SetOperator(GreaterThan)
+	assert.Nil(err)
+}
+
+func TestComparisonFilterBuilder_SetValue(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewComparisonFilterBuilder()
+
+	err := b.SetValue(nil)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlComparisonValue))
+
+	err = b.SetValue("value")
+	assert.Nil(err)
+}
+
+func TestComparisonFilterBuilder_Build_NoKey(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewComparisonFilterBuilder()
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrInvalidSqlComparisonKey))
+}
+
+func TestComparisonFilterBuilder_Build_NoValue(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewComparisonFilterBuilder()
+	b.SetKey("key")
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrNoValuesInSqlComparison))
+}
+
+func TestComparisonFilterBuilder_Build_ArgWithError(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewComparisonFilterBuilder()
+	b.SetKey("key")
+	b.SetValue(mockUnmarshalable{})
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(strings.Contains(cause.Error(), errDefault.Error()))
+}
+
+func TestComparisonFilterBuilder_Build(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewComparisonFilterBuilder()
+	b.SetKey("key")
+	b.SetValue("value")
+
+	filter, err := b.Build()
+	assert.Nil(err)
+	assert.True(filter.Valid())
+	assert.Equal("key = $1", filter.ToSql())
+	assert.Equal([]interface{}{"value"}, filter.Args())
+}
+
+func TestComparisonFilterBuilder_Build_Operators(t *testing.T) {
+	assert := assert.New(t)
+
+	expected := map[ComparisonOperator]string{
+		Equal:              "key = $1",
+		NotEqual:           "key <> $1",
+		LessThan:           "key < $1",
+		LessThanOrEqual:    "key <= $1",
+		GreaterThan:        "key > $1",
+		GreaterThanOrEqual: "key >= $1",
+	}
+
+	for op, sql := range expected {
+		b := NewComparisonFilterBuilder()
+		b.SetKey("key")
+		b.SetOperator(op)
+		b.SetValue(32)
+
+		filter, err := b.Build()
+		assert.Nil(err)
+		assert.Equal(sql, filter.ToSql())
+		assert.Equal([]interface{}{32}, filter.Args())
+	}
+}
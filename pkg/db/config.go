@@ -2,28 +2,86 @@ package db
 
 import (
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
 	"github.com/jackc/pgx"
 )
 
-type dbCreationFunc func(config pgx.ConnPoolConfig) (pgxDbFacade, error)
+type dbCreationFunc func(config pgx.ConnPoolConfig) (DriverPool, error)
+type dbListenerFunc func(config pgx.ConnConfig) (DriverListener, error)
+
+// Read replicas share the credentials and the settings of the primary.
+type ReplicaConfig struct {
+	DbHost string
+	DbPort uint16
+}
 
 type Config struct {
-	DbHost                string
-	DbPort                uint16
-	DbName                string
-	DbUser                string
-	DbPassword            string
+	DbHost     string
+	DbPort     uint16
+	DbName     string
+	DbUser     string
+	DbPassword string
+	// Read on each connection instead of using the password so that it
+	// is not kept in the configuration files, e.g. a docker secret.
+	DbPasswordFile string
+	// https://www.postgresql.org/docs/current/libpq-ssl.html#LIBPQ-SSL-PROTECTION
+	// TLS is disabled when no mode is set. The root certificate is the
+	// one of the authority signing the server's, the system ones are
+	// used when it is not set.
+	DbSslMode         string
+	DbSslRootCert     string
+	DbSslCert         string
+	DbSslKey          string
+	DbApplicationName string
+	// Enforced by the server for each statement, unlike the query
+	// timeout which only applies to the queries of the application.
+	DbStatementTimeout time.Duration
+	// Comma separated list of schemas, as expected by postgres.
+	DbSearchPath string
+	// The pool size defaults to the one of pgx, 5, when 0.
 	DbConnectionsPoolSize uint
 	DbConnectionTimeout   time.Duration
 	DbQueryTimeout        time.Duration
-	creationFunc          dbCreationFunc
+	// Maximum number of statements prepared per connection, 0 disables the
+	// cache.
+	DbStatementCacheSize uint
+	// The health check and the reconnection run in the background: the
+	// health check is disabled when the interval is 0.
+	DbHealthCheckInterval     time.Duration
+	DbReconnectMinBackoff     time.Duration
+	DbReconnectMaxBackoff     time.Duration
+	DbCircuitBreakerThreshold uint
+	// Only the queries marked as idempotent are retried.
+	DbRetryAttempts       uint
+	DbRetryBackoff        time.Duration
+	DbReplicas            []ReplicaConfig
+	DbReplicaEjectionTime time.Duration
+	// EXPLAIN is run in the background for the verbose queries and the
+	// ones slower than the threshold, 0 disables the latter. The queries
+	// of the transactions are not explained. ANALYZE executes the query
+	// again in a transaction which is rolled back: the writes are only
+	// analyzed when allowed. The plans are logged when there is no
+	// handler.
+	DbExplainVerboseQueries bool
+	DbExplainThreshold      time.Duration
+	DbExplainAnalyze        bool
+	DbExplainAnalyzeWrites  bool
+	DbExplainHandler        QueryPlanHandler
+	Hooks                   []QueryHook
+	creationFunc            dbCreationFunc
+	listenerFunc            dbListenerFunc
 }
 
 func NewConfig() Config {
 	conf := Config{
+		Hooks:        []QueryHook{NewVerboseHook()},
 		creationFunc: newPgxDbFacadeImpl,
+		listenerFunc: newPgxListener,
 	}
 
 	return conf
@@ -39,16 +97,93 @@ func (c Config) Valid() bool {
 	if len(c.DbUser) == 0 {
 		return false
 	}
-	if len(c.DbPassword) == 0 {
+	if len(c.DbPassword) == 0 && len(c.DbPasswordFile) == 0 {
+		return false
+	}
+	if !validSslMode(c.DbSslMode) {
 		return false
 	}
-	if c.DbConnectionsPoolSize < 2 {
+	if (len(c.DbSslCert) == 0) != (len(c.DbSslKey) == 0) {
 		return false
 	}
 
+	for _, replica := range c.DbReplicas {
+		if len(replica.DbHost) == 0 {
+			return false
+		}
+	}
+
 	return true
 }
 
+// The password is never part of the output.
 func (c Config) String() string {
-	return fmt.Sprintf("%v %v@%v:%v", c.DbName, c.DbUser, c.DbHost, c.DbPort)
+	out := fmt.Sprintf("%v %v@%v:%v", c.DbName, c.DbUser, c.DbHost, c.DbPort)
+	if len(c.DbSslMode) > 0 {
+		out += fmt.Sprintf(" (sslmode: %v)", c.DbSslMode)
+	}
+
+	return out
+}
+
+func (c Config) replica(replica ReplicaConfig) Config {
+	out := c
+	out.DbHost = replica.DbHost
+	out.DbPort = replica.DbPort
+	out.DbReplicas = nil
+
+	return out
+}
+
+// The password file is read at this point so that a new password is
+// used when reconnecting.
+func (c Config) connConfig() (pgx.ConnConfig, error) {
+	out := pgx.ConnConfig{
+		Host:          c.DbHost,
+		Database:      c.DbName,
+		Port:          c.DbPort,
+		User:          c.DbUser,
+		Password:      c.DbPassword,
+		RuntimeParams: c.runtimeParams(),
+	}
+
+	if len(c.DbPasswordFile) > 0 {
+		password, err := readPasswordFile(c.DbPasswordFile)
+		if err != nil {
+			return pgx.ConnConfig{}, err
+		}
+		out.Password = password
+	}
+
+	if err := configureTls(c, &out); err != nil {
+		return pgx.ConnConfig{}, err
+	}
+
+	return out, nil
+}
+
+func (c Config) runtimeParams() map[string]string {
+	params := make(map[string]string)
+
+	if len(c.DbApplicationName) > 0 {
+		params["application_name"] = c.DbApplicationName
+	}
+	if c.DbStatementTimeout > 0 {
+		params["statement_timeout"] = strconv.FormatInt(c.DbStatementTimeout.Milliseconds(), 10)
+	}
+	if len(c.DbSearchPath) > 0 {
+		params["search_path"] = c.DbSearchPath
+	}
+
+	return params
+}
+
+func readPasswordFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.WrapCode(err, errors.ErrInvalidDbConfig)
+	}
+
+	// Editors usually add a new line at the end of the file.
+	return strings.TrimRight(string(content), "\r\n"), nil
 }
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"os"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+const passwordFileVariable = "PGPASSWORD_FILE"
+
+// https://www.postgresql.org/docs/current/libpq-envars.html
+var environmentConnectionParams = map[string]string{
+	"PGHOST":        "host",
+	"PGPORT":        "port",
+	"PGDATABASE":    "dbname",
+	"PGUSER":        "user",
+	"PGPASSWORD":    "password",
+	"PGSSLMODE":     "sslmode",
+	"PGSSLROOTCERT": "sslrootcert",
+	"PGSSLCERT":     "sslcert",
+	"PGSSLKEY":      "sslkey",
+	"PGAPPNAME":     "application_name",
+}
+
+// The variables used by libpq override the properties of the config
+// so that a deployment does not need to change the configuration
+// files. The password file has no libpq variable: it is read from
+// PGPASSWORD_FILE and takes precedence over PGPASSWORD.
+func (c Config) WithEnvironment() (Config, error) {
+	out := c
+	for variable, key := range environmentConnectionParams {
+		value, ok := os.LookupEnv(variable)
+		if !ok {
+			continue
+		}
+
+		if err := out.setConnectionParam(key, value); err != nil {
+			return c, errors.WrapCode(errors.Wrapf(err, "invalid %s", variable), errors.ErrInvalidDbConfig)
+		}
+	}
+
+	if path, ok := os.LookupEnv(passwordFileVariable); ok {
+		out.DbPassword = ""
+		out.DbPasswordFile = path
+	}
+
+	return out, nil
+}
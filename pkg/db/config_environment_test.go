@@ -0,0 +1,52 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_WithEnvironment(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Setenv("PGHOST", "remote")
+	t.Setenv("PGPORT", "5433")
+	t.Setenv("PGSSLMODE", "verify-ca")
+	t.Setenv("PGAPPNAME", "server")
+
+	actual, err := testConfig.WithEnvironment()
+	assert.Nil(err)
+	assert.Equal("remote", actual.DbHost)
+	assert.Equal(uint16(5433), actual.DbPort)
+	assert.Equal("verify-ca", actual.DbSslMode)
+	assert.Equal("server", actual.DbApplicationName)
+	assert.Equal(testConfig.DbName, actual.DbName)
+	assert.Equal(testConfig.DbPassword, actual.DbPassword)
+}
+
+func TestConfig_WithEnvironment_PasswordFile(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Setenv("PGPASSWORD", "from-env")
+	actual, err := testConfig.WithEnvironment()
+	assert.Nil(err)
+	assert.Equal("from-env", actual.DbPassword)
+
+	t.Setenv("PGPASSWORD_FILE", "/run/secrets/db-password")
+	actual, err = testConfig.WithEnvironment()
+	assert.Nil(err)
+	assert.Equal("", actual.DbPassword)
+	assert.Equal("/run/secrets/db-password", actual.DbPasswordFile)
+}
+
+func TestConfig_WithEnvironment_Invalid(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Setenv("PGHOST", "remote")
+	t.Setenv("PGPORT", "not-a-port")
+
+	actual, err := testConfig.WithEnvironment()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidDbConfig))
+	assert.Equal(testConfig, actual)
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+// https://www.postgresql.org/docs/current/sql-insert.html#SQL-ON-CONFLICT
+type conflictClause struct {
+	columns    []string
+	constraint string
+	doNothing  bool
+	keys       map[string]bool
+	updates    []conflictUpdate
+}
+
+type conflictUpdate struct {
+	column   string
+	value    interface{}
+	excluded bool
+}
+
+func (c *conflictClause) setColumns(columns []string) error {
+	if len(columns) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlConflictTarget)
+	}
+	for _, column := range columns {
+		if len(column) == 0 {
+			return errors.NewCode(errors.ErrInvalidSqlConflictTarget)
+		}
+	}
+
+	c.columns = columns
+	c.constraint = ""
+	return nil
+}
+
+func (c *conflictClause) setConstraint(constraint string) error {
+	if len(constraint) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlConflictTarget)
+	}
+
+	c.constraint = constraint
+	c.columns = nil
+	return nil
+}
+
+func (c *conflictClause) addUpdate(update conflictUpdate) error {
+	if len(update.column) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlColumn)
+	}
+
+	if c.keys == nil {
+		c.keys = make(map[string]bool)
+	}
+	if _, ok := c.keys[update.column]; ok {
+		return errors.NewCode(errors.ErrDuplicatedSqlColumn)
+	}
+
+	c.keys[update.column] = true
+	c.updates = append(c.updates, update)
+	return nil
+}
+
+func (c *conflictClause) empty() bool {
+	return len(c.columns) == 0 && len(c.constraint) == 0 && !c.doNothing && len(c.updates) == 0
+}
+
+func (c *conflictClause) toSql(args *sqlArgs) (string, error) {
+	if c.empty() {
+		return "", nil
+	}
+
+	if c.doNothing && len(c.updates) > 0 {
+		return "", errors.NewCode(errors.ErrInvalidSqlConflictAction)
+	}
+	if !c.doNothing && len(c.updates) == 0 {
+		return "", errors.NewCode(errors.ErrInvalidSqlConflictAction)
+	}
+
+	target := ""
+	if len(c.columns) > 0 {
+		target = fmt.Sprintf(" (%s)", strings.Join(c.columns, ", "))
+	} else if len(c.constraint) > 0 {
+		target = fmt.Sprintf(" ON CONSTRAINT %s", c.constraint)
+	} else if !c.doNothing {
+		return "", errors.NewCode(errors.ErrInvalidSqlConflictTarget)
+	}
+
+	if c.doNothing {
+		return fmt.Sprintf(" ON CONFLICT%s DO NOTHING", target), nil
+	}
+
+	updates := make([]string, 0, len(c.updates))
+	for _, update := range c.updates {
+		if update.excluded {
+			updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", update.column, update.column))
+			continue
+		}
+
+		placeholder, err := args.add(update.value)
+		if err != nil {
+			return "", err
+		}
+
+		updates = append(updates, fmt.Sprintf("%s = %s", update.column, placeholder))
+	}
+
+	return fmt.Sprintf(" ON CONFLICT%s DO UPDATE SET %s", target, strings.Join(updates, ", ")), nil
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
+)
+
+const defaultReconnectMinBackoff = 100 * time.Millisecond
+const defaultReconnectMaxBackoff = 10 * time.Second
+
+type connectionMonitor struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newConnectionMonitor() *connectionMonitor {
+	return &connectionMonitor{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+func (m *connectionMonitor) shutdown() {
+	close(m.stop)
+	<-m.done
+}
+
+// Runs in the background while the database is connected: it checks
+// the health of the pool periodically if configured to do so and
+// reconnects whenever the circuit breaker opens.
+func (db *postgresDb) watchConnection(m *connectionMonitor) {
+	defer close(m.done)
+
+	var ticks <-chan time.Time
+	if db.config.DbHealthCheckInterval > 0 {
+		ticker := time.NewTicker(db.config.DbHealthCheckInterval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticks:
+			db.checkHealth()
+		case <-db.breaker.tripped:
+			db.reconnect(m)
+		}
+	}
+}
+
+func (db *postgresDb) checkHealth() {
+	pool := db.currentPool()
+	if pool == nil || db.breaker.isOpen() {
+		return
+	}
+
+	if err := db.ping(pool); err != nil {
+		logger.Warnf("health check of %s failed (err: %v)", db.config, err)
+		db.breaker.trip()
+	}
+}
+
+func (db *postgresDb) reconnect(m *connectionMonitor) {
+	backoff, maxBackoff := reconnectBackoff(db.config)
+
+	for db.breaker.isOpen() {
+		err := db.replacePool()
+		if err == nil {
+			logger.Infof("reconnected to %s", db.config)
+			db.breaker.reset()
+			return
+		}
+
+		logger.Warnf("failed to reconnect to %s, retrying in %v (err: %v)", db.config, backoff, err)
+
+		select {
+		case <-m.stop:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func reconnectBackoff(conf Config) (time.Duration, time.Duration) {
+	minBackoff := conf.DbReconnectMinBackoff
+	if minBackoff == 0 {
+		minBackoff = defaultReconnectMinBackoff
+	}
+	maxBackoff := conf.DbReconnectMaxBackoff
+	if maxBackoff == 0 {
+		maxBackoff = defaultReconnectMaxBackoff
+	}
+
+	return minBackoff, maxBackoff
+}
+
+func (db *postgresDb) replacePool() error {
+	pool, err := db.createPool(context.Background())
+	if err != nil {
+		return err
+	}
+	if err := db.ping(pool); err != nil {
+		pool.Close()
+		return err
+	}
+
+	db.lock.Lock()
+	old := db.pool
+	db.pool = pool
+	db.lock.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+
+	return nil
+}
+
+func (db *postgresDb) ping(pool DriverPool) error {
+	ctx, cancel := withQueryTimeout(context.Background(), db.config.DbConnectionTimeout)
+	defer cancel()
+
+	return pool.Ping(ctx)
+}
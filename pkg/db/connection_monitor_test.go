@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPoolFactory struct {
+	lock     sync.Mutex
+	pools    []*mockPgxDbFacade
+	failures atomic.Int32
+	created  atomic.Int32
+}
+
+func (f *mockPoolFactory) create(config pgx.ConnPoolConfig) (DriverPool, error) {
+	f.created.Add(1)
+	if f.failures.Load() > 0 {
+		f.failures.Add(-1)
+		return nil, errDefault
+	}
+
+	pool := &mockPgxDbFacade{}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.pools = append(f.pools, pool)
+	return pool, nil
+}
+
+func (f *mockPoolFactory) pool(id int) *mockPgxDbFacade {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if id >= len(f.pools) {
+		return nil
+	}
+	return f.pools[id]
+}
+
+func newMonitoredTestDatabase(t *testing.T, factory *mockPoolFactory) *postgresDb {
+	config := testConfig
+	config.DbHealthCheckInterval = 10 * time.Millisecond
+	config.DbReconnectMinBackoff = time.Millisecond
+	config.DbReconnectMaxBackoff = 5 * time.Millisecond
+	config.creationFunc = factory.create
+
+	db := NewPostgresDatabase(config).(*postgresDb)
+	assert.Nil(t, db.Connect(context.Background()))
+	t.Cleanup(func() {
+		db.Disconnect(context.Background())
+	})
+
+	return db
+}
+
+func TestConnectionMonitor_HealthCheckFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	factory := &mockPoolFactory{}
+	db := newMonitoredTestDatabase(t, factory)
+	factory.failures.Store(3)
+	factory.pool(0).setPingError(io.EOF)
+
+	assert.Eventually(func() bool {
+		return factory.pool(1) != nil && !db.breaker.isOpen()
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(int32(5), factory.created.Load())
+	assert.Equal(int32(1), factory.pool(0).closeCalled.Load())
+	assert.Equal(DriverPool(factory.pool(1)), db.currentPool())
+
+	rows := db.Query(context.Background(), queryImpl{sqlCode: "SELECT 1"})
+	assert.Nil(rows.Err())
+}
+
+func TestConnectionMonitor_FailsFastWhileDown(t *testing.T) {
+	assert := assert.New(t)
+
+	factory := &mockPoolFactory{}
+	factory.failures.Store(1000)
+	config := testConfig
+	config.DbReconnectMinBackoff = time.Millisecond
+	config.creationFunc = factory.create
+	db := NewPostgresDatabase(config).(*postgresDb)
+	db.pool = &mockPgxDbFacade{}
+	db.breaker.trip()
+
+	res := db.Execute(context.Background(), queryImpl{sqlCode: "DELETE FROM t"})
+	assert.True(errors.IsErrorWithCode(res.Err(), errors.ErrDbConnectionInvalid))
+	rows := db.Query(context.Background(), queryImpl{sqlCode: "SELECT 1"})
+	assert.True(errors.IsErrorWithCode(rows.Err(), errors.ErrDbConnectionInvalid))
+	_, err := db.Begin(context.Background())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbConnectionInvalid))
+	assert.Empty(db.pool.(*mockPgxDbFacade).sqlExecuteReceived)
+}
+
+func TestConnectionMonitor_ConsecutiveFailuresOpenTheCircuit(t *testing.T) {
+	assert := assert.New(t)
+
+	factory := &mockPoolFactory{}
+	config := testConfig
+	config.DbCircuitBreakerThreshold = 2
+	config.DbReconnectMinBackoff = time.Hour
+	config.creationFunc = factory.create
+	db := NewPostgresDatabase(config).(*postgresDb)
+	assert.Nil(db.Connect(context.Background()))
+	defer db.Disconnect(context.Background())
+
+	factory.failures.Store(1000)
+	factory.pool(0).execError = io.EOF
+	query := queryImpl{sqlCode: "DELETE FROM t"}
+
+	res := db.Execute(context.Background(), query)
+	assert.True(errors.IsErrorWithCode(res.Err(), errors.ErrDbRequestFailed))
+	res = db.Execute(context.Background(), query)
+	assert.True(errors.IsErrorWithCode(res.Err(), errors.ErrDbRequestFailed))
+	assert.True(db.breaker.isOpen())
+
+	res = db.Execute(context.Background(), query)
+	assert.True(errors.IsErrorWithCode(res.Err(), errors.ErrDbConnectionInvalid))
+	assert.Equal(2, len(factory.pool(0).sqlExecuteReceived))
+}
+
+func TestConnectionMonitor_IterationFailuresOpenTheCircuit(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	config.DbCircuitBreakerThreshold = 2
+	db := NewPostgresDatabase(config).(*postgresDb)
+	pool := &mockPgxDbFacade{}
+	db.pool = pool
+	query := queryImpl{sqlCode: "SELECT 1"}
+
+	for id := 0; id < 2; id++ {
+		pool.rows = &mockSqlRows{numberOfRows: 1, err: io.EOF}
+		rows := db.Query(context.Background(), query)
+		assert.Nil(rows.Err())
+		assert.False(db.breaker.isOpen())
+
+		err := rows.GetAll(&mockParser{})
+		assert.True(errors.IsErrorWithCode(err, errors.ErrDbRequestFailed))
+	}
+
+	assert.True(db.breaker.isOpen())
+}
+
+func TestConnectionMonitor_DisconnectStopsReconnection(t *testing.T) {
+	assert := assert.New(t)
+
+	factory := &mockPoolFactory{}
+	config := testConfig
+	config.DbReconnectMinBackoff = time.Hour
+	config.creationFunc = factory.create
+	db := NewPostgresDatabase(config).(*postgresDb)
+	assert.Nil(db.Connect(context.Background()))
+
+	factory.failures.Store(1000)
+	db.breaker.trip()
+	assert.Eventually(func() bool {
+		return factory.created.Load() == 2
+	}, time.Second, time.Millisecond)
+
+	assert.Nil(db.Disconnect(context.Background()))
+	assert.Nil(db.monitor)
+	assert.Equal(int32(2), factory.created.Load())
+	assert.Equal(int32(1), factory.pool(0).closeCalled.Load())
+}
+
+func TestPostgresDatabase_RetriesIdempotentQueries(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	config.DbRetryAttempts = 2
+	config.DbRetryBackoff = time.Millisecond
+	db := NewPostgresDatabase(config).(*postgresDb)
+	pool := &mockPgxDbFacade{
+		queryError: pgx.PgError{Code: deadlockDetected},
+		execError:  pgx.PgError{Code: serializationFailure},
+	}
+	db.pool = pool
+
+	rows := db.Query(WithIdempotentQuery(context.Background()), queryImpl{sqlCode: "SELECT 1"})
+	assert.True(errors.IsErrorWithCode(rows.Err(), errors.ErrDbDeadlockDetected))
+	assert.Equal(3, len(pool.sqlQueriesReceived))
+
+	res := db.Execute(WithIdempotentQuery(context.Background()), queryImpl{sqlCode: "DELETE FROM t"})
+	assert.True(errors.IsErrorWithCode(res.Err(), errors.ErrDbSerializationFailure))
+	assert.Equal(3, len(pool.sqlExecuteReceived))
+
+	db.Execute(context.Background(), queryImpl{sqlCode: "DELETE FROM t"})
+	assert.Equal(4, len(pool.sqlExecuteReceived))
+}
+
+func TestPostgresDatabase_Retry_ClosesDiscardedRows(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	config.DbRetryAttempts = 2
+	config.DbRetryBackoff = time.Millisecond
+	db := NewPostgresDatabase(config).(*postgresDb)
+	mockRows := &mockSqlRows{err: pgx.PgError{Code: deadlockDetected}}
+	pool := &mockPgxDbFacade{rows: mockRows}
+	db.pool = pool
+
+	rows := db.Query(WithIdempotentQuery(context.Background()), queryImpl{sqlCode: "SELECT 1"})
+	assert.True(errors.IsErrorWithCode(rows.Err(), errors.ErrDbDeadlockDetected))
+	assert.Equal(3, len(pool.sqlQueriesReceived))
+	assert.Equal(int32(2), mockRows.closeCalls.Load())
+
+	rows.Close()
+	assert.Equal(int32(3), mockRows.closeCalls.Load())
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+)
+
+// Stops the iteration as soon as the context is done, even when the
+// rows are already received: a long export can then be interrupted
+// between two rows.
+type contextRows struct {
+	Rows
+	ctx context.Context
+	err error
+}
+
+func newContextRows(ctx context.Context, rows Rows) Rows {
+	return &contextRows{
+		Rows: rows,
+		ctx:  ctx,
+	}
+}
+
+func (r *contextRows) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	if err := r.ctx.Err(); err != nil {
+		r.err = wrapQueryError(r.ctx, err)
+		r.Rows.Close()
+		return false
+	}
+
+	return r.Rows.Next()
+}
+
+func (r *contextRows) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+
+	return r.Rows.Err()
+}
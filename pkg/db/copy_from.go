@@ -0,0 +1,60 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx"
+)
+
+// https://www.postgresql.org/docs/current/sql-copy.html
+type CopyFrom interface {
+	Valid() bool
+	Table() string
+	Columns() []string
+	Rows() [][]interface{}
+	Verbose() bool
+}
+
+type CopyFromBuilder interface {
+	BuildCopyFrom() (CopyFrom, error)
+}
+
+type copyFromImpl struct {
+	table   string
+	columns []string
+	rows    [][]interface{}
+	verbose bool
+}
+
+func (c copyFromImpl) Valid() bool {
+	return len(c.table) > 0 && len(c.columns) > 0
+}
+
+func (c copyFromImpl) Table() string {
+	return c.table
+}
+
+func (c copyFromImpl) Columns() []string {
+	return c.columns
+}
+
+func (c copyFromImpl) Rows() [][]interface{} {
+	return c.rows
+}
+
+func (c copyFromImpl) Verbose() bool {
+	return c.verbose
+}
+
+func copyFromToDebugStr(c CopyFrom) string {
+	return fmt.Sprintf("COPY %s (%s) FROM STDIN (%d row(s))", c.Table(), strings.Join(c.Columns(), ", "), len(c.Rows()))
+}
+
+func tableToIdentifier(table string) pgx.Identifier {
+	return pgx.Identifier(strings.Split(table, "."))
+}
+
+func copyFromResultTag(rows int) pgx.CommandTag {
+	return pgx.CommandTag(fmt.Sprintf("%s %d", copyCommandTag, rows))
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyFrom_Valid(t *testing.T) {
+	assert := assert.New(t)
+
+	c := copyFromImpl{}
+	assert.False(c.Valid())
+
+	c.table = "table"
+	assert.False(c.Valid())
+
+	c.columns = []string{"a"}
+	assert.True(c.Valid())
+}
+
+func TestCopyFromToDebugStr(t *testing.T) {
+	assert := assert.New(t)
+
+	c := copyFromImpl{
+		table:   "table",
+		columns: []string{"a", "b"},
+		rows:    [][]interface{}{{1, 2}, {3, 4}},
+	}
+
+	assert.Equal("COPY table (a, b) FROM STDIN (2 row(s))", copyFromToDebugStr(c))
+}
+
+func TestTableToIdentifier(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(pgx.Identifier{"table"}, tableToIdentifier("table"))
+	assert.Equal(pgx.Identifier{"schema", "table"}, tableToIdentifier("schema.table"))
+}
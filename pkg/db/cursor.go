@@ -0,0 +1,75 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+type Cursor string
+
+func NewCursor(values ...interface{}) (Cursor, error) {
+	if len(values) == 0 {
+		return "", errors.NewCode(errors.ErrInvalidSqlCursor)
+	}
+
+	out := make([]*string, 0, len(values))
+	for _, value := range values {
+		str, err := cursorValueToStr(value)
+		if err != nil {
+			return "", errors.WrapCode(err, errors.ErrInvalidSqlCursor)
+		}
+
+		out = append(out, str)
+	}
+
+	raw, err := json.Marshal(out)
+	if err != nil {
+		return "", errors.WrapCode(err, errors.ErrInvalidSqlCursor)
+	}
+
+	return Cursor(base64.URLEncoding.EncodeToString(raw)), nil
+}
+
+func (c Cursor) decode() ([]*string, error) {
+	raw, err := base64.URLEncoding.DecodeString(string(c))
+	if err != nil {
+		return nil, errors.WrapCode(err, errors.ErrInvalidSqlCursor)
+	}
+
+	var values []*string
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, errors.WrapCode(err, errors.ErrInvalidSqlCursor)
+	}
+	if len(values) == 0 {
+		return nil, errors.NewCode(errors.ErrInvalidSqlCursor)
+	}
+
+	return values, nil
+}
+
+// cursorValueToStr returns nil for the NULL values: they are kept in the
+// cursor so that the seek predicate can use IS NULL checks.
+func cursorValueToStr(value interface{}) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil, nil
+	}
+
+	if t, ok := value.(time.Time); ok {
+		str := t.Format(time.RFC3339Nano)
+		return &str, nil
+	}
+
+	str, err := argToStr(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &str, nil
+}
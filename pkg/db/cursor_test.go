@@ -0,0 +1,77 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCursor_NoValues(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewCursor()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlCursor))
+}
+
+func TestNewCursor_NilValue(t *testing.T) {
+	assert := assert.New(t)
+
+	var nilTime *time.Time
+	c, err := NewCursor("value", nil, nilTime)
+	assert.Nil(err)
+
+	values, err := c.decode()
+	assert.Nil(err)
+	assert.Equal(3, len(values))
+	assert.Equal("value", *values[0])
+	assert.Nil(values[1])
+	assert.Nil(values[2])
+}
+
+func TestNewCursor_UnmarshalableValue(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewCursor(mockUnmarshalable{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlCursor))
+}
+
+func TestCursor_Decode(t *testing.T) {
+	assert := assert.New(t)
+
+	id := uuid.MustParse("08ce96a3-3430-48a8-a3b2-b1c987a207ca")
+	someTime := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
+
+	c, err := NewCursor(someTime, id, 32)
+	assert.Nil(err)
+
+	values, err := c.decode()
+	assert.Nil(err)
+	expected := []string{
+		"2009-11-17T20:34:58.651387237Z",
+		"08ce96a3-3430-48a8-a3b2-b1c987a207ca",
+		"32",
+	}
+	assert.Equal(len(expected), len(values))
+	for id, value := range values {
+		assert.Equal(expected[id], *value)
+	}
+}
+
+func TestCursor_Decode_Invalid(t *testing.T) {
+	assert := assert.New(t)
+
+	c := Cursor("not base 64")
+	_, err := c.decode()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlCursor))
+
+	c = Cursor("bm90IGpzb24=")
+	_, err = c.decode()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlCursor))
+
+	c = Cursor("W10=")
+	_, err = c.decode()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlCursor))
+}
@@ -9,4 +9,9 @@ type Database interface {
 
 	Query(ctx context.Context, query Query) Rows
 	Execute(ctx context.Context, query Query) Result
+	CopyFrom(ctx context.Context, copy CopyFrom) Result
+
+	Begin(ctx context.Context) (Transaction, error)
+
+	Subscribe(ctx context.Context, channel string) (Subscription, error)
 }
@@ -11,13 +11,15 @@ type DeleteQueryBuilder interface {
 
 	SetTable(table string) error
 	SetFilter(filter Filter) error
+	AddReturning(column string) error
 	SetVerbose(verbose bool)
 }
 
 type deleteQueryBuilder struct {
-	table   string
-	filter  Filter
-	verbose bool
+	table     string
+	filter    Filter
+	returning returningClause
+	verbose   bool
 }
 
 func NewDeleteQueryBuilder() DeleteQueryBuilder {
@@ -42,6 +44,10 @@ func (b *deleteQueryBuilder) SetFilter(filter Filter) error {
 	return nil
 }
 
+func (b *deleteQueryBuilder) AddReturning(column string) error {
+	return b.returning.add(column)
+}
+
 func (b *deleteQueryBuilder) SetVerbose(verbose bool) {
 	b.verbose = verbose
 }
@@ -53,12 +59,16 @@ func (b *deleteQueryBuilder) Build() (Query, error) {
 
 	// https://www.w3schools.com/sql/sql_delete.asp
 	sqlQuery := fmt.Sprintf("DELETE FROM %s", b.table)
+	var args sqlArgs
 	if b.filter != nil {
-		sqlQuery += fmt.Sprintf(" WHERE %s", b.filter.ToSql())
+		sqlQuery += fmt.Sprintf(" WHERE %s", args.addFilter(b.filter))
 	}
 
+	sqlQuery += b.returning.toSql()
+
 	query := queryImpl{
 		sqlCode: sqlQuery,
+		args:    args.values,
 		verbose: b.verbose,
 	}
 
@@ -104,3 +104,49 @@ func TestDeleteQueryBuilder_Build_WithFilter(t *testing.T) {
 	assert.True(query.Valid())
 	assert.Equal("DELETE FROM table WHERE someFilter", query.ToSql())
 }
+
+func TestDeleteQueryBuilder_Build_WithFilterArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewDeleteQueryBuilder()
+	b.SetTable("table")
+	f := filterImpl{
+		sqlCode: "key in ($1)",
+		args:    []interface{}{"value"},
+	}
+	b.SetFilter(f)
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.True(query.Valid())
+	assert.Equal("DELETE FROM table WHERE key in ($1)", query.ToSql())
+	assert.Equal([]interface{}{"value"}, query.Args())
+}
+
+func TestDeleteQueryBuilder_AddReturning(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewDeleteQueryBuilder()
+
+	err := b.AddReturning("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlColumn))
+
+	err = b.AddReturning("id")
+	assert.Nil(err)
+
+	err = b.AddReturning("id")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDuplicatedSqlColumn))
+}
+
+func TestDeleteQueryBuilder_Build_WithReturning(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewDeleteQueryBuilder()
+	b.SetTable("table")
+	b.SetFilter(filterImpl{sqlCode: "key = $1", args: []interface{}{"value"}})
+	b.AddReturning("id")
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("DELETE FROM table WHERE key = $1 RETURNING id", query.ToSql())
+}
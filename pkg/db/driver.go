@@ -0,0 +1,19 @@
+package db
+
+import "github.com/jackc/pgx"
+
+// A Driver runs the queries of a database created with
+// NewDatabaseWithDriver on an engine other than postgres, such as the
+// in-memory one of the memory package. The hooks, the timeouts and the
+// transactions of the postgres database still apply.
+type Driver interface {
+	Open(config pgx.ConnPoolConfig) (DriverPool, error)
+	Listen(config pgx.ConnConfig) (DriverListener, error)
+}
+
+func NewDatabaseWithDriver(conf Config, driver Driver) Database {
+	conf.creationFunc = driver.Open
+	conf.listenerFunc = driver.Listen
+
+	return NewPostgresDatabase(conf)
+}
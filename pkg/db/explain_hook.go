@@ -0,0 +1,250 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
+)
+
+var infoLog = logger.ScopedInfof
+
+// https://www.postgresql.org/docs/current/sql-explain.html
+// The plan is the one of the text format, one line per node.
+type QueryPlan struct {
+	Label    string
+	Query    Query
+	Duration time.Duration
+	// Analyzed plans include the actual timings and the buffers used.
+	Analyzed bool
+	Plan     string
+}
+
+type QueryPlanHandler func(ctx context.Context, plan QueryPlan)
+
+// Only these statements can be explained: the others, such as the
+// ones managing the transactions, are ignored.
+var explainableStatements = []string{"SELECT", "INSERT", "UPDATE", "DELETE", "WITH", "VALUES"}
+
+// ANALYZE executes the query again: only the statements which do not
+// write are analyzed unless the writes are explicitly allowed. A WITH
+// might hold an INSERT so it is not part of them.
+var readOnlyStatements = []string{"SELECT", "VALUES"}
+
+// Used when the queries have no timeout: a plan never holds one of
+// the connections of the pool for long.
+const defaultExplainTimeout = 30 * time.Second
+
+// Plans computed at the same time, the slow queries are not explained
+// while this many are in progress.
+const maxConcurrentExplains = 2
+
+type explainHook struct {
+	verbose       bool
+	threshold     time.Duration
+	analyze       bool
+	analyzeWrites bool
+	timeout       time.Duration
+	handler       QueryPlanHandler
+	pool          func() DriverPool
+	slots         chan struct{}
+	lock          sync.Mutex
+	pending       sync.WaitGroup
+}
+
+func explainEnabled(conf Config) bool {
+	return conf.DbExplainVerboseQueries || conf.DbExplainThreshold > 0
+}
+
+// The plan is computed on its own connection of the pool: the hook is
+// not used for the queries of the transactions as their connection is
+// busy and might hold locks the plan would wait for.
+func newExplainHook(conf Config, pool func() DriverPool) *explainHook {
+	h := &explainHook{
+		verbose:       conf.DbExplainVerboseQueries,
+		threshold:     conf.DbExplainThreshold,
+		analyze:       conf.DbExplainAnalyze,
+		analyzeWrites: conf.DbExplainAnalyzeWrites,
+		timeout:       conf.DbQueryTimeout,
+		handler:       conf.DbExplainHandler,
+		pool:          pool,
+		slots:         make(chan struct{}, maxConcurrentExplains),
+	}
+	if h.timeout == 0 {
+		h.timeout = defaultExplainTimeout
+	}
+	if h.handler == nil {
+		h.handler = logQueryPlan
+	}
+
+	return h
+}
+
+func (h *explainHook) BeforeQuery(ctx context.Context, event QueryEvent) context.Context {
+	return ctx
+}
+
+// The query is explained in the background once it completes so that
+// its duration is known without making the caller wait for the plan.
+func (h *explainHook) AfterQuery(ctx context.Context, event QueryEvent) {
+	if !h.shouldExplain(event) {
+		return
+	}
+
+	select {
+	case h.slots <- struct{}{}:
+	default:
+		traceLog(ctx, "not explaining query %s: too many plans in progress", queryToDebugStr(event.Query))
+		return
+	}
+
+	// The pool is taken before starting the plan so that wait covers
+	// every plan which uses it.
+	h.lock.Lock()
+	pool := h.pool()
+	if pool == nil {
+		h.lock.Unlock()
+		<-h.slots
+		return
+	}
+	h.pending.Add(1)
+	h.lock.Unlock()
+
+	go func() {
+		defer h.pending.Done()
+		defer func() { <-h.slots }()
+
+		h.explain(detachedContext{ctx}, pool, event)
+	}()
+}
+
+// Waits for the plans in progress. The pool must no longer be available
+// to the hook so that no plan starts meanwhile.
+func (h *explainHook) wait() {
+	h.lock.Lock()
+	h.lock.Unlock()
+	h.pending.Wait()
+}
+
+func (h *explainHook) explain(ctx context.Context, pool DriverPool, event QueryEvent) {
+
+	// A query which timed out would time out again when analyzed.
+	analyze := h.analyze && event.Err == nil && (h.analyzeWrites || readOnly(event.Query))
+	plan, err := explainQuery(ctx, pool, event.Query, analyze, h.timeout)
+	if err != nil {
+		warnLog(ctx, "failed to explain query %s (err: %v)", queryToDebugStr(event.Query), err)
+		return
+	}
+
+	h.handler(ctx, QueryPlan{
+		Label:    event.Label,
+		Query:    event.Query,
+		Duration: event.Duration,
+		Analyzed: analyze,
+		Plan:     plan,
+	})
+}
+
+// A query which timed out is the most interesting one to explain, the
+// other failures would fail the same way.
+func (h *explainHook) shouldExplain(event QueryEvent) bool {
+	if event.Err != nil && !errors.IsErrorWithCode(event.Err, errors.ErrDbRequestTimeout) {
+		return false
+	}
+	if !explainable(event.Query) {
+		return false
+	}
+
+	if h.verbose && event.Query.Verbose() {
+		return true
+	}
+	return h.threshold > 0 && event.Duration >= h.threshold
+}
+
+func explainable(query Query) bool {
+	return hasStatementPrefix(query, explainableStatements)
+}
+
+func readOnly(query Query) bool {
+	return hasStatementPrefix(query, readOnlyStatements)
+}
+
+func hasStatementPrefix(query Query, statements []string) bool {
+	sql := strings.ToUpper(strings.TrimSpace(query.ToSql()))
+	for _, statement := range statements {
+		if strings.HasPrefix(sql, statement) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Keeps the values of the context, used by the logs, without its
+// deadline and cancellation: the plan is computed after the query
+// returned to the caller.
+type detachedContext struct {
+	context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (c detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (c detachedContext) Err() error {
+	return nil
+}
+
+// ANALYZE executes the query again: the transaction is always rolled
+// back so that the writes are not applied twice.
+func explainQuery(ctx context.Context, pool DriverPool, query Query, analyze bool, timeout time.Duration) (string, error) {
+	explainCtx, cancel := withQueryTimeout(ctx, timeout)
+	defer cancel()
+
+	tx, err := pool.Begin(explainCtx)
+	if err != nil {
+		return "", errors.WrapCode(err, errors.ErrDbTransactionBeginFailed)
+	}
+	defer tx.Rollback(explainCtx)
+
+	sql := "EXPLAIN " + query.ToSql()
+	if analyze {
+		sql = "EXPLAIN (ANALYZE, BUFFERS) " + query.ToSql()
+	}
+
+	rows, err := tx.Query(explainCtx, sql, query.Args()...)
+	if err != nil {
+		return "", wrapQueryError(explainCtx, err)
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return "", errors.WrapCode(err, errors.ErrSqlRowParsingFailed)
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return "", wrapQueryError(explainCtx, err)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func logQueryPlan(ctx context.Context, plan QueryPlan) {
+	label := plan.Label
+	if len(label) == 0 {
+		label = UnlabeledQuery
+	}
+
+	infoLog(ctx, "plan of query %s (took %v): %s\n%s", label, plan.Duration, queryToDebugStr(plan.Query), plan.Plan)
+}
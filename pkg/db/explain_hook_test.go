@@ -0,0 +1,354 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockQueryPlanHandler struct {
+	plans []QueryPlan
+}
+
+func (m *mockQueryPlanHandler) handle(ctx context.Context, plan QueryPlan) {
+	m.plans = append(m.plans, plan)
+}
+
+func newTestExplainedPool() *mockPgxDbFacade {
+	rows := &mockPlanRows{
+		mockSqlRows: mockSqlRows{numberOfRows: 2},
+		lines:       []string{"Seq Scan on players", "  Filter: (name = 'alice'::text)"},
+	}
+
+	return &mockPgxDbFacade{
+		tx: &mockPgxTxFacade{rows: rows},
+	}
+}
+
+func newTestExplainHook(conf Config, pool DriverPool) (*explainHook, *mockQueryPlanHandler) {
+	handler := &mockQueryPlanHandler{}
+	conf.DbExplainHandler = handler.handle
+
+	return newExplainHook(conf, func() DriverPool { return pool }), handler
+}
+
+func TestExplainHook_ShouldExplain(t *testing.T) {
+	assert := assert.New(t)
+
+	h := explainHook{verbose: true, threshold: time.Second}
+	query := queryImpl{sqlCode: "SELECT name FROM players"}
+
+	assert.False(h.shouldExplain(QueryEvent{Query: query, Duration: time.Millisecond}))
+	assert.True(h.shouldExplain(QueryEvent{Query: query, Duration: time.Second}))
+
+	query.verbose = true
+	assert.True(h.shouldExplain(QueryEvent{Query: query}))
+	assert.False(h.shouldExplain(QueryEvent{Query: query, Err: errors.NewCode(errors.ErrDbRequestFailed)}))
+	assert.True(h.shouldExplain(QueryEvent{Query: query, Err: errors.NewCode(errors.ErrDbRequestTimeout)}))
+	assert.False(h.shouldExplain(QueryEvent{Query: queryImpl{sqlCode: "SAVEPOINT s", verbose: true}}))
+	assert.True(h.shouldExplain(QueryEvent{Query: queryImpl{sqlCode: "  with cte AS (SELECT 1) SELECT 1", verbose: true}}))
+
+	h = explainHook{}
+	assert.False(h.shouldExplain(QueryEvent{Query: query, Duration: time.Hour}))
+}
+
+func TestExplainHook(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := newTestExplainedPool()
+	h, handler := newTestExplainHook(Config{DbExplainVerboseQueries: true}, pool)
+	event := QueryEvent{
+		Query:    queryImpl{sqlCode: "SELECT id FROM players WHERE name = $1", args: []interface{}{"alice"}, verbose: true},
+		Label:    "player",
+		Duration: 3 * time.Millisecond,
+	}
+
+	ctx := h.BeforeQuery(context.Background(), event)
+	h.AfterQuery(ctx, event)
+	h.wait()
+
+	assert.Equal([]string{"EXPLAIN SELECT id FROM players WHERE name = $1"}, pool.tx.sqlQueriesReceived)
+	assert.Equal(int32(1), pool.tx.rollbackCalled.Load())
+	assert.Equal(int32(0), pool.tx.commitCalled.Load())
+	expected := QueryPlan{
+		Label:    "player",
+		Query:    event.Query,
+		Duration: 3 * time.Millisecond,
+		Plan:     "Seq Scan on players\n  Filter: (name = 'alice'::text)",
+	}
+	assert.Equal([]QueryPlan{expected}, handler.plans)
+}
+
+func TestExplainHook_Analyze(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := newTestExplainedPool()
+	conf := Config{DbExplainThreshold: time.Second, DbExplainAnalyze: true}
+	h, handler := newTestExplainHook(conf, pool)
+	event := QueryEvent{
+		Query:    queryImpl{sqlCode: "SELECT id FROM players"},
+		Duration: 2 * time.Second,
+	}
+
+	h.AfterQuery(context.Background(), event)
+	h.wait()
+
+	assert.Equal([]string{"EXPLAIN (ANALYZE, BUFFERS) SELECT id FROM players"}, pool.tx.sqlQueriesReceived)
+	assert.Equal(int32(1), pool.tx.rollbackCalled.Load())
+	assert.Equal(1, len(handler.plans))
+	assert.True(handler.plans[0].Analyzed)
+}
+
+func TestExplainHook_Analyze_Timeout(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := newTestExplainedPool()
+	conf := Config{DbExplainThreshold: time.Second, DbExplainAnalyze: true}
+	h, handler := newTestExplainHook(conf, pool)
+	event := QueryEvent{
+		Query:    queryImpl{sqlCode: "SELECT id FROM players"},
+		Duration: 2 * time.Second,
+		Err:      errors.NewCode(errors.ErrDbRequestTimeout),
+	}
+
+	h.AfterQuery(context.Background(), event)
+	h.wait()
+
+	assert.Equal([]string{"EXPLAIN SELECT id FROM players"}, pool.tx.sqlQueriesReceived)
+	assert.Equal(1, len(handler.plans))
+	assert.False(handler.plans[0].Analyzed)
+}
+
+func TestExplainHook_Analyze_Writes(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := newTestExplainedPool()
+	conf := Config{DbExplainThreshold: time.Second, DbExplainAnalyze: true}
+	h, handler := newTestExplainHook(conf, pool)
+	event := QueryEvent{
+		Query:    queryImpl{sqlCode: "UPDATE players SET level = 2"},
+		Duration: 2 * time.Second,
+	}
+
+	h.AfterQuery(context.Background(), event)
+	h.wait()
+	h.analyzeWrites = true
+	h.AfterQuery(context.Background(), event)
+	h.wait()
+
+	expected := []string{
+		"EXPLAIN UPDATE players SET level = 2",
+		"EXPLAIN (ANALYZE, BUFFERS) UPDATE players SET level = 2",
+	}
+	assert.Equal(expected, pool.tx.sqlQueriesReceived)
+	assert.Equal(int32(2), pool.tx.rollbackCalled.Load())
+	assert.Equal(2, len(handler.plans))
+	assert.False(handler.plans[0].Analyzed)
+	assert.True(handler.plans[1].Analyzed)
+}
+
+func TestExplainHook_CancelledQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := newTestExplainedPool()
+	h, handler := newTestExplainHook(Config{DbExplainVerboseQueries: true}, pool)
+	event := QueryEvent{Query: queryImpl{sqlCode: "SELECT 1", verbose: true}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	h.AfterQuery(ctx, event)
+	h.wait()
+
+	assert.Equal(1, len(handler.plans))
+}
+
+func TestExplainHook_TooManyPlans(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := newTestExplainedPool()
+	h, handler := newTestExplainHook(Config{DbExplainVerboseQueries: true}, pool)
+	event := QueryEvent{Query: queryImpl{sqlCode: "SELECT 1", verbose: true}}
+
+	for id := 0; id < maxConcurrentExplains; id++ {
+		h.slots <- struct{}{}
+	}
+	h.AfterQuery(context.Background(), event)
+	h.wait()
+	assert.Empty(pool.tx.sqlQueriesReceived)
+
+	<-h.slots
+	h.AfterQuery(context.Background(), event)
+	h.wait()
+	assert.Equal(1, len(handler.plans))
+}
+
+func TestExplainHook_Failure(t *testing.T) {
+	assert := assert.New(t)
+	defer func() {
+		warnLog = logger.ScopedWarnf
+	}()
+
+	var warnings []string
+	warnLog = func(ctx context.Context, format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	pool := &mockPgxDbFacade{beginError: errDefault}
+	h, handler := newTestExplainHook(Config{DbExplainVerboseQueries: true}, pool)
+	event := QueryEvent{Query: queryImpl{sqlCode: "SELECT 1", verbose: true}}
+
+	h.AfterQuery(context.Background(), event)
+	h.wait()
+	pool.beginError = nil
+	pool.tx = &mockPgxTxFacade{queryError: pgx.PgError{Code: "42P01"}}
+	h.AfterQuery(context.Background(), event)
+	h.wait()
+
+	assert.Empty(handler.plans)
+	assert.Equal(2, len(warnings))
+	assert.Contains(warnings[0], "failed to explain query SELECT 1")
+	assert.Equal(int32(1), pool.tx.rollbackCalled.Load())
+}
+
+func TestExplainHook_NotConnected(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := &mockQueryPlanHandler{}
+	conf := Config{DbExplainVerboseQueries: true, DbExplainHandler: handler.handle}
+	h := newExplainHook(conf, func() DriverPool { return nil })
+
+	h.AfterQuery(context.Background(), QueryEvent{Query: queryImpl{sqlCode: "SELECT 1", verbose: true}})
+	h.wait()
+	assert.Empty(handler.plans)
+}
+
+func TestExplainHook_LogsPlan(t *testing.T) {
+	assert := assert.New(t)
+	defer func() {
+		infoLog = logger.ScopedInfof
+	}()
+
+	var messages []string
+	infoLog = func(ctx context.Context, format string, args ...interface{}) {
+		messages = append(messages, fmt.Sprintf(format, args...))
+	}
+
+	h := newExplainHook(Config{DbExplainVerboseQueries: true}, func() DriverPool { return newTestExplainedPool() })
+	event := QueryEvent{
+		Query:    queryImpl{sqlCode: "SELECT id FROM players WHERE name = $1", args: []interface{}{"alice"}, verbose: true},
+		Duration: time.Millisecond,
+	}
+	h.AfterQuery(context.Background(), event)
+	h.wait()
+
+	expected := "plan of query unlabeled (took 1ms): SELECT id FROM players WHERE name = 'alice'\nSeq Scan on players\n  Filter: (name = 'alice'::text)"
+	assert.Equal([]string{expected}, messages)
+}
+
+func TestPostgresDatabase_Explain(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := newTestExplainedPool()
+	pool.tag = "DELETE 1"
+	handler := &mockQueryPlanHandler{}
+	hook := &mockQueryHook{name: "hook"}
+	config := testConfig
+	config.Hooks = []QueryHook{hook}
+	config.DbExplainVerboseQueries = true
+	config.DbExplainHandler = handler.handle
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return pool, nil
+	}
+	ctx := context.Background()
+
+	db := NewPostgresDatabase(config)
+	assert.Nil(db.Connect(ctx))
+	defer db.Disconnect(ctx)
+
+	res := db.Execute(ctx, queryImpl{sqlCode: "DELETE FROM players"})
+	assert.Nil(res.Err())
+	res = db.Execute(ctx, queryImpl{sqlCode: "DELETE FROM players WHERE level = 1", verbose: true})
+	assert.Nil(res.Err())
+	explainHookOf(db).wait()
+
+	assert.Equal([]string{"DELETE FROM players", "DELETE FROM players WHERE level = 1"}, pool.sqlExecuteReceived)
+	assert.Equal([]string{"EXPLAIN DELETE FROM players WHERE level = 1"}, pool.tx.sqlQueriesReceived)
+	assert.Equal(1, len(handler.plans))
+	assert.Equal(2, len(hook.after))
+	assert.Equal([]QueryHook{hook}, config.Hooks)
+}
+
+func TestPostgresDatabase_Explain_Transaction(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := newTestExplainedPool()
+	handler := &mockQueryPlanHandler{}
+	config := testConfig
+	config.DbExplainVerboseQueries = true
+	config.DbExplainHandler = handler.handle
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return pool, nil
+	}
+	ctx := context.Background()
+
+	db := NewPostgresDatabase(config)
+	assert.Nil(db.Connect(ctx))
+	defer db.Disconnect(ctx)
+
+	tx, err := db.Begin(ctx)
+	assert.Nil(err)
+	rows := tx.Query(ctx, queryImpl{sqlCode: "SELECT id FROM players FOR UPDATE", verbose: true})
+	assert.Nil(rows.Err())
+	rows.Close()
+	assert.Nil(tx.Commit(ctx))
+	explainHookOf(db).wait()
+
+	assert.Equal([]string{"SELECT id FROM players FOR UPDATE"}, pool.tx.sqlQueriesReceived)
+	assert.Empty(handler.plans)
+}
+
+func TestPostgresDatabase_Explain_Disconnect(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := newTestExplainedPool()
+	pool.tag = "DELETE 1"
+	pool.beginDelay = 20 * time.Millisecond
+	handler := &mockQueryPlanHandler{}
+	config := testConfig
+	config.DbExplainVerboseQueries = true
+	config.DbExplainHandler = handler.handle
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return pool, nil
+	}
+	ctx := context.Background()
+
+	db := NewPostgresDatabase(config)
+	assert.Nil(db.Connect(ctx))
+
+	res := db.Execute(ctx, queryImpl{sqlCode: "DELETE FROM players", verbose: true})
+	assert.Nil(res.Err())
+	assert.Nil(db.Disconnect(ctx))
+
+	assert.Equal(1, len(handler.plans))
+	assert.Equal(int32(1), pool.closeCalled.Load())
+}
+
+func explainHookOf(db Database) *explainHook {
+	return db.(*postgresDb).explain
+}
+
+type mockPlanRows struct {
+	mockSqlRows
+	lines []string
+}
+
+func (m *mockPlanRows) Scan(dest ...interface{}) error {
+	*(dest[0].(*string)) = m.lines[m.count-1]
+	return nil
+}
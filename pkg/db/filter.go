@@ -3,10 +3,12 @@ package db
 type Filter interface {
 	Valid() bool
 	ToSql() string
+	Args() []interface{}
 }
 
 type filterImpl struct {
 	sqlCode string
+	args    []interface{}
 }
 
 func (f filterImpl) Valid() bool {
@@ -16,3 +18,7 @@ func (f filterImpl) Valid() bool {
 func (f filterImpl) ToSql() string {
 	return f.sqlCode
 }
+
+func (f filterImpl) Args() []interface{} {
+	return f.args
+}
@@ -25,3 +25,13 @@ func TestFilter_ToSql(t *testing.T) {
 	f.sqlCode = "someSqlCode"
 	assert.Equal("someSqlCode", f.ToSql())
 }
+
+func TestFilter_Args(t *testing.T) {
+	assert := assert.New(t)
+
+	f := filterImpl{}
+	assert.Nil(f.Args())
+
+	f.args = []interface{}{"value"}
+	assert.Equal([]interface{}{"value"}, f.Args())
+}
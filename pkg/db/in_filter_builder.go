@@ -2,8 +2,6 @@ package db
 
 import (
 	"fmt"
-	"strings"
-	"time"
 
 	"github.com/KnoblauchPilze/go-game/pkg/errors"
 )
@@ -51,30 +49,18 @@ func (b *inFilterBuilder) Build() (Filter, error) {
 		return filterImpl{}, errors.WrapCode(errors.NewCode(errors.ErrNoValuesInSqlComparison), errors.ErrSqlTranslationFailed)
 	}
 
-	valuesAsStr := b.valuesToStr()
+	var args sqlArgs
+	valuesAsStr, err := args.addAll(b.values)
+	if err != nil {
+		return filterImpl{}, errors.WrapCode(err, errors.ErrSqlTranslationFailed)
+	}
+
 	sqlFilter := fmt.Sprintf("%s in (%s)", b.key, valuesAsStr)
 
 	filter := filterImpl{
 		sqlCode: sqlFilter,
+		args:    args.values,
 	}
 
 	return filter, nil
 }
-
-func (b *inFilterBuilder) valuesToStr() string {
-	values := make([]string, 0, len(b.values))
-	for _, value := range b.values {
-		valueStr := valueToStr(value)
-		values = append(values, fmt.Sprintf("'%s'", valueStr))
-	}
-
-	return strings.Join(values, ", ")
-}
-
-func valueToStr(value interface{}) string {
-	if t, ok := value.(time.Time); ok {
-		return fmt.Sprintf("%v", t.Format(time.RFC3339))
-	}
-
-	return fmt.Sprintf("%v", value)
-}
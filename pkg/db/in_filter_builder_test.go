@@ -65,7 +65,8 @@ func TestInFilterBuilder_Build_StringValue(t *testing.T) {
 	filter, err := b.Build()
 	assert.Nil(err)
 	assert.True(filter.Valid())
-	assert.Equal("key in ('value')", filter.ToSql())
+	assert.Equal("key in ($1)", filter.ToSql())
+	assert.Equal([]interface{}{"value"}, filter.Args())
 }
 
 func TestInFilterBuilder_Build_TimeValue(t *testing.T) {
@@ -79,7 +80,8 @@ func TestInFilterBuilder_Build_TimeValue(t *testing.T) {
 	filter, err := b.Build()
 	assert.Nil(err)
 	assert.True(filter.Valid())
-	assert.Equal("key in ('2009-11-17T20:34:58Z')", filter.ToSql())
+	assert.Equal("key in ($1)", filter.ToSql())
+	assert.Equal([]interface{}{someTime}, filter.Args())
 }
 
 func TestInFilterBuilder_Build_MultiArgs(t *testing.T) {
@@ -93,5 +95,6 @@ func TestInFilterBuilder_Build_MultiArgs(t *testing.T) {
 	filter, err := b.Build()
 	assert.Nil(err)
 	assert.True(filter.Valid())
-	assert.Equal("key in ('value1', 'value2')", filter.ToSql())
+	assert.Equal("key in ($1, $2)", filter.ToSql())
+	assert.Equal([]interface{}{"value1", "value2"}, filter.Args())
 }
@@ -12,14 +12,26 @@ type InsertQueryBuilder interface {
 
 	SetTable(table string) error
 	AddElement(column string, value interface{}) error
+	SetOnConflictColumns(columns ...string) error
+	SetOnConflictConstraint(constraint string) error
+	SetOnConflictDoNothing()
+	AddOnConflictUpdate(column string, value interface{}) error
+	AddOnConflictUpdateFromExcluded(column string) error
+	AddReturning(column string) error
+	AddReturningUpsertStatus() error
+	ReturnsUpsertStatus() bool
 	SetVerbose(verbose bool)
 }
 
 type insertQueryBuilder struct {
-	columns map[string]bool
-	props   []sqlProp
-	table   string
-	verbose bool
+	columns   map[string]bool
+	props     []sqlProp
+	table     string
+	conflict  conflictClause
+	returning returningClause
+	// Set when the status of the upsert is returned, see ExecuteUpsert.
+	upsertStatus bool
+	verbose      bool
 }
 
 func NewInsertQueryBuilder() InsertQueryBuilder {
@@ -55,6 +67,52 @@ func (b *insertQueryBuilder) AddElement(column string, value interface{}) error
 	return nil
 }
 
+func (b *insertQueryBuilder) SetOnConflictColumns(columns ...string) error {
+	return b.conflict.setColumns(columns)
+}
+
+func (b *insertQueryBuilder) SetOnConflictConstraint(constraint string) error {
+	return b.conflict.setConstraint(constraint)
+}
+
+func (b *insertQueryBuilder) SetOnConflictDoNothing() {
+	b.conflict.doNothing = true
+}
+
+func (b *insertQueryBuilder) AddOnConflictUpdate(column string, value interface{}) error {
+	update := conflictUpdate{
+		column: column,
+		value:  value,
+	}
+	return b.conflict.addUpdate(update)
+}
+
+func (b *insertQueryBuilder) AddOnConflictUpdateFromExcluded(column string) error {
+	update := conflictUpdate{
+		column:   column,
+		excluded: true,
+	}
+	return b.conflict.addUpdate(update)
+}
+
+func (b *insertQueryBuilder) AddReturning(column string) error {
+	return b.returning.add(column)
+}
+
+func (b *insertQueryBuilder) AddReturningUpsertStatus() error {
+	if err := b.returning.add(UpsertStatusColumn); err != nil {
+		return err
+	}
+
+	b.upsertStatus = true
+	return nil
+}
+
+// Any other returned column would be read in place of the status.
+func (b *insertQueryBuilder) ReturnsUpsertStatus() bool {
+	return b.upsertStatus && len(b.returning.columns) == 1
+}
+
 func (b *insertQueryBuilder) SetVerbose(verbose bool) {
 	b.verbose = verbose
 }
@@ -68,7 +126,8 @@ func (b *insertQueryBuilder) Build() (Query, error) {
 	}
 
 	columnsAsStr := b.columnsToStr()
-	valuesAsStr, err := b.valuesToStr()
+	var args sqlArgs
+	valuesAsStr, err := b.valuesToStr(&args)
 	if err != nil {
 		return queryImpl{}, errors.WrapCode(err, errors.ErrSqlTranslationFailed)
 	}
@@ -76,8 +135,17 @@ func (b *insertQueryBuilder) Build() (Query, error) {
 	// https://www.w3schools.com/sql/sql_insert.asp
 	sqlQuery := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", b.table, columnsAsStr, valuesAsStr)
 
+	conflict, err := b.conflict.toSql(&args)
+	if err != nil {
+		return queryImpl{}, errors.WrapCode(err, errors.ErrSqlTranslationFailed)
+	}
+
+	sqlQuery += conflict
+	sqlQuery += b.returning.toSql()
+
 	query := queryImpl{
 		sqlCode: sqlQuery,
+		args:    args.values,
 		verbose: b.verbose,
 	}
 
@@ -94,16 +162,16 @@ func (b *insertQueryBuilder) columnsToStr() string {
 	return strings.Join(columns, ", ")
 }
 
-func (b *insertQueryBuilder) valuesToStr() (string, error) {
+func (b *insertQueryBuilder) valuesToStr(args *sqlArgs) (string, error) {
 	var values []string
 
 	for _, prop := range b.props {
-		arg, err := argToStr(prop.value)
+		placeholder, err := args.add(prop.value)
 		if err != nil {
 			return "", err
 		}
 
-		values = append(values, fmt.Sprintf("'%s'", arg))
+		values = append(values, placeholder)
 	}
 
 	return strings.Join(values, ", "), nil
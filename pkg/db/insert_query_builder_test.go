@@ -85,7 +85,8 @@ func TestInsertQueryBuilder_Build(t *testing.T) {
 	query, err := b.Build()
 	assert.Nil(err)
 	assert.True(query.Valid())
-	assert.Equal("INSERT INTO table (column) VALUES ('prop')", query.ToSql())
+	assert.Equal("INSERT INTO table (column) VALUES ($1)", query.ToSql())
+	assert.Equal([]interface{}{"prop"}, query.Args())
 }
 
 func TestInsertQueryBuilder_Build_MultiColumns(t *testing.T) {
@@ -99,7 +100,8 @@ func TestInsertQueryBuilder_Build_MultiColumns(t *testing.T) {
 	query, err := b.Build()
 	assert.Nil(err)
 	assert.True(query.Valid())
-	assert.Equal("INSERT INTO table (column1, column2) VALUES ('prop1', 'prop2')", query.ToSql())
+	assert.Equal("INSERT INTO table (column1, column2) VALUES ($1, $2)", query.ToSql())
+	assert.Equal([]interface{}{"prop1", "prop2"}, query.Args())
 }
 
 func TestInsertQueryBuilder_Build_ArgWithError(t *testing.T) {
@@ -114,3 +116,185 @@ func TestInsertQueryBuilder_Build_ArgWithError(t *testing.T) {
 	cause := errors.Unwrap(err)
 	assert.True(strings.Contains(cause.Error(), errDefault.Error()))
 }
+
+func TestInsertQueryBuilder_AddReturning(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewInsertQueryBuilder()
+
+	err := b.AddReturning("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlColumn))
+
+	err = b.AddReturning("id")
+	assert.Nil(err)
+
+	err = b.AddReturning("id")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDuplicatedSqlColumn))
+}
+
+func TestInsertQueryBuilder_ReturnsUpsertStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewInsertQueryBuilder()
+	assert.False(b.ReturnsUpsertStatus())
+
+	err := b.AddReturningUpsertStatus()
+	assert.Nil(err)
+	assert.True(b.ReturnsUpsertStatus())
+
+	err = b.AddReturningUpsertStatus()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDuplicatedSqlColumn))
+
+	b.AddReturning("id")
+	assert.False(b.ReturnsUpsertStatus())
+}
+
+func TestInsertQueryBuilder_ReturnsUpsertStatus_Column(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewInsertQueryBuilder()
+	b.AddReturning(UpsertStatusColumn)
+	assert.False(b.ReturnsUpsertStatus())
+}
+
+func TestInsertQueryBuilder_Build_WithReturning(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewInsertQueryBuilder()
+	b.SetTable("table")
+	b.AddElement("column", "prop")
+	b.AddReturning("id")
+	b.AddReturning("created_at")
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("INSERT INTO table (column) VALUES ($1) RETURNING id, created_at", query.ToSql())
+	assert.Equal([]interface{}{"prop"}, query.Args())
+}
+
+func TestInsertQueryBuilder_SetOnConflictColumns(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewInsertQueryBuilder()
+
+	err := b.SetOnConflictColumns()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlConflictTarget))
+
+	err = b.SetOnConflictColumns("mail", "")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlConflictTarget))
+
+	err = b.SetOnConflictColumns("mail")
+	assert.Nil(err)
+}
+
+func TestInsertQueryBuilder_SetOnConflictConstraint(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewInsertQueryBuilder()
+
+	err := b.SetOnConflictConstraint("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlConflictTarget))
+
+	err = b.SetOnConflictConstraint("users_mail_key")
+	assert.Nil(err)
+}
+
+func TestInsertQueryBuilder_AddOnConflictUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewInsertQueryBuilder()
+
+	err := b.AddOnConflictUpdate("", "value")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlColumn))
+
+	err = b.AddOnConflictUpdate("name", "value")
+	assert.Nil(err)
+
+	err = b.AddOnConflictUpdateFromExcluded("name")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDuplicatedSqlColumn))
+}
+
+func TestInsertQueryBuilder_Build_OnConflictDoNothing(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewInsertQueryBuilder()
+	b.SetTable("table")
+	b.AddElement("column", "prop")
+	b.SetOnConflictDoNothing()
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("INSERT INTO table (column) VALUES ($1) ON CONFLICT DO NOTHING", query.ToSql())
+
+	b.SetOnConflictColumns("column")
+	query, err = b.Build()
+	assert.Nil(err)
+	assert.Equal("INSERT INTO table (column) VALUES ($1) ON CONFLICT (column) DO NOTHING", query.ToSql())
+}
+
+func TestInsertQueryBuilder_Build_OnConflictDoUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewInsertQueryBuilder()
+	b.SetTable("table")
+	b.AddElement("column", "prop")
+	b.AddElement("other", 12)
+	b.SetOnConflictColumns("column")
+	b.AddOnConflictUpdateFromExcluded("other")
+	b.AddOnConflictUpdate("updated", "value")
+	b.AddReturningUpsertStatus()
+
+	query, err := b.Build()
+	assert.Nil(err)
+	expected := "INSERT INTO table (column, other) VALUES ($1, $2) ON CONFLICT (column) DO UPDATE SET other = EXCLUDED.other, updated = $3 RETURNING (xmax = 0) AS inserted"
+	assert.Equal(expected, query.ToSql())
+	assert.Equal([]interface{}{"prop", 12, "value"}, query.Args())
+}
+
+func TestInsertQueryBuilder_Build_OnConflictConstraint(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewInsertQueryBuilder()
+	b.SetTable("table")
+	b.AddElement("column", "prop")
+	b.SetOnConflictConstraint("table_column_key")
+	b.AddOnConflictUpdateFromExcluded("column")
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("INSERT INTO table (column) VALUES ($1) ON CONFLICT ON CONSTRAINT table_column_key DO UPDATE SET column = EXCLUDED.column", query.ToSql())
+}
+
+func TestInsertQueryBuilder_Build_OnConflictUpdateWithoutTarget(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewInsertQueryBuilder()
+	b.SetTable("table")
+	b.AddElement("column", "prop")
+	b.AddOnConflictUpdateFromExcluded("column")
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrInvalidSqlConflictTarget))
+}
+
+func TestInsertQueryBuilder_Build_OnConflictWithoutAction(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewInsertQueryBuilder()
+	b.SetTable("table")
+	b.AddElement("column", "prop")
+	b.SetOnConflictColumns("column")
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrInvalidSqlConflictAction))
+
+	b.SetOnConflictDoNothing()
+	b.AddOnConflictUpdateFromExcluded("column")
+	_, err = b.Build()
+	cause = errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrInvalidSqlConflictAction))
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+type JoinType int
+
+const (
+	InnerJoin JoinType = iota
+	LeftJoin
+	RightJoin
+)
+
+// https://www.postgresql.org/docs/current/queries-table-expressions.html#QUERIES-JOIN
+var joinTypesToSql = map[JoinType]string{
+	InnerJoin: "INNER JOIN",
+	LeftJoin:  "LEFT JOIN",
+	RightJoin: "RIGHT JOIN",
+}
+
+type Join struct {
+	Type  JoinType
+	Table string
+	Alias string
+	On    Filter
+}
+
+func QualifiedColumn(table string, column string) string {
+	return fmt.Sprintf("%s.%s", table, column)
+}
+
+func (j Join) valid() error {
+	if _, ok := joinTypesToSql[j.Type]; !ok {
+		return errors.NewCode(errors.ErrInvalidSqlJoin)
+	}
+	if len(j.Table) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlTable)
+	}
+	if j.On == nil || !j.On.Valid() {
+		return errors.NewCode(errors.ErrInvalidSqlFilter)
+	}
+
+	return nil
+}
+
+func (j Join) name() string {
+	if len(j.Alias) > 0 {
+		return j.Alias
+	}
+	return j.Table
+}
+
+func (j Join) toSql(args *sqlArgs) string {
+	return fmt.Sprintf("%s %s ON %s", joinTypesToSql[j.Type], tableToSql(j.Table, j.Alias), args.addFilter(j.On))
+}
+
+func tableToSql(table string, alias string) string {
+	if len(alias) == 0 {
+		return table
+	}
+	return fmt.Sprintf("%s AS %s", table, alias)
+}
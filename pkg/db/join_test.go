@@ -0,0 +1,62 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQualifiedColumn(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("u.id", QualifiedColumn("u", "id"))
+}
+
+func TestJoin_Valid(t *testing.T) {
+	assert := assert.New(t)
+
+	on := filterImpl{sqlCode: "u.id = p.user_id"}
+
+	j := Join{Type: JoinType(5), Table: "profiles", On: on}
+	assert.True(errors.IsErrorWithCode(j.valid(), errors.ErrInvalidSqlJoin))
+
+	j = Join{On: on}
+	assert.True(errors.IsErrorWithCode(j.valid(), errors.ErrInvalidSqlTable))
+
+	j = Join{Table: "profiles"}
+	assert.True(errors.IsErrorWithCode(j.valid(), errors.ErrInvalidSqlFilter))
+
+	j = Join{Table: "profiles", On: filterImpl{}}
+	assert.True(errors.IsErrorWithCode(j.valid(), errors.ErrInvalidSqlFilter))
+
+	j = Join{Type: LeftJoin, Table: "profiles", On: on}
+	assert.Nil(j.valid())
+}
+
+func TestJoin_Name(t *testing.T) {
+	assert := assert.New(t)
+
+	j := Join{Table: "profiles"}
+	assert.Equal("profiles", j.name())
+
+	j.Alias = "p"
+	assert.Equal("p", j.name())
+}
+
+func TestJoin_ToSql(t *testing.T) {
+	assert := assert.New(t)
+
+	var args sqlArgs
+	args.add("first")
+
+	j := Join{
+		Type:  RightJoin,
+		Table: "profiles",
+		Alias: "p",
+		On:    filterImpl{sqlCode: "p.kind = $1", args: []interface{}{"second"}},
+	}
+
+	assert.Equal("RIGHT JOIN profiles AS p ON p.kind = $2", j.toSql(&args))
+	assert.Equal([]interface{}{"first", "second"}, args.values)
+}
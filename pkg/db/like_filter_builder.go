@@ -0,0 +1,78 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+type LikeFilterBuilder interface {
+	FilterBuilder
+
+	SetKey(key string) error
+	SetPattern(pattern string) error
+	SetCaseInsensitive(caseInsensitive bool)
+	SetNegated(negated bool)
+}
+
+type likeFilterBuilder struct {
+	key             string
+	pattern         string
+	caseInsensitive bool
+	negated         bool
+}
+
+func NewLikeFilterBuilder() LikeFilterBuilder {
+	return &likeFilterBuilder{}
+}
+
+func (b *likeFilterBuilder) SetKey(key string) error {
+	if len(key) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlComparisonKey)
+	}
+
+	b.key = key
+	return nil
+}
+
+func (b *likeFilterBuilder) SetPattern(pattern string) error {
+	if len(pattern) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlComparisonValue)
+	}
+
+	b.pattern = pattern
+	return nil
+}
+
+func (b *likeFilterBuilder) SetCaseInsensitive(caseInsensitive bool) {
+	b.caseInsensitive = caseInsensitive
+}
+
+func (b *likeFilterBuilder) SetNegated(negated bool) {
+	b.negated = negated
+}
+
+func (b *likeFilterBuilder) Build() (Filter, error) {
+	if len(b.key) == 0 {
+		return filterImpl{}, errors.WrapCode(errors.NewCode(errors.ErrInvalidSqlComparisonKey), errors.ErrSqlTranslationFailed)
+	}
+	if len(b.pattern) == 0 {
+		return filterImpl{}, errors.WrapCode(errors.NewCode(errors.ErrNoValuesInSqlComparison), errors.ErrSqlTranslationFailed)
+	}
+
+	// https://www.postgresql.org/docs/current/functions-matching.html#FUNCTIONS-LIKE
+	operator := "LIKE"
+	if b.caseInsensitive {
+		operator = "ILIKE"
+	}
+	if b.negated {
+		operator = "NOT " + operator
+	}
+
+	filter := filterImpl{
+		sqlCode: fmt.Sprintf("%s %s $1", b.key, operator),
+		args:    []interface{}{b.pattern},
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLikeFilterBuilder_SetKey(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewLikeFilterBuilder()
+
+	err := b.SetKey("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlComparisonKey))
+
+	err = b.SetKey("key")
+	assert.Nil(err)
+}
+
+func TestLikeFilterBuilder_SetPattern(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewLikeFilterBuilder()
+
+	err := b.SetPattern("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlComparisonValue))
+
+	err = b.SetPattern("a%")
+	assert.Nil(err)
+}
+
+func TestLikeFilterBuilder_Build_NoKey(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewLikeFilterBuilder()
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrInvalidSqlComparisonKey))
+}
+
+func TestLikeFilterBuilder_Build_NoPattern(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewLikeFilterBuilder()
+	b.SetKey("key")
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrNoValuesInSqlComparison))
+}
+
+func TestLikeFilterBuilder_Build(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewLikeFilterBuilder()
+	b.SetKey("name")
+	b.SetPattern("a%")
+
+	filter, err := b.Build()
+	assert.Nil(err)
+	assert.True(filter.Valid())
+	assert.Equal("name LIKE $1", filter.ToSql())
+	assert.Equal([]interface{}{"a%"}, filter.Args())
+}
+
+func TestLikeFilterBuilder_Build_CaseInsensitive(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewLikeFilterBuilder()
+	b.SetKey("name")
+	b.SetPattern("a%")
+	b.SetCaseInsensitive(true)
+
+	filter, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("name ILIKE $1", filter.ToSql())
+}
+
+func TestLikeFilterBuilder_Build_Negated(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewLikeFilterBuilder()
+	b.SetKey("name")
+	b.SetPattern("a%")
+	b.SetCaseInsensitive(true)
+	b.SetNegated(true)
+
+	filter, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("name NOT ILIKE $1", filter.ToSql())
+}
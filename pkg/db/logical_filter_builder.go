@@ -0,0 +1,59 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+type LogicalFilterBuilder interface {
+	FilterBuilder
+
+	AddFilter(filter Filter) error
+}
+
+type logicalFilterBuilder struct {
+	operator string
+	filters  []Filter
+}
+
+func NewAndFilterBuilder() LogicalFilterBuilder {
+	return &logicalFilterBuilder{
+		operator: "AND",
+	}
+}
+
+func NewOrFilterBuilder() LogicalFilterBuilder {
+	return &logicalFilterBuilder{
+		operator: "OR",
+	}
+}
+
+func (b *logicalFilterBuilder) AddFilter(filter Filter) error {
+	if filter == nil || !filter.Valid() {
+		return errors.NewCode(errors.ErrInvalidSqlFilter)
+	}
+
+	b.filters = append(b.filters, filter)
+	return nil
+}
+
+func (b *logicalFilterBuilder) Build() (Filter, error) {
+	if len(b.filters) == 0 {
+		return filterImpl{}, errors.WrapCode(errors.NewCode(errors.ErrNoFilterInSqlCombination), errors.ErrSqlTranslationFailed)
+	}
+
+	var args sqlArgs
+	filters := make([]string, 0, len(b.filters))
+	for _, filter := range b.filters {
+		filters = append(filters, fmt.Sprintf("(%s)", args.addFilter(filter)))
+	}
+
+	filter := filterImpl{
+		sqlCode: strings.Join(filters, fmt.Sprintf(" %s ", b.operator)),
+		args:    args.values,
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogicalFilterBuilder_AddFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewAndFilterBuilder()
+
+	err := b.AddFilter(nil)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlFilter))
+
+	err = b.AddFilter(filterImpl{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlFilter))
+
+	err = b.AddFilter(filterImpl{sqlCode: "someSqlCode"})
+	assert.Nil(err)
+}
+
+func TestLogicalFilterBuilder_Build_NoFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewOrFilterBuilder()
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrNoFilterInSqlCombination))
+}
+
+func TestLogicalFilterBuilder_Build_And(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewAndFilterBuilder()
+	b.AddFilter(filterImpl{sqlCode: "key = $1", args: []interface{}{"value"}})
+	b.AddFilter(filterImpl{sqlCode: "other BETWEEN $1 AND $2", args: []interface{}{1, 2}})
+
+	filter, err := b.Build()
+	assert.Nil(err)
+	assert.True(filter.Valid())
+	assert.Equal("(key = $1) AND (other BETWEEN $2 AND $3)", filter.ToSql())
+	assert.Equal([]interface{}{"value", 1, 2}, filter.Args())
+}
+
+func TestLogicalFilterBuilder_Build_Or(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewOrFilterBuilder()
+	b.AddFilter(filterImpl{sqlCode: "key = $1", args: []interface{}{"value"}})
+	b.AddFilter(filterImpl{sqlCode: "mail IS NULL"})
+
+	filter, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("(key = $1) OR (mail IS NULL)", filter.ToSql())
+	assert.Equal([]interface{}{"value"}, filter.Args())
+}
+
+func TestLogicalFilterBuilder_Build_Nested(t *testing.T) {
+	assert := assert.New(t)
+
+	or := NewOrFilterBuilder()
+	or.AddFilter(filterImpl{sqlCode: "a = $1", args: []interface{}{1}})
+	or.AddFilter(filterImpl{sqlCode: "b = $1", args: []interface{}{2}})
+	orFilter, err := or.Build()
+	assert.Nil(err)
+
+	and := NewAndFilterBuilder()
+	and.AddFilter(filterImpl{sqlCode: "c = $1", args: []interface{}{3}})
+	and.AddFilter(orFilter)
+
+	filter, err := and.Build()
+	assert.Nil(err)
+	assert.Equal("(c = $1) AND ((a = $2) OR (b = $3))", filter.ToSql())
+	assert.Equal([]interface{}{3, 1, 2}, filter.Args())
+}
+
+func TestLogicalFilterBuilder_Build_InUpdateQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	fb := NewAndFilterBuilder()
+	fb.AddFilter(filterImpl{sqlCode: "created_at > $1", args: []interface{}{"2023-01-01"}})
+	fb.AddFilter(filterImpl{sqlCode: "name LIKE $1", args: []interface{}{"a%"}})
+	f, err := fb.Build()
+	assert.Nil(err)
+
+	qb := NewUpdateQueryBuilder()
+	qb.SetTable("users")
+	qb.AddUpdate("name", "b")
+	qb.SetFilter(f)
+
+	query, err := qb.Build()
+	assert.Nil(err)
+	assert.Equal("UPDATE users SET name = $1 WHERE (created_at > $2) AND (name LIKE $3)", query.ToSql())
+	assert.Equal([]interface{}{"b", "2023-01-01", "a%"}, query.Args())
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"github.com/KnoblauchPilze/go-game/pkg/db"
+	"github.com/jackc/pgx"
+)
+
+// Replaces the pgx pool and listener of the postgres database: an
+// invalid schema is reported when connecting.
+type memoryDriver struct {
+	engine *memoryEngine
+	err    error
+}
+
+// The in-memory database understands the SQL generated by the query
+// builders of the db package and is meant for tests and local runs. It
+// reuses the connection, timeout and transaction logic of the postgres
+// implementation: only the pgx pool is replaced. The data survives a
+// disconnection and is lost when the database is garbage collected.
+// The notifications sent with pg_notify are delivered in process.
+func NewDatabase(conf db.Config, tables ...Table) db.Database {
+	engine, err := newMemoryEngine(tables)
+
+	return db.NewDatabaseWithDriver(conf, &memoryDriver{engine: engine, err: err})
+}
+
+func (d *memoryDriver) Open(config pgx.ConnPoolConfig) (db.DriverPool, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return &memoryDbFacade{engine: d.engine}, nil
+}
+
+func (d *memoryDriver) Listen(config pgx.ConnConfig) (db.DriverListener, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return d.engine.listeners.newListener(), nil
+}
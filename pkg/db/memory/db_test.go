@@ -0,0 +1,390 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/db"
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var errDefault = fmt.Errorf("someError")
+
+type memoryTestPlayer struct {
+	Id        uuid.UUID  `db:"id,omitempty"`
+	Name      string     `db:"name"`
+	Level     int        `db:"level"`
+	CreatedAt *time.Time `db:"created_at,readonly"`
+}
+
+type memoryTestNamesParser struct {
+	names []string
+}
+
+func (p *memoryTestNamesParser) ScanRow(row db.Scannable) error {
+	var name string
+	if err := row.Scan(&name); err != nil {
+		return err
+	}
+
+	p.names = append(p.names, name)
+	return nil
+}
+
+func newTestMemoryDatabase(t *testing.T) db.Database {
+	database := NewDatabase(db.NewConfig(), memoryTestTables...)
+	assert.Nil(t, database.Connect(context.Background()))
+	t.Cleanup(func() { database.Disconnect(context.Background()) })
+	return database
+}
+
+func newMemoryTestQuery(t *testing.T, sql string, args ...interface{}) db.Query {
+	qb := db.NewRawQueryBuilder()
+	assert.Nil(t, qb.SetSql(sql))
+	for _, arg := range args {
+		qb.AddArg(arg)
+	}
+
+	query, err := qb.Build()
+	assert.Nil(t, err)
+	return query
+}
+
+func insertTestPlayer(t *testing.T, qe db.QueryExecutor, name string, level int) {
+	qb := db.NewInsertQueryBuilder()
+	qb.SetTable("players")
+	qb.AddElement("name", name)
+	qb.AddElement("level", level)
+
+	assert.Nil(t, qe.ExecuteQueryAffectingSingleRow(context.Background(), qb))
+}
+
+func selectTestPlayerNames(t *testing.T, qe db.QueryExecutor) []string {
+	qb := db.NewSelectQueryBuilder()
+	qb.SetTable("players")
+	qb.AddProp("name")
+	qb.AddOrderBy(db.OrderBy{Column: "name"})
+
+	parser := &memoryTestNamesParser{}
+	assert.Nil(t, qe.RunQueryAndScanAllResults(context.Background(), qb, parser))
+	return parser.names
+}
+
+func TestMemoryDatabase_InvalidSchema(t *testing.T) {
+	assert := assert.New(t)
+
+	database := NewDatabase(db.NewConfig(), Table{Name: "t"})
+	err := database.Connect(context.Background())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbConnectionFailed))
+}
+
+func TestMemoryDatabase_NotConnected(t *testing.T) {
+	assert := assert.New(t)
+
+	database := NewDatabase(db.NewConfig(), memoryTestTables...)
+	qe := db.NewQueryExecutor(database)
+
+	qb := db.NewSelectQueryBuilder()
+	qb.SetTable("players")
+	qb.AddProp("name")
+	err := qe.RunQueryAndScanAllResults(context.Background(), qb, &memoryTestNamesParser{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbConnectionInvalid))
+}
+
+func TestMemoryDatabase_DataSurvivesReconnection(t *testing.T) {
+	assert := assert.New(t)
+
+	database := newTestMemoryDatabase(t)
+	qe := db.NewQueryExecutor(database)
+	insertTestPlayer(t, qe, "alice", 1)
+
+	assert.Nil(database.Disconnect(context.Background()))
+	assert.Nil(database.Connect(context.Background()))
+
+	assert.Equal([]string{"alice"}, selectTestPlayerNames(t, qe))
+}
+
+func TestMemoryDatabase_QueryBuilders(t *testing.T) {
+	assert := assert.New(t)
+
+	database := newTestMemoryDatabase(t)
+	qe := db.NewQueryExecutor(database)
+	insertTestPlayer(t, qe, "alice", 1)
+	insertTestPlayer(t, qe, "bob", 2)
+	insertTestPlayer(t, qe, "carol", 3)
+
+	ub := db.NewUpdateQueryBuilder()
+	ub.SetTable("players")
+	ub.AddUpdate("level", 10)
+	fb := db.NewComparisonFilterBuilder()
+	fb.SetKey("level")
+	fb.SetOperator(db.GreaterThanOrEqual)
+	fb.SetValue(2)
+	f, err := fb.Build()
+	assert.Nil(err)
+	ub.SetFilter(f)
+	affected, err := qe.ExecuteQueryAffectingAnyRows(context.Background(), ub)
+	assert.Nil(err)
+	assert.Equal(2, affected)
+
+	cursor, err := db.NewCursor("alice")
+	assert.Nil(err)
+	sb := db.NewSelectQueryBuilder()
+	sb.SetTable("players")
+	sb.AddProp("name")
+	sb.AddOrderBy(db.OrderBy{Column: "name"})
+	sb.SetCursor(cursor)
+	sb.SetLimit(1)
+	parser := &memoryTestNamesParser{}
+	assert.Nil(qe.RunQueryAndScanAllResults(context.Background(), sb, parser))
+	assert.Equal([]string{"bob"}, parser.names)
+
+	dqb := db.NewDeleteQueryBuilder()
+	dqb.SetTable("players")
+	dqb.SetFilter(f)
+	affected, err = qe.ExecuteQueryAffectingAtLeastOneRow(context.Background(), dqb)
+	assert.Nil(err)
+	assert.Equal(2, affected)
+
+	assert.Equal([]string{"alice"}, selectTestPlayerNames(t, qe))
+}
+
+func TestMemoryDatabase_RunQueryAndIterate(t *testing.T) {
+	assert := assert.New(t)
+
+	database := newTestMemoryDatabase(t)
+	qe := db.NewQueryExecutor(database)
+	insertTestPlayer(t, qe, "b", 2)
+	insertTestPlayer(t, qe, "a", 1)
+
+	qb := db.NewSelectQueryBuilder()
+	qb.SetTable("players")
+	qb.AddProp("name")
+	qb.AddOrderBy(db.OrderBy{Column: "name"})
+
+	rows, err := qe.RunQueryAndIterate(context.Background(), qb)
+	assert.Nil(err)
+	defer rows.Close()
+
+	parser := &memoryTestNamesParser{}
+	for rows.Next() {
+		assert.Nil(rows.Scan(parser))
+	}
+	assert.Nil(rows.Err())
+	assert.Equal([]string{"a", "b"}, parser.names)
+}
+
+func TestMemoryDatabase_Upsert(t *testing.T) {
+	assert := assert.New(t)
+
+	database := newTestMemoryDatabase(t)
+	qe := db.NewQueryExecutor(database)
+
+	upsert := func(name string, level int) db.UpsertStatus {
+		qb := db.NewInsertQueryBuilder()
+		qb.SetTable("players")
+		qb.AddElement("name", name)
+		qb.AddElement("level", level)
+		qb.SetOnConflictColumns("name")
+		qb.AddOnConflictUpdateFromExcluded("level")
+		qb.AddReturningUpsertStatus()
+
+		status, err := qe.ExecuteUpsert(context.Background(), qb)
+		assert.Nil(err)
+		return status
+	}
+
+	assert.Equal(db.UpsertInserted, upsert("alice", 1))
+	assert.Equal(db.UpsertUpdated, upsert("alice", 2))
+}
+
+func TestMemoryDatabase_CopyFrom(t *testing.T) {
+	assert := assert.New(t)
+
+	database := newTestMemoryDatabase(t)
+	qe := db.NewQueryExecutor(database)
+
+	bb := db.NewBulkInsertQueryBuilder()
+	bb.SetTable("players")
+	bb.SetColumns("name", "level")
+	bb.AddRow("alice", 1)
+	bb.AddRow("bob", 2)
+
+	copied, err := qe.ExecuteCopyFrom(context.Background(), bb)
+	assert.Nil(err)
+	assert.Equal(2, copied)
+
+	assert.Equal([]string{"alice", "bob"}, selectTestPlayerNames(t, qe))
+}
+
+func TestMemoryDatabase_UniqueViolation(t *testing.T) {
+	assert := assert.New(t)
+
+	database := newTestMemoryDatabase(t)
+	qe := db.NewQueryExecutor(database)
+	insertTestPlayer(t, qe, "alice", 1)
+
+	qb := db.NewInsertQueryBuilder()
+	qb.SetTable("players")
+	qb.AddElement("name", "alice")
+	err := qe.ExecuteQueryAffectingSingleRow(context.Background(), qb)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbUniqueViolation))
+
+	violation, ok := db.ViolatedConstraint(err)
+	assert.True(ok)
+	assert.Equal(db.ConstraintViolation{Table: "players", Constraint: "players_name_key"}, violation)
+}
+
+func TestMemoryDatabase_WithTransaction(t *testing.T) {
+	assert := assert.New(t)
+
+	database := newTestMemoryDatabase(t)
+	qe := db.NewQueryExecutor(database)
+
+	err := qe.WithTransaction(context.Background(), func(tx db.QueryExecutor) error {
+		insertTestPlayer(t, tx, "alice", 1)
+
+		nestedErr := tx.WithTransaction(context.Background(), func(nested db.QueryExecutor) error {
+			insertTestPlayer(t, nested, "bob", 2)
+			return errDefault
+		})
+		assert.Equal(errDefault, nestedErr)
+
+		return tx.WithTransaction(context.Background(), func(nested db.QueryExecutor) error {
+			insertTestPlayer(t, nested, "carol", 3)
+			return nil
+		})
+	})
+	assert.Nil(err)
+	assert.Equal([]string{"alice", "carol"}, selectTestPlayerNames(t, qe))
+
+	err = qe.WithTransaction(context.Background(), func(tx db.QueryExecutor) error {
+		insertTestPlayer(t, tx, "dave", 4)
+		return errDefault
+	})
+	assert.Equal(errDefault, err)
+	assert.Equal([]string{"alice", "carol"}, selectTestPlayerNames(t, qe))
+}
+
+func TestMemoryDatabase_TransactionIsolation(t *testing.T) {
+	assert := assert.New(t)
+
+	database := newTestMemoryDatabase(t)
+	qe := db.NewQueryExecutor(database)
+	insertTestPlayer(t, qe, "alice", 1)
+
+	err := qe.WithTransaction(context.Background(), func(tx db.QueryExecutor) error {
+		insertTestPlayer(t, tx, "bob", 2)
+		assert.Equal([]string{"alice", "bob"}, selectTestPlayerNames(t, tx))
+		assert.Equal([]string{"alice"}, selectTestPlayerNames(t, qe))
+
+		insertTestPlayer(t, qe, "carol", 3)
+		assert.Equal([]string{"alice", "bob"}, selectTestPlayerNames(t, tx))
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal([]string{"alice", "bob", "carol"}, selectTestPlayerNames(t, qe))
+
+	err = qe.WithTransaction(context.Background(), func(tx db.QueryExecutor) error {
+		insertTestPlayer(t, tx, "dave", 4)
+		insertTestPlayer(t, qe, "dave", 5)
+		return nil
+	})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbTransactionCommitFailed))
+	assert.Equal([]string{"alice", "bob", "carol", "dave"}, selectTestPlayerNames(t, qe))
+}
+
+func TestMemoryDatabase_ClosedTransaction(t *testing.T) {
+	assert := assert.New(t)
+
+	database := newTestMemoryDatabase(t)
+	tx, err := database.Begin(context.Background())
+	assert.Nil(err)
+	assert.Nil(tx.Commit(context.Background()))
+
+	// The transaction wrapper refuses the calls before they reach the
+	// facade so the facade is checked directly.
+	facade := &memoryTxFacade{engine: &memoryEngine{}}
+	assert.Nil(facade.Rollback(context.Background()))
+	assert.NotNil(facade.Commit(context.Background()))
+	_, err = facade.Exec(context.Background(), "SELECT 1")
+	assert.NotNil(err)
+	_, err = facade.Query(context.Background(), "SELECT 1")
+	assert.NotNil(err)
+}
+
+func TestMemoryDatabase_CancelledContext(t *testing.T) {
+	assert := assert.New(t)
+
+	database := newTestMemoryDatabase(t)
+	qe := db.NewQueryExecutor(database)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	qb := db.NewInsertQueryBuilder()
+	qb.SetTable("players")
+	qb.AddElement("name", "alice")
+	err := qe.ExecuteQueryAffectingSingleRow(ctx, qb)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbRequestFailed))
+
+	assert.Empty(selectTestPlayerNames(t, qe))
+}
+
+func TestMemoryDatabase_StructMapper(t *testing.T) {
+	assert := assert.New(t)
+
+	qe := db.NewQueryExecutor(newTestMemoryDatabase(t))
+	m := db.MustNewStructMapper[memoryTestPlayer]()
+
+	ib := db.NewInsertQueryBuilder()
+	ib.SetTable("players")
+	assert.Nil(m.AddElements(ib, memoryTestPlayer{Name: "name", Level: 2}))
+	assert.Nil(m.AddReturning(ib))
+	created := m.NewParser()
+	assert.Nil(qe.ExecuteQueryAndScanReturnedRow(context.Background(), ib, created))
+	assert.NotEqual(uuid.Nil, created.Value().Id)
+	assert.Equal("name", created.Value().Name)
+
+	insertTestPlayer(t, qe, "other", 1)
+
+	sb := db.NewSelectQueryBuilder()
+	sb.SetTable("players")
+	assert.Nil(m.AddProps(sb))
+	sb.AddOrderBy(db.OrderBy{Column: "name"})
+	all := m.NewParser()
+	assert.Nil(qe.RunQueryAndScanAllResults(context.Background(), sb, all))
+	assert.Equal(2, len(all.Values()))
+	assert.Equal(created.Value(), all.Values()[0])
+	assert.Equal("other", all.Values()[1].Name)
+
+	sb = db.NewSelectQueryBuilder()
+	sb.SetTable("players")
+	sb.AddProp("name")
+	err := qe.RunQueryAndScanAllResults(context.Background(), sb, m.NewParser())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbCorruptedData))
+	cause := errors.Unwrap(errors.Unwrap(err))
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrMismatchedSqlColumns))
+	assert.Contains(cause.Error(), "missing column(s) [id level created_at])")
+}
+
+// The memory database has no connections to prepare the statements on.
+func TestMemoryDatabase_StatementCache(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := db.NewConfig()
+	conf.DbStatementCacheSize = 10
+	database := NewDatabase(conf, memoryTestTables...)
+	assert.Nil(database.Connect(context.Background()))
+	qe := db.NewQueryExecutor(database)
+
+	insertTestPlayer(t, qe, "alice", 1)
+	insertTestPlayer(t, qe, "bob", 2)
+	assert.Equal([]string{"alice", "bob"}, selectTestPlayerNames(t, qe))
+
+	assert.Equal(db.StatementCacheStats{}, database.(db.StatementCacheReporter).StatementCacheStats())
+}
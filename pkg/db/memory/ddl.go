@@ -0,0 +1,352 @@
+package memory
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/db"
+	"github.com/google/uuid"
+)
+
+// ParseSchema returns the tables created by the scripts, typically
+// the up scripts of the migrations in order. Only the CREATE TABLE and
+// DROP TABLE statements are interpreted: the others (functions, indices,
+// settings...) do not change the columns and are skipped. The names of
+// the constraints are not kept: the violations report the ones postgres
+// generates by default.
+func ParseSchema(scripts ...string) ([]Table, error) {
+	var tables []Table
+
+	for _, script := range scripts {
+		for _, statement := range db.SplitSqlStatements(script) {
+			words := strings.Fields(strings.ToUpper(statement))
+			if len(words) < 2 || words[1] != "TABLE" {
+				continue
+			}
+
+			var err error
+			switch words[0] {
+			case "CREATE":
+				tables, err = createMemoryTable(tables, statement)
+			case "DROP":
+				tables, err = dropMemoryTables(tables, statement)
+			case "ALTER":
+				err = memoryErrorf(memoryFeatureNotSupported, "ALTER TABLE is not supported by the in-memory database")
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return tables, nil
+}
+
+func createMemoryTable(tables []Table, statement string) ([]Table, error) {
+	p, err := newMemoryDdlParser(statement, "CREATE", "TABLE")
+	if err != nil {
+		return nil, err
+	}
+
+	ifNotExists := false
+	if p.acceptKeyword("IF") {
+		if err := p.expectKeywords("NOT", "EXISTS"); err != nil {
+			return nil, err
+		}
+		ifNotExists = true
+	}
+
+	table := Table{}
+	if table.Name, err = p.parseTableName(); err != nil {
+		return nil, err
+	}
+	if findMemoryTable(tables, table.Name) >= 0 {
+		if ifNotExists {
+			return tables, nil
+		}
+		return nil, memoryErrorf(memoryDuplicateTable, "relation %q already exists", table.Name)
+	}
+
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	for {
+		if err := p.parseTableElement(&table); err != nil {
+			return nil, err
+		}
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+
+	return append(tables, table), p.expectEnd()
+}
+
+func dropMemoryTables(tables []Table, statement string) ([]Table, error) {
+	p, err := newMemoryDdlParser(statement, "DROP", "TABLE")
+	if err != nil {
+		return nil, err
+	}
+
+	ifExists := false
+	if p.acceptKeyword("IF") {
+		if err := p.expectKeyword("EXISTS"); err != nil {
+			return nil, err
+		}
+		ifExists = true
+	}
+
+	for {
+		name, err := p.parseTableName()
+		if err != nil {
+			return nil, err
+		}
+
+		index := findMemoryTable(tables, name)
+		if index >= 0 {
+			tables = append(tables[:index:index], tables[index+1:]...)
+		} else if !ifExists {
+			return nil, memoryErrorf(memoryUndefinedTable, "table %q does not exist", name)
+		}
+
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	if !p.acceptKeyword("CASCADE") {
+		p.acceptKeyword("RESTRICT")
+	}
+
+	return tables, p.expectEnd()
+}
+
+func findMemoryTable(tables []Table, name string) int {
+	for id, table := range tables {
+		if table.Name == name {
+			return id
+		}
+	}
+
+	return -1
+}
+
+func newMemoryDdlParser(statement string, keywords ...string) (*memoryParser, error) {
+	tokens, err := tokenizeMemorySql(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &memoryParser{tokens: tokens}
+	return p, p.expectKeywords(keywords...)
+}
+
+func (p *memoryParser) expectKeywords(keywords ...string) error {
+	for _, keyword := range keywords {
+		if err := p.expectKeyword(keyword); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *memoryParser) expectEnd() error {
+	p.acceptSymbol(";")
+	if p.peek().kind != memoryEof {
+		return p.unexpected()
+	}
+
+	return nil
+}
+
+// The schema is dropped from qualified names: the in-memory database
+// only has one.
+func (p *memoryParser) parseTableName() (string, error) {
+	name, err := p.expectIdent()
+	if err != nil {
+		return "", err
+	}
+	if p.acceptSymbol(".") {
+		return p.expectIdent()
+	}
+
+	return name, nil
+}
+
+// https://www.postgresql.org/docs/current/sql-createtable.html
+func (p *memoryParser) parseTableElement(table *Table) error {
+	if p.acceptKeyword("CONSTRAINT") {
+		if _, err := p.expectIdent(); err != nil {
+			return err
+		}
+		return p.parseTableConstraint(table)
+	}
+	if p.peekKeyword("PRIMARY") || p.peekKeyword("UNIQUE") || p.peekKeyword("FOREIGN") || p.peekKeyword("CHECK") {
+		return p.parseTableConstraint(table)
+	}
+
+	column := Column{}
+	var err error
+	if column.Name, err = p.expectIdent(); err != nil {
+		return err
+	}
+	if column.Type, column.Default, err = p.parseColumnType(); err != nil {
+		return err
+	}
+
+	for !p.peekSymbol(",") && !p.peekSymbol(")") && p.peek().kind != memoryEof {
+		if err := p.parseColumnConstraint(&column); err != nil {
+			return err
+		}
+	}
+
+	table.Columns = append(table.Columns, column)
+	return nil
+}
+
+func (p *memoryParser) parseTableConstraint(table *Table) error {
+	primaryKey := p.acceptKeyword("PRIMARY")
+	if primaryKey {
+		if err := p.expectKeyword("KEY"); err != nil {
+			return err
+		}
+	} else if !p.acceptKeyword("UNIQUE") {
+		return memoryErrorf(memoryFeatureNotSupported, "constraint near %q is not supported by the in-memory database", p.peek().text)
+	}
+
+	columns, err := p.parseIdentList()
+	if err != nil {
+		return err
+	}
+	if len(columns) != 1 {
+		return memoryErrorf(memoryFeatureNotSupported, "only single column constraints are supported by the in-memory database")
+	}
+
+	for id := range table.Columns {
+		if table.Columns[id].Name != columns[0] {
+			continue
+		}
+
+		if primaryKey {
+			table.Columns[id].PrimaryKey = true
+		} else {
+			table.Columns[id].Unique = true
+		}
+		return nil
+	}
+
+	return memoryErrorf(memoryUndefinedColumn, "column %q named in key does not exist", columns[0])
+}
+
+func (p *memoryParser) parseColumnConstraint(column *Column) error {
+	switch {
+	case p.acceptKeyword("CONSTRAINT"):
+		_, err := p.expectIdent()
+		return err
+	case p.acceptKeyword("NOT"):
+		column.NotNull = true
+		return p.expectKeyword("NULL")
+	case p.acceptKeyword("NULL"):
+		return nil
+	case p.acceptKeyword("PRIMARY"):
+		column.PrimaryKey = true
+		return p.expectKeyword("KEY")
+	case p.acceptKeyword("UNIQUE"):
+		column.Unique = true
+		return nil
+	case p.acceptKeyword("DEFAULT"):
+		var err error
+		column.Default, err = p.parseColumnDefault()
+		return err
+	default:
+		return memoryErrorf(memoryFeatureNotSupported, "column constraint near %q is not supported by the in-memory database", p.peek().text)
+	}
+}
+
+// The type modifiers such as the length of a varchar are ignored. The
+// serial types come with the default generating their values.
+// https://www.postgresql.org/docs/current/datatype.html
+func (p *memoryParser) parseColumnType() (ColumnType, func() interface{}, error) {
+	tok := p.next()
+	if tok.kind != memoryIdent {
+		return 0, nil, p.unexpectedToken(tok)
+	}
+
+	words := []string{strings.ToUpper(tok.text)}
+	for _, next := range []string{"VARYING", "PRECISION", "WITH", "WITHOUT", "TIME", "ZONE"} {
+		if p.acceptKeyword(next) {
+			words = append(words, next)
+		}
+	}
+	if p.acceptSymbol("(") {
+		for !p.acceptSymbol(")") {
+			if tok := p.next(); tok.kind == memoryEof {
+				return 0, nil, p.unexpectedToken(tok)
+			}
+		}
+	}
+
+	switch name := strings.Join(words, " "); name {
+	case "TEXT", "VARCHAR", "CHARACTER VARYING", "CHARACTER", "CHAR":
+		return Text, nil, nil
+	case "INTEGER", "INT", "INT2", "INT4", "INT8", "SMALLINT", "BIGINT":
+		return Integer, nil, nil
+	case "SERIAL", "SMALLSERIAL", "BIGSERIAL", "SERIAL4", "SERIAL8":
+		var sequence atomic.Int64
+		return Integer, func() interface{} { return sequence.Add(1) }, nil
+	case "REAL", "FLOAT", "FLOAT4", "FLOAT8", "DOUBLE PRECISION", "NUMERIC", "DECIMAL":
+		return Float, nil, nil
+	case "BOOLEAN", "BOOL":
+		return Boolean, nil, nil
+	case "TIMESTAMP", "TIMESTAMPTZ", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITHOUT TIME ZONE", "DATE":
+		return Timestamp, nil, nil
+	case "UUID":
+		return Uuid, nil, nil
+	default:
+		return 0, nil, memoryErrorf(memoryFeatureNotSupported, "type %q is not supported by the in-memory database", strings.ToLower(name))
+	}
+}
+
+// Only the constants and the functions generating ids and timestamps
+// are supported.
+func (p *memoryParser) parseColumnDefault() (func() interface{}, error) {
+	if p.acceptKeyword("CURRENT_TIMESTAMP") || p.acceptKeyword("LOCALTIMESTAMP") {
+		return func() interface{} { return time.Now() }, nil
+	}
+
+	tok := p.peek()
+	if tok.kind == memoryIdent && p.tokens[p.pos+1].kind == memorySymbol && p.tokens[p.pos+1].text == "(" {
+		p.next()
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+
+		switch strings.ToLower(tok.text) {
+		case "uuid_generate_v4", "gen_random_uuid":
+			return func() interface{} { return uuid.New() }, nil
+		case "now", "transaction_timestamp", "statement_timestamp", "clock_timestamp":
+			return func() interface{} { return time.Now() }, nil
+		default:
+			return nil, memoryErrorf(memoryUndefinedFunction, "function %s() is not supported by the in-memory database", tok.text)
+		}
+	}
+
+	expr, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	literal, ok := expr.(memoryLiteralExpr)
+	if !ok {
+		return nil, memoryErrorf(memoryFeatureNotSupported, "default near %q is not supported by the in-memory database", tok.text)
+	}
+
+	return func() interface{} { return literal.value }, nil
+}
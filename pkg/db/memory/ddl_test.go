@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMemorySchema(t *testing.T) {
+	assert := assert.New(t)
+
+	first := `
+SET client_encoding = 'UTF8';
+CREATE TABLE players (
+  id uuid NOT NULL DEFAULT uuid_generate_v4(),
+  name varchar(64) NOT NULL,
+  level integer DEFAULT 1,
+  score DOUBLE PRECISION,
+  active boolean NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT players_pk PRIMARY KEY (id),
+  UNIQUE (name)
+);
+CREATE TABLE legacy (id serial PRIMARY KEY);`
+	second := `
+DROP TABLE IF EXISTS legacy;
+CREATE TABLE IF NOT EXISTS players (id uuid);
+CREATE TABLE public.scores (player text UNIQUE, points real);`
+
+	tables, err := ParseSchema(first, second)
+	assert.Nil(err)
+	if !assert.Equal(2, len(tables)) {
+		return
+	}
+
+	players := tables[0]
+	assert.Equal("players", players.Name)
+	assert.Equal(6, len(players.Columns))
+
+	id := players.Columns[0]
+	assert.Equal("id", id.Name)
+	assert.Equal(Uuid, id.Type)
+	assert.True(id.PrimaryKey)
+	assert.True(id.NotNull)
+	assert.IsType(uuid.UUID{}, id.Default())
+
+	name := players.Columns[1]
+	assert.Equal(Text, name.Type)
+	assert.True(name.NotNull)
+	assert.True(name.Unique)
+	assert.Nil(name.Default)
+
+	level := players.Columns[2]
+	assert.Equal(Integer, level.Type)
+	assert.Equal(int64(1), level.Default())
+
+	assert.Equal(Float, players.Columns[3].Type)
+	assert.Equal(Boolean, players.Columns[4].Type)
+	assert.False(players.Columns[4].NotNull)
+
+	createdAt := players.Columns[5]
+	assert.Equal(Timestamp, createdAt.Type)
+	assert.IsType(time.Time{}, createdAt.Default())
+
+	scores := tables[1]
+	assert.Equal("scores", scores.Name)
+	assert.Equal([]Column{
+		{Name: "player", Type: Text, Unique: true},
+		{Name: "points", Type: Float},
+	}, scores.Columns)
+}
+
+func TestParseMemorySchema_Serial(t *testing.T) {
+	assert := assert.New(t)
+
+	tables, err := ParseSchema("CREATE TABLE t (id bigserial PRIMARY KEY)")
+	assert.Nil(err)
+
+	id := tables[0].Columns[0]
+	assert.Equal(Integer, id.Type)
+	assert.Equal(int64(1), id.Default())
+	assert.Equal(int64(2), id.Default())
+}
+
+func TestParseMemorySchema_Errors(t *testing.T) {
+	_, err := ParseSchema("CREATE TABLE t (id uuid); CREATE TABLE t (id uuid)")
+	assertMemoryError(t, err, memoryDuplicateTable)
+
+	_, err = ParseSchema("DROP TABLE t")
+	assertMemoryError(t, err, memoryUndefinedTable)
+
+	_, err = ParseSchema("CREATE TABLE t (id uuid); ALTER TABLE t ADD COLUMN name text")
+	assertMemoryError(t, err, memoryFeatureNotSupported)
+
+	_, err = ParseSchema("CREATE TABLE t (id int, other int, PRIMARY KEY (id, other))")
+	assertMemoryError(t, err, memoryFeatureNotSupported)
+
+	_, err = ParseSchema("CREATE TABLE t (id int, UNIQUE (missing))")
+	assertMemoryError(t, err, memoryUndefinedColumn)
+
+	_, err = ParseSchema("CREATE TABLE t (id int REFERENCES other (id))")
+	assertMemoryError(t, err, memoryFeatureNotSupported)
+
+	_, err = ParseSchema("CREATE TABLE t (id int, FOREIGN KEY (id) REFERENCES other (id))")
+	assertMemoryError(t, err, memoryFeatureNotSupported)
+
+	_, err = ParseSchema("CREATE TABLE t (data jsonb)")
+	assertMemoryError(t, err, memoryFeatureNotSupported)
+
+	_, err = ParseSchema("CREATE TABLE t (id text DEFAULT md5())")
+	assertMemoryError(t, err, memoryUndefinedFunction)
+
+	_, err = ParseSchema("CREATE TABLE t (id int DEFAULT other)")
+	assertMemoryError(t, err, memoryFeatureNotSupported)
+
+	_, err = ParseSchema("CREATE TABLE t (id int")
+	assertMemoryError(t, err, memorySyntaxError)
+
+	_, err = ParseSchema("CREATE TABLE t (id int) extra")
+	assertMemoryError(t, err, memorySyntaxError)
+}
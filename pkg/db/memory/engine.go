@@ -0,0 +1,1296 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx"
+)
+
+type memoryRow struct {
+	values []interface{}
+	// Zero for rows created by an insert and non-zero for rows
+	// updated by an ON CONFLICT clause: see UpsertStatusColumn.
+	xmax int64
+}
+
+type memoryTableData struct {
+	schema  Table
+	columns map[string]int
+	rows    []*memoryRow
+	// Incremented on each change: this tells whether the table changed
+	// since a transaction took its snapshot.
+	version int
+	// The row each updated row replaced, only set for the snapshots: the
+	// updated rows take the place of the original ones on commit.
+	replaced map[*memoryRow]*memoryRow
+}
+
+type memoryResult struct {
+	tag     pgx.CommandTag
+	columns []string
+	rows    [][]interface{}
+}
+
+type memorySavepointMark struct {
+	name          string
+	mark          int
+	notifications int
+}
+
+// Transactions record how to revert each change they make so that
+// savepoints can be rolled back: rows are never modified in place so
+// reverting only swaps pointers back. The notifications are held until
+// the transaction commits.
+type memoryUndoLog struct {
+	entries       []func()
+	savepoints    []memorySavepointMark
+	notifications []*pgx.Notification
+}
+
+// Statements are atomic and serialized by the lock. A transaction works
+// on a snapshot of the tables taken when it begins: its changes are not
+// visible to the other connections until it commits. Committing merges
+// the rows it changed with the ones changed by the other connections in
+// the meantime and only fails when both changed the same row.
+type memoryEngine struct {
+	lock      sync.Mutex
+	tables    map[string]*memoryTableData
+	listeners *memoryBroker
+	// The versions and the rows of the tables when the snapshot was
+	// taken, only set for the snapshots.
+	versions map[string]int
+	origins  map[string][]*memoryRow
+}
+
+func newMemoryEngine(tables []Table) (*memoryEngine, error) {
+	e := memoryEngine{
+		tables:    make(map[string]*memoryTableData),
+		listeners: newMemoryBroker(),
+	}
+
+	for _, table := range tables {
+		if len(table.Name) == 0 || len(table.Columns) == 0 {
+			return nil, fmt.Errorf("invalid in-memory table %q", table.Name)
+		}
+		if _, ok := e.tables[table.Name]; ok {
+			return nil, fmt.Errorf("duplicated in-memory table %q", table.Name)
+		}
+
+		data := &memoryTableData{
+			schema:  table,
+			columns: make(map[string]int),
+		}
+		for id, column := range table.Columns {
+			if _, ok := data.columns[column.Name]; ok || len(column.Name) == 0 {
+				return nil, fmt.Errorf("invalid column %q in in-memory table %q", column.Name, table.Name)
+			}
+			data.columns[column.Name] = id
+		}
+
+		e.tables[table.Name] = data
+	}
+
+	return &e, nil
+}
+
+func (e *memoryEngine) execute(sql string, args []interface{}, log *memoryUndoLog) (memoryResult, error) {
+	stmt, err := parseMemorySql(sql)
+	if err != nil {
+		return memoryResult{}, err
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if savepoint, ok := stmt.(memorySavepoint); ok {
+		return memoryResult{tag: savepointCommandTag(savepoint)}, log.handleSavepoint(savepoint)
+	}
+	if notify, ok := stmt.(memoryNotify); ok {
+		return e.notify(notify, args, log)
+	}
+
+	var undo []func()
+	res, err := e.dispatch(stmt, args, &undo)
+	if err != nil {
+		revertMemoryChanges(undo)
+		return memoryResult{}, err
+	}
+
+	if log != nil {
+		log.entries = append(log.entries, undo...)
+	}
+
+	return res, nil
+}
+
+func (e *memoryEngine) copyFrom(table string, columns []string, rows [][]interface{}, log *memoryUndoLog) (int, error) {
+	stmt := memoryInsert{
+		table:   table,
+		columns: columns,
+	}
+	for _, row := range rows {
+		if len(row) != len(columns) {
+			return 0, memoryErrorf(memorySyntaxError, "expected %d values in copied row but got %d", len(columns), len(row))
+		}
+
+		values := make([]memoryExpr, 0, len(row))
+		for _, value := range row {
+			values = append(values, memoryLiteralExpr{value: normalizeMemoryValue(value)})
+		}
+		stmt.rows = append(stmt.rows, values)
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	var undo []func()
+	if _, err := e.insert(stmt, nil, &undo); err != nil {
+		revertMemoryChanges(undo)
+		return 0, err
+	}
+
+	if log != nil {
+		log.entries = append(log.entries, undo...)
+	}
+
+	return len(stmt.rows), nil
+}
+
+// The snapshot shares the rows of the engine: they are never modified
+// in place.
+func (e *memoryEngine) snapshot() *memoryEngine {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	snapshot := &memoryEngine{
+		tables:    make(map[string]*memoryTableData, len(e.tables)),
+		listeners: e.listeners,
+		versions:  make(map[string]int, len(e.tables)),
+		origins:   make(map[string][]*memoryRow, len(e.tables)),
+	}
+	for name, table := range e.tables {
+		snapshot.tables[name] = &memoryTableData{
+			schema:   table.schema,
+			columns:  table.columns,
+			rows:     append([]*memoryRow(nil), table.rows...),
+			version:  table.version,
+			replaced: make(map[*memoryRow]*memoryRow),
+		}
+		snapshot.versions[name] = table.version
+		snapshot.origins[name] = append([]*memoryRow(nil), table.rows...)
+	}
+
+	return snapshot
+}
+
+// Unlike postgres in READ COMMITTED the statements of the transaction
+// are not applied again on the latest version of the rows it changed:
+// committing fails with a serialization error when one of them was
+// changed by someone else in the meantime.
+// https://www.postgresql.org/docs/current/transaction-iso.html#XACT-READ-COMMITTED
+func (e *memoryEngine) commit(snapshot *memoryEngine, log *memoryUndoLog) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	merged := make(map[string][]*memoryRow)
+	for name, table := range snapshot.tables {
+		if table.version == snapshot.versions[name] {
+			continue
+		}
+
+		current := e.tables[name]
+		if current.version == snapshot.versions[name] {
+			merged[name] = table.rows
+			continue
+		}
+
+		rows, err := mergeMemoryRows(current, snapshot.origins[name], table)
+		if err != nil {
+			return err
+		}
+		merged[name] = rows
+	}
+
+	for name, rows := range merged {
+		table := e.tables[name]
+		table.rows = rows
+		table.version++
+	}
+
+	for _, notification := range log.notifications {
+		e.listeners.publish(notification)
+	}
+	log.notifications = nil
+
+	return nil
+}
+
+// The rows are never modified in place so the changes of a transaction
+// are found by comparing the pointers of the rows it started from with
+// the ones it ends with: a row of the origin missing from the changed
+// rows was deleted unless an updated row replaced it.
+func mergeMemoryRows(current *memoryTableData, origin []*memoryRow, changed *memoryTableData) ([]*memoryRow, error) {
+	original := make(map[*memoryRow]bool, len(origin))
+	for _, row := range origin {
+		original[row] = true
+	}
+
+	replaced := make(map[*memoryRow]*memoryRow)
+	var inserted []*memoryRow
+	for _, row := range changed.rows {
+		if original[row] {
+			delete(original, row)
+			continue
+		}
+
+		old := changed.replaced[row]
+		for old != nil && !original[old] {
+			old = changed.replaced[old]
+		}
+		if old == nil {
+			inserted = append(inserted, row)
+			continue
+		}
+		replaced[old] = row
+		delete(original, old)
+	}
+	for row := range original {
+		replaced[row] = nil
+	}
+
+	present := make(map[*memoryRow]bool, len(current.rows))
+	for _, row := range current.rows {
+		present[row] = true
+	}
+	for row := range replaced {
+		if !present[row] {
+			return nil, memoryErrorf(memorySerializationFailure, "could not serialize access due to concurrent update of %q", current.schema.Name)
+		}
+	}
+
+	rows := make([]*memoryRow, 0, len(current.rows)+len(inserted))
+	for _, row := range current.rows {
+		replacement, ok := replaced[row]
+		if !ok {
+			rows = append(rows, row)
+		} else if replacement != nil {
+			rows = append(rows, replacement)
+		}
+	}
+	rows = append(rows, inserted...)
+
+	// The rows committed in the meantime may hold the same unique
+	// values as the ones of the transaction.
+	merged := &memoryTableData{schema: current.schema, rows: rows}
+	if err := merged.checkUniqueAfter(nil); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// https://www.postgresql.org/docs/current/functions-info.html#FUNCTIONS-INFO-SESSION
+func (e *memoryEngine) notify(stmt memoryNotify, args []interface{}, log *memoryUndoLog) (memoryResult, error) {
+	env := &memoryEnv{args: args}
+	channel, err := stmt.channel.eval(env)
+	if err != nil {
+		return memoryResult{}, err
+	}
+	payload, err := stmt.payload.eval(env)
+	if err != nil {
+		return memoryResult{}, err
+	}
+
+	if channel == nil || channel == "" {
+		return memoryResult{}, memoryErrorf(memoryInvalidParameterValue, "channel name cannot be empty")
+	}
+	notification := &pgx.Notification{
+		Channel: fmt.Sprint(channel),
+	}
+	if payload != nil {
+		notification.Payload = fmt.Sprint(payload)
+	}
+
+	if log != nil {
+		log.notifications = append(log.notifications, notification)
+	} else {
+		e.listeners.publish(notification)
+	}
+
+	res := memoryResult{
+		tag:     "SELECT 1",
+		columns: []string{"pg_notify"},
+		rows:    [][]interface{}{{nil}},
+	}
+	return res, nil
+}
+
+func (e *memoryEngine) dispatch(stmt memoryStatement, args []interface{}, undo *[]func()) (memoryResult, error) {
+	switch s := stmt.(type) {
+	case memorySelect:
+		return e.selectRows(s, args)
+	case memoryInsert:
+		return e.insert(s, args, undo)
+	case memoryUpdate:
+		return e.update(s, args, undo)
+	case memoryDelete:
+		return e.delete(s, args, undo)
+	default:
+		return memoryResult{}, memoryErrorf(memoryFeatureNotSupported, "unsupported statement")
+	}
+}
+
+func (e *memoryEngine) table(name string) (*memoryTableData, error) {
+	table, ok := e.tables[name]
+	if !ok {
+		return nil, memoryErrorf(memoryUndefinedTable, "relation %q does not exist", name)
+	}
+
+	return table, nil
+}
+
+func (e *memoryEngine) selectRows(stmt memorySelect, args []interface{}) (memoryResult, error) {
+	envs, err := e.sourceEnvs(stmt, args)
+	if err != nil {
+		return memoryResult{}, err
+	}
+	if err := stmt.checkColumns(e); err != nil {
+		return memoryResult{}, err
+	}
+
+	filtered := make([]*memoryEnv, 0, len(envs))
+	for _, env := range envs {
+		ok, err := memoryConditionHolds(stmt.where, env)
+		if err != nil {
+			return memoryResult{}, err
+		}
+		if ok {
+			filtered = append(filtered, env)
+		}
+	}
+
+	if stmt.aggregated() {
+		if filtered, err = e.groupRows(stmt, filtered, args); err != nil {
+			return memoryResult{}, err
+		}
+	}
+
+	type outputRow struct {
+		values []interface{}
+		keys   []interface{}
+	}
+
+	names := outputColumnNames(stmt.items)
+	out := make([]outputRow, 0, len(filtered))
+	for _, env := range filtered {
+		values, err := projectMemoryRow(stmt.items, env)
+		if err != nil {
+			return memoryResult{}, err
+		}
+
+		row := outputRow{values: values}
+		for _, term := range stmt.orderBy {
+			key, err := orderKey(term, names, values, env)
+			if err != nil {
+				return memoryResult{}, err
+			}
+			row.keys = append(row.keys, key)
+		}
+		out = append(out, row)
+	}
+
+	var sortErr error
+	sort.SliceStable(out, func(i, j int) bool {
+		for id, term := range stmt.orderBy {
+			cmp, err := compareOrderKeys(out[i].keys[id], out[j].keys[id], term)
+			if err != nil && sortErr == nil {
+				sortErr = err
+			}
+			if cmp != 0 {
+				return cmp < 0
+			}
+		}
+		return false
+	})
+	if sortErr != nil {
+		return memoryResult{}, sortErr
+	}
+
+	start, end := 0, len(out)
+	if stmt.offset != nil {
+		start = clampMemoryIndex(*stmt.offset, len(out))
+	}
+	if stmt.limit != nil {
+		end = clampMemoryIndex(int64(start)+*stmt.limit, len(out))
+	}
+
+	res := memoryResult{columns: resultColumnNames(stmt.items, stmt.emptyBindings(e))}
+	for _, row := range out[start:end] {
+		res.rows = append(res.rows, row.values)
+	}
+	res.tag = pgx.CommandTag(fmt.Sprintf("SELECT %d", len(res.rows)))
+
+	return res, nil
+}
+
+func (s memorySelect) aggregated() bool {
+	if len(s.groupBy) > 0 || s.having != nil {
+		return true
+	}
+	for _, item := range s.items {
+		if !item.star && containsMemoryAggregate(item.expr) {
+			return true
+		}
+	}
+	for _, term := range s.orderBy {
+		if containsMemoryAggregate(term.expr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s memorySelect) checkColumns(e *memoryEngine) error {
+	env := &memoryEnv{}
+	if s.from != nil {
+		env.bindings = s.emptyBindings(e)
+	}
+
+	exprs := []memoryExpr{s.where, s.having}
+	exprs = append(exprs, s.groupBy...)
+	for _, item := range s.items {
+		exprs = append(exprs, item.expr)
+	}
+	for _, join := range s.joins {
+		exprs = append(exprs, join.on)
+	}
+
+	names := outputColumnNames(s.items)
+	for _, term := range s.orderBy {
+		if column, ok := term.expr.(memoryColumnExpr); ok && len(column.table) == 0 && containsString(names, column.name) {
+			continue
+		}
+		exprs = append(exprs, term.expr)
+	}
+
+	return checkMemoryColumns(env, exprs...)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *memoryEngine) sourceEnvs(stmt memorySelect, args []interface{}) ([]*memoryEnv, error) {
+	if stmt.from == nil {
+		return []*memoryEnv{{args: args}}, nil
+	}
+
+	from, err := e.table(stmt.from.name)
+	if err != nil {
+		return nil, err
+	}
+
+	var envs []*memoryEnv
+	for _, row := range from.rows {
+		envs = append(envs, &memoryEnv{
+			bindings: []memoryBinding{{alias: stmt.from.alias, table: from, row: row}},
+			args:     args,
+		})
+	}
+
+	for _, join := range stmt.joins {
+		table, err := e.table(join.table.name)
+		if err != nil {
+			return nil, err
+		}
+		if envs, err = joinMemoryRows(envs, stmt.emptyBindings(e), join, table, args); err != nil {
+			return nil, err
+		}
+	}
+
+	return envs, nil
+}
+
+// Returns the bindings of all the tables of the query null-extended:
+// used for right joins without match on the left and empty groups.
+func (s memorySelect) emptyBindings(e *memoryEngine) []memoryBinding {
+	bindings := []memoryBinding{{alias: s.from.alias, table: e.tables[s.from.name]}}
+	for _, join := range s.joins {
+		bindings = append(bindings, memoryBinding{alias: join.table.alias, table: e.tables[join.table.name]})
+	}
+
+	return bindings
+}
+
+func joinMemoryRows(envs []*memoryEnv, empty []memoryBinding, join memoryJoin, table *memoryTableData, args []interface{}) ([]*memoryEnv, error) {
+	var out []*memoryEnv
+
+	combine := func(bindings []memoryBinding, row *memoryRow) *memoryEnv {
+		combined := make([]memoryBinding, 0, len(bindings)+1)
+		combined = append(combined, bindings...)
+		combined = append(combined, memoryBinding{alias: join.table.alias, table: table, row: row})
+		return &memoryEnv{bindings: combined, args: args}
+	}
+
+	matchedRight := make(map[*memoryRow]bool)
+	for _, env := range envs {
+		matched := false
+		for _, row := range table.rows {
+			candidate := combine(env.bindings, row)
+			ok, err := memoryConditionHolds(join.on, candidate)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				out = append(out, candidate)
+				matched = true
+				matchedRight[row] = true
+			}
+		}
+
+		if !matched && join.kind == "LEFT" {
+			out = append(out, combine(env.bindings, nil))
+		}
+	}
+
+	if join.kind == "RIGHT" {
+		left := make([]memoryBinding, 0, len(empty))
+		for _, binding := range empty {
+			if binding.alias == join.table.alias {
+				break
+			}
+			left = append(left, binding)
+		}
+
+		for _, row := range table.rows {
+			if !matchedRight[row] {
+				out = append(out, combine(left, row))
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func (e *memoryEngine) groupRows(stmt memorySelect, envs []*memoryEnv, args []interface{}) ([]*memoryEnv, error) {
+	var keys []string
+	groups := make(map[string][]*memoryEnv)
+
+	for _, env := range envs {
+		var parts []string
+		for _, expr := range stmt.groupBy {
+			value, err := expr.eval(env)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, memoryValueKey(value))
+		}
+
+		key := strings.Join(parts, "\x00")
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], env)
+	}
+
+	// Without GROUP BY the aggregates are computed over a single group
+	// even when no rows match.
+	if len(stmt.groupBy) == 0 && len(keys) == 0 {
+		keys = append(keys, "")
+		groups[""] = []*memoryEnv{}
+	}
+
+	var out []*memoryEnv
+	for _, key := range keys {
+		rows := groups[key]
+
+		env := &memoryEnv{args: args, group: rows}
+		if len(rows) > 0 {
+			env.bindings = rows[0].bindings
+		} else if stmt.from != nil {
+			env.bindings = stmt.emptyBindings(e)
+		}
+
+		ok, err := memoryConditionHolds(stmt.having, env)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, env)
+		}
+	}
+
+	return out, nil
+}
+
+func outputColumnNames(items []memorySelectItem) []string {
+	var names []string
+	for _, item := range items {
+		name := item.alias
+		if column, ok := item.expr.(memoryColumnExpr); ok && len(name) == 0 {
+			name = column.name
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// Mirrors the names postgres gives to the columns of a result: stars
+// are expanded and unnamed expressions are called '?column?'.
+func resultColumnNames(items []memorySelectItem, bindings []memoryBinding) []string {
+	var names []string
+	for _, item := range items {
+		switch expr := item.expr.(type) {
+		case nil:
+		case memoryColumnExpr:
+			if len(item.alias) == 0 {
+				names = append(names, expr.name)
+				continue
+			}
+		case memoryAggregateExpr:
+			if len(item.alias) == 0 {
+				names = append(names, strings.ToLower(expr.function))
+				continue
+			}
+		}
+
+		if !item.star {
+			name := item.alias
+			if len(name) == 0 {
+				name = "?column?"
+			}
+			names = append(names, name)
+			continue
+		}
+
+		for _, binding := range bindings {
+			if len(item.starTable) > 0 && binding.alias != item.starTable {
+				continue
+			}
+			for _, column := range binding.table.schema.Columns {
+				names = append(names, column.Name)
+			}
+		}
+	}
+
+	return names
+}
+
+func projectMemoryRow(items []memorySelectItem, env *memoryEnv) ([]interface{}, error) {
+	var values []interface{}
+
+	for _, item := range items {
+		if !item.star {
+			value, err := item.expr.eval(env)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			continue
+		}
+
+		found := false
+		for _, binding := range env.bindings {
+			if len(item.starTable) > 0 && binding.alias != item.starTable {
+				continue
+			}
+
+			found = true
+			if binding.row == nil {
+				values = append(values, make([]interface{}, len(binding.table.schema.Columns))...)
+			} else {
+				values = append(values, binding.row.values...)
+			}
+		}
+		if !found && len(item.starTable) > 0 {
+			return nil, memoryErrorf(memoryUndefinedTable, "missing FROM-clause entry for table %q", item.starTable)
+		}
+	}
+
+	return values, nil
+}
+
+// Bare names in the ORDER BY clause refer to output columns first.
+// https://www.postgresql.org/docs/current/queries-order.html
+func orderKey(term memoryOrderTerm, names []string, values []interface{}, env *memoryEnv) (interface{}, error) {
+	if column, ok := term.expr.(memoryColumnExpr); ok && len(column.table) == 0 {
+		for id, name := range names {
+			if name == column.name && id < len(values) {
+				return values[id], nil
+			}
+		}
+	}
+
+	return term.expr.eval(env)
+}
+
+func compareOrderKeys(lhs interface{}, rhs interface{}, term memoryOrderTerm) (int, error) {
+	nullsFirst := term.desc
+	if term.nullsFirst != nil {
+		nullsFirst = *term.nullsFirst
+	}
+
+	switch {
+	case lhs == nil && rhs == nil:
+		return 0, nil
+	case lhs == nil:
+		if nullsFirst {
+			return -1, nil
+		}
+		return 1, nil
+	case rhs == nil:
+		if nullsFirst {
+			return 1, nil
+		}
+		return -1, nil
+	}
+
+	cmp, err := compareMemoryValues(lhs, rhs)
+	if term.desc {
+		cmp = -cmp
+	}
+	return cmp, err
+}
+
+func clampMemoryIndex(index int64, size int) int {
+	if index < 0 {
+		return 0
+	}
+	if index > int64(size) {
+		return size
+	}
+	return int(index)
+}
+
+func (e *memoryEngine) insert(stmt memoryInsert, args []interface{}, undo *[]func()) (memoryResult, error) {
+	table, err := e.table(stmt.table)
+	if err != nil {
+		return memoryResult{}, err
+	}
+
+	indices, err := table.columnIndices(stmt.columns)
+	if err != nil {
+		return memoryResult{}, err
+	}
+	arbiters, err := table.conflictArbiters(stmt.onConflict)
+	if err != nil {
+		return memoryResult{}, err
+	}
+
+	env := &memoryEnv{
+		bindings: []memoryBinding{{alias: stmt.table, table: table}},
+		excluded: &memoryBinding{alias: memoryExcludedTable, table: table},
+	}
+	exprs := returningExprs(stmt.returning)
+	if stmt.onConflict != nil {
+		exprs = append(exprs, assignmentExprs(stmt.onConflict.updates)...)
+	}
+	if err := checkMemoryColumns(env, exprs...); err != nil {
+		return memoryResult{}, err
+	}
+
+	res := memoryResult{columns: resultColumnNames(stmt.returning, env.bindings)}
+	affected := 0
+	touched := make(map[*memoryRow]bool)
+	for _, exprs := range stmt.rows {
+		row, err := table.newRow(exprs, indices, args)
+		if err != nil {
+			return memoryResult{}, err
+		}
+
+		var result *memoryRow
+		existing := table.findConflict(row, arbiters, nil)
+		switch {
+		case existing == nil:
+			if err := table.checkUnique(row, nil); err != nil {
+				return memoryResult{}, err
+			}
+			table.appendRow(row, undo)
+			result = row
+		case stmt.onConflict.doNothing:
+			continue
+		default:
+			if touched[existing] {
+				return memoryResult{}, memoryErrorf(memoryCardinalityViolation, "ON CONFLICT DO UPDATE command cannot affect row a second time")
+			}
+			if result, err = table.upsertRow(existing, row, stmt.onConflict.updates, args, undo); err != nil {
+				return memoryResult{}, err
+			}
+		}
+
+		touched[result] = true
+		affected++
+		if len(stmt.returning) > 0 {
+			values, err := table.returning(stmt.table, stmt.returning, result, args)
+			if err != nil {
+				return memoryResult{}, err
+			}
+			res.rows = append(res.rows, values)
+		}
+	}
+
+	// https://www.postgresql.org/docs/current/sql-insert.html#id-1.9.3.152.7
+	res.tag = pgx.CommandTag(fmt.Sprintf("INSERT 0 %d", affected))
+	return res, nil
+}
+
+func (e *memoryEngine) update(stmt memoryUpdate, args []interface{}, undo *[]func()) (memoryResult, error) {
+	table, err := e.table(stmt.table.name)
+	if err != nil {
+		return memoryResult{}, err
+	}
+
+	env := &memoryEnv{bindings: []memoryBinding{{alias: stmt.table.alias, table: table}}}
+	exprs := append(returningExprs(stmt.returning), stmt.where)
+	if err := checkMemoryColumns(env, append(exprs, assignmentExprs(stmt.updates)...)...); err != nil {
+		return memoryResult{}, err
+	}
+
+	replacements := make(map[*memoryRow]*memoryRow)
+	var updated []*memoryRow
+	var previous []*memoryRow
+	for _, row := range table.rows {
+		env := &memoryEnv{
+			bindings: []memoryBinding{{alias: stmt.table.alias, table: table, row: row}},
+			args:     args,
+		}
+
+		ok, err := memoryConditionHolds(stmt.where, env)
+		if err != nil {
+			return memoryResult{}, err
+		}
+		if !ok {
+			continue
+		}
+
+		newRow, err := table.applyAssignments(row, stmt.updates, env)
+		if err != nil {
+			return memoryResult{}, err
+		}
+
+		replacements[row] = newRow
+		previous = append(previous, row)
+		updated = append(updated, newRow)
+	}
+
+	if err := table.checkUniqueAfter(replacements); err != nil {
+		return memoryResult{}, err
+	}
+
+	res := memoryResult{columns: resultColumnNames(stmt.returning, env.bindings)}
+	for id, row := range updated {
+		table.replaceRow(previous[id], row, undo)
+
+		if len(stmt.returning) > 0 {
+			values, err := table.returning(stmt.table.alias, stmt.returning, row, args)
+			if err != nil {
+				return memoryResult{}, err
+			}
+			res.rows = append(res.rows, values)
+		}
+	}
+
+	res.tag = pgx.CommandTag(fmt.Sprintf("UPDATE %d", len(updated)))
+	return res, nil
+}
+
+func (e *memoryEngine) delete(stmt memoryDelete, args []interface{}, undo *[]func()) (memoryResult, error) {
+	table, err := e.table(stmt.table.name)
+	if err != nil {
+		return memoryResult{}, err
+	}
+
+	env := &memoryEnv{bindings: []memoryBinding{{alias: stmt.table.alias, table: table}}}
+	if err := checkMemoryColumns(env, append(returningExprs(stmt.returning), stmt.where)...); err != nil {
+		return memoryResult{}, err
+	}
+
+	var deleted []*memoryRow
+	for _, row := range table.rows {
+		env := &memoryEnv{
+			bindings: []memoryBinding{{alias: stmt.table.alias, table: table, row: row}},
+			args:     args,
+		}
+
+		ok, err := memoryConditionHolds(stmt.where, env)
+		if err != nil {
+			return memoryResult{}, err
+		}
+		if ok {
+			deleted = append(deleted, row)
+		}
+	}
+
+	res := memoryResult{columns: resultColumnNames(stmt.returning, env.bindings)}
+	for _, row := range deleted {
+		if len(stmt.returning) > 0 {
+			values, err := table.returning(stmt.table.alias, stmt.returning, row, args)
+			if err != nil {
+				return memoryResult{}, err
+			}
+			res.rows = append(res.rows, values)
+		}
+	}
+	for _, row := range deleted {
+		table.removeRow(row, undo)
+	}
+
+	res.tag = pgx.CommandTag(fmt.Sprintf("DELETE %d", len(deleted)))
+	return res, nil
+}
+
+func returningExprs(items []memorySelectItem) []memoryExpr {
+	var exprs []memoryExpr
+	for _, item := range items {
+		exprs = append(exprs, item.expr)
+	}
+
+	return exprs
+}
+
+func assignmentExprs(assignments []memoryAssignment) []memoryExpr {
+	var exprs []memoryExpr
+	for _, assignment := range assignments {
+		exprs = append(exprs, assignment.value)
+	}
+
+	return exprs
+}
+
+func (t *memoryTableData) columnIndices(columns []string) ([]int, error) {
+	indices := make([]int, 0, len(columns))
+	seen := make(map[string]bool)
+
+	for _, column := range columns {
+		index, ok := t.columns[column]
+		if !ok {
+			return nil, memoryErrorf(memoryUndefinedColumn, "column %q of relation %q does not exist", column, t.schema.Name)
+		}
+		if seen[column] {
+			return nil, memoryErrorf(memoryDuplicateColumn, "column %q specified more than once", column)
+		}
+
+		seen[column] = true
+		indices = append(indices, index)
+	}
+
+	return indices, nil
+}
+
+// https://www.postgresql.org/docs/current/sql-insert.html#SQL-ON-CONFLICT
+// Without a conflict target all the unique constraints are arbiters.
+func (t *memoryTableData) conflictArbiters(clause *memoryOnConflict) ([]int, error) {
+	if clause == nil {
+		return nil, nil
+	}
+
+	var arbiters []int
+	switch {
+	case len(clause.constraint) > 0:
+		for id, column := range t.schema.Columns {
+			if column.unique() && column.constraintName(t.schema.Name) == clause.constraint {
+				arbiters = append(arbiters, id)
+			}
+		}
+		if len(arbiters) == 0 {
+			return nil, memoryErrorf(memoryUndefinedObject, "constraint %q for table %q does not exist", clause.constraint, t.schema.Name)
+		}
+	case len(clause.columns) > 0:
+		if len(clause.columns) != 1 {
+			return nil, memoryErrorf(memoryInvalidColumnReference, "there is no unique or exclusion constraint matching the ON CONFLICT specification")
+		}
+		index, ok := t.columns[clause.columns[0]]
+		if !ok {
+			return nil, memoryErrorf(memoryUndefinedColumn, "column %q does not exist", clause.columns[0])
+		}
+		if !t.schema.Columns[index].unique() {
+			return nil, memoryErrorf(memoryInvalidColumnReference, "there is no unique or exclusion constraint matching the ON CONFLICT specification")
+		}
+		arbiters = append(arbiters, index)
+	default:
+		if !clause.doNothing {
+			return nil, memoryErrorf(memorySyntaxError, "ON CONFLICT DO UPDATE requires inference specification or constraint name")
+		}
+		for id, column := range t.schema.Columns {
+			if column.unique() {
+				arbiters = append(arbiters, id)
+			}
+		}
+	}
+
+	return arbiters, nil
+}
+
+func (t *memoryTableData) newRow(exprs []memoryExpr, indices []int, args []interface{}) (*memoryRow, error) {
+	values := make([]interface{}, len(t.schema.Columns))
+	assigned := make([]bool, len(t.schema.Columns))
+
+	env := &memoryEnv{args: args}
+	for id, expr := range exprs {
+		value, err := expr.eval(env)
+		if err != nil {
+			return nil, err
+		}
+
+		values[indices[id]] = value
+		assigned[indices[id]] = true
+	}
+
+	for id, column := range t.schema.Columns {
+		if !assigned[id] && column.Default != nil {
+			values[id] = column.Default()
+		}
+	}
+
+	return t.validateRow(values)
+}
+
+func (t *memoryTableData) validateRow(values []interface{}) (*memoryRow, error) {
+	for id, column := range t.schema.Columns {
+		value, err := coerceMemoryValue(values[id], column.Type)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil && column.notNull() {
+			return nil, memoryNotNullError(t.schema.Name, column.Name)
+		}
+
+		values[id] = value
+	}
+
+	return &memoryRow{values: values}, nil
+}
+
+func (t *memoryTableData) applyAssignments(row *memoryRow, updates []memoryAssignment, env *memoryEnv) (*memoryRow, error) {
+	values := make([]interface{}, len(row.values))
+	copy(values, row.values)
+
+	for _, update := range updates {
+		index, ok := t.columns[update.column]
+		if !ok {
+			return nil, memoryErrorf(memoryUndefinedColumn, "column %q of relation %q does not exist", update.column, t.schema.Name)
+		}
+
+		value, err := update.value.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		values[index] = value
+	}
+
+	return t.validateRow(values)
+}
+
+func (t *memoryTableData) upsertRow(existing *memoryRow, proposed *memoryRow, updates []memoryAssignment, args []interface{}, undo *[]func()) (*memoryRow, error) {
+	env := &memoryEnv{
+		bindings: []memoryBinding{{alias: t.schema.Name, table: t, row: existing}},
+		args:     args,
+		excluded: &memoryBinding{alias: memoryExcludedTable, table: t, row: proposed},
+	}
+
+	row, err := t.applyAssignments(existing, updates, env)
+	if err != nil {
+		return nil, err
+	}
+	row.xmax = 1
+
+	if err := t.checkUnique(row, existing); err != nil {
+		return nil, err
+	}
+
+	t.replaceRow(existing, row, undo)
+	return row, nil
+}
+
+// Returns the first row having the same value as the input row for
+// any of the columns.
+func (t *memoryTableData) findConflict(row *memoryRow, columns []int, ignored *memoryRow) *memoryRow {
+	for _, index := range columns {
+		value := row.values[index]
+		if value == nil {
+			continue
+		}
+
+		for _, existing := range t.rows {
+			if existing == ignored {
+				continue
+			}
+			if cmp, err := compareMemoryValues(existing.values[index], value); err == nil && existing.values[index] != nil && cmp == 0 {
+				return existing
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTableData) checkUnique(row *memoryRow, ignored *memoryRow) error {
+	for id, column := range t.schema.Columns {
+		if column.unique() && t.findConflict(row, []int{id}, ignored) != nil {
+			return memoryUniqueError(t.schema.Name, column, row.values[id])
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTableData) checkUniqueAfter(replacements map[*memoryRow]*memoryRow) error {
+	for id, column := range t.schema.Columns {
+		if !column.unique() {
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for _, row := range t.rows {
+			if replacement, ok := replacements[row]; ok {
+				row = replacement
+			}
+
+			value := row.values[id]
+			if value == nil {
+				continue
+			}
+
+			key := memoryValueKey(value)
+			if seen[key] {
+				return memoryUniqueError(t.schema.Name, column, value)
+			}
+			seen[key] = true
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTableData) returning(alias string, items []memorySelectItem, row *memoryRow, args []interface{}) ([]interface{}, error) {
+	env := &memoryEnv{
+		bindings: []memoryBinding{{alias: alias, table: t, row: row}},
+		args:     args,
+	}
+
+	return projectMemoryRow(items, env)
+}
+
+func (t *memoryTableData) appendRow(row *memoryRow, undo *[]func()) {
+	t.rows = append(t.rows, row)
+	t.version++
+	*undo = append(*undo, func() {
+		t.removeRow(row, nil)
+	})
+}
+
+func (t *memoryTableData) replaceRow(old *memoryRow, row *memoryRow, undo *[]func()) {
+	for id, existing := range t.rows {
+		if existing == old {
+			t.rows[id] = row
+			break
+		}
+	}
+	t.version++
+
+	if undo != nil {
+		if t.replaced != nil {
+			t.replaced[row] = old
+		}
+		*undo = append(*undo, func() {
+			t.replaceRow(row, old, nil)
+		})
+	}
+}
+
+func (t *memoryTableData) removeRow(row *memoryRow, undo *[]func()) {
+	for id, existing := range t.rows {
+		if existing != row {
+			continue
+		}
+
+		t.rows = append(t.rows[:id:id], t.rows[id+1:]...)
+		t.version++
+		if undo != nil {
+			*undo = append(*undo, func() {
+				t.insertRowAt(row, id)
+			})
+		}
+		return
+	}
+}
+
+func (t *memoryTableData) insertRowAt(row *memoryRow, index int) {
+	if index > len(t.rows) {
+		index = len(t.rows)
+	}
+
+	rows := make([]*memoryRow, 0, len(t.rows)+1)
+	rows = append(rows, t.rows[:index]...)
+	rows = append(rows, row)
+	t.rows = append(rows, t.rows[index:]...)
+	t.version++
+}
+
+func (l *memoryUndoLog) handleSavepoint(savepoint memorySavepoint) error {
+	if l == nil {
+		return memoryErrorf(memoryNoActiveTransaction, "%s can only be used in transaction blocks", savepointCommandTag(savepoint))
+	}
+
+	if savepoint.kind == memoryCreateSavepoint {
+		mark := memorySavepointMark{
+			name:          savepoint.name,
+			mark:          len(l.entries),
+			notifications: len(l.notifications),
+		}
+		l.savepoints = append(l.savepoints, mark)
+		return nil
+	}
+
+	index := -1
+	for id := len(l.savepoints) - 1; id >= 0; id-- {
+		if l.savepoints[id].name == savepoint.name {
+			index = id
+			break
+		}
+	}
+	if index < 0 {
+		return memoryErrorf(memoryInvalidSavepoint, "savepoint %q does not exist", savepoint.name)
+	}
+
+	if savepoint.kind == memoryReleaseSavepoint {
+		l.savepoints = l.savepoints[:index]
+		return nil
+	}
+
+	mark := l.savepoints[index]
+	revertMemoryChanges(l.entries[mark.mark:])
+	l.entries = l.entries[:mark.mark]
+	l.notifications = l.notifications[:mark.notifications]
+	l.savepoints = l.savepoints[:index+1]
+
+	return nil
+}
+
+func savepointCommandTag(savepoint memorySavepoint) pgx.CommandTag {
+	switch savepoint.kind {
+	case memoryReleaseSavepoint:
+		return "RELEASE"
+	case memoryRollbackToSavepoint:
+		return "ROLLBACK"
+	default:
+		return "SAVEPOINT"
+	}
+}
+
+func revertMemoryChanges(undo []func()) {
+	for id := len(undo) - 1; id >= 0; id-- {
+		undo[id]()
+	}
+}
@@ -0,0 +1,392 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+var memoryTestTables = []Table{
+	{
+		Name: "players",
+		Columns: []Column{
+			{Name: "id", Type: Uuid, PrimaryKey: true, Default: func() interface{} { return uuid.New() }},
+			{Name: "name", Type: Text, NotNull: true, Unique: true},
+			{Name: "level", Type: Integer},
+			{Name: "created_at", Type: Timestamp},
+		},
+	},
+	{
+		Name: "scores",
+		Columns: []Column{
+			{Name: "player", Type: Text},
+			{Name: "points", Type: Float},
+		},
+	},
+}
+
+func newTestMemoryEngine(t *testing.T) *memoryEngine {
+	e, err := newMemoryEngine(memoryTestTables)
+	assert.Nil(t, err)
+
+	mustExecuteMemory(t, e, nil, "INSERT INTO players (name, level) VALUES ($1, $2), ($3, $4), ($5, NULL)", "alice", 3, "bob", 1, "carol")
+	mustExecuteMemory(t, e, nil, "INSERT INTO scores (player, points) VALUES ('alice', 10), ('alice', 2.5), ('bob', 4)")
+
+	return e
+}
+
+func mustExecuteMemory(t *testing.T, e *memoryEngine, log *memoryUndoLog, sql string, args ...interface{}) memoryResult {
+	res, err := e.execute(sql, args, log)
+	assert.Nil(t, err, sql)
+	return res
+}
+
+func assertMemoryError(t *testing.T, err error, code string) {
+	pgErr, ok := err.(pgx.PgError)
+	if assert.True(t, ok, "%v", err) {
+		assert.Equal(t, code, pgErr.Code, pgErr.Message)
+	}
+}
+
+func TestNewMemoryEngine_InvalidTables(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := newMemoryEngine([]Table{{Name: "t"}})
+	assert.NotNil(err)
+
+	column := Column{Name: "a"}
+	_, err = newMemoryEngine([]Table{{Name: "t", Columns: []Column{column, column}}})
+	assert.NotNil(err)
+
+	table := Table{Name: "t", Columns: []Column{column}}
+	_, err = newMemoryEngine([]Table{table, table})
+	assert.NotNil(err)
+}
+
+func TestMemoryEngine_Select(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	res := mustExecuteMemory(t, e, nil, "SELECT name, level FROM players WHERE level >= $1 ORDER BY level DESC", 1)
+	assert.Equal(pgx.CommandTag("SELECT 2"), res.tag)
+	assert.Equal([][]interface{}{{"alice", int64(3)}, {"bob", int64(1)}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT p.name FROM players AS p WHERE p.level IS NULL")
+	assert.Equal([][]interface{}{{"carol"}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT name FROM players WHERE name in ($1, $2) AND NOT (level = 3)", "alice", "bob")
+	assert.Equal([][]interface{}{{"bob"}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT name FROM players WHERE name ILIKE $1 OR level BETWEEN $2 AND $3 ORDER BY name", "AL%", 0, 2)
+	assert.Equal([][]interface{}{{"alice"}, {"bob"}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT name FROM players WHERE name NOT LIKE $1 ORDER BY name LIMIT 1 OFFSET 1", "a%")
+	assert.Equal([][]interface{}{{"carol"}}, res.rows)
+}
+
+func TestMemoryEngine_Select_OrderByNulls(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	res := mustExecuteMemory(t, e, nil, "SELECT name FROM players ORDER BY level ASC")
+	assert.Equal([][]interface{}{{"bob"}, {"alice"}, {"carol"}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT name FROM players ORDER BY level DESC")
+	assert.Equal([][]interface{}{{"carol"}, {"alice"}, {"bob"}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT name FROM players ORDER BY level ASC NULLS FIRST")
+	assert.Equal([][]interface{}{{"carol"}, {"bob"}, {"alice"}}, res.rows)
+}
+
+func TestMemoryEngine_Select_Joins(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	res := mustExecuteMemory(t, e, nil, "SELECT p.name, s.points FROM players AS p INNER JOIN scores AS s ON p.name = s.player ORDER BY s.points")
+	assert.Equal([][]interface{}{{"alice", 2.5}, {"bob", 4.0}, {"alice", 10.0}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT p.name, s.points FROM players AS p LEFT JOIN scores AS s ON p.name = s.player WHERE s.points IS NULL")
+	assert.Equal([][]interface{}{{"carol", nil}}, res.rows)
+
+	mustExecuteMemory(t, e, nil, "INSERT INTO scores (player, points) VALUES ('dave', 1)")
+	res = mustExecuteMemory(t, e, nil, "SELECT p.name, s.player FROM players AS p RIGHT JOIN scores AS s ON p.name = s.player WHERE p.name IS NULL")
+	assert.Equal([][]interface{}{{nil, "dave"}}, res.rows)
+}
+
+func TestMemoryEngine_Select_Aggregates(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	res := mustExecuteMemory(t, e, nil, "SELECT COUNT(*) AS total, COUNT(level), MAX(level), MIN(name) FROM players")
+	assert.Equal([][]interface{}{{int64(3), int64(2), int64(3), "alice"}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT player, SUM(points) AS total, AVG(points) FROM scores GROUP BY player HAVING COUNT(*) > $1", 1)
+	assert.Equal([][]interface{}{{"alice", 12.5, 6.25}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT player, COUNT(DISTINCT points) AS total FROM scores GROUP BY player ORDER BY total DESC, player")
+	assert.Equal([][]interface{}{{"alice", int64(2)}, {"bob", int64(1)}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT COUNT(*), SUM(points) FROM scores WHERE player = 'nobody'")
+	assert.Equal([][]interface{}{{int64(0), nil}}, res.rows)
+}
+
+func TestMemoryEngine_Columns(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	res := mustExecuteMemory(t, e, nil, "SELECT p.*, s.points AS score, COUNT(*), 1 FROM players p JOIN scores s ON s.player = p.name GROUP BY p.id, s.points")
+	assert.Equal([]string{"id", "name", "level", "created_at", "score", "count", "?column?"}, res.columns)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT * FROM scores WHERE player = 'nobody'")
+	assert.Equal([]string{"player", "points"}, res.columns)
+
+	res = mustExecuteMemory(t, e, nil, "INSERT INTO scores (player, points) VALUES ('dave', 1) RETURNING points, player AS name")
+	assert.Equal([]string{"points", "name"}, res.columns)
+
+	res = mustExecuteMemory(t, e, nil, "UPDATE scores SET points = 2 WHERE player = 'dave' RETURNING *")
+	assert.Equal([]string{"player", "points"}, res.columns)
+
+	res = mustExecuteMemory(t, e, nil, "DELETE FROM scores WHERE player = 'dave' RETURNING player")
+	assert.Equal([]string{"player"}, res.columns)
+
+	res = mustExecuteMemory(t, e, nil, "DELETE FROM scores WHERE player = 'dave'")
+	assert.Nil(res.columns)
+}
+
+func TestMemoryEngine_Select_Errors(t *testing.T) {
+	e := newTestMemoryEngine(t)
+
+	_, err := e.execute("SELECT name FROM unknown", nil, nil)
+	assertMemoryError(t, err, memoryUndefinedTable)
+
+	_, err = e.execute("SELECT unknown FROM players", nil, nil)
+	assertMemoryError(t, err, memoryUndefinedColumn)
+
+	mustExecuteMemory(t, e, nil, "DELETE FROM scores")
+	_, err = e.execute("SELECT points FROM scores WHERE unknown = 1", nil, nil)
+	assertMemoryError(t, err, memoryUndefinedColumn)
+
+	_, err = e.execute("UPDATE scores SET points = 1 RETURNING unknown", nil, nil)
+	assertMemoryError(t, err, memoryUndefinedColumn)
+
+	_, err = e.execute("SELECT player FROM scores AS a INNER JOIN scores AS b ON a.player = b.player", nil, nil)
+	assertMemoryError(t, err, memoryAmbiguousColumn)
+
+	_, err = e.execute("SELECT name FROM players WHERE", nil, nil)
+	assertMemoryError(t, err, memorySyntaxError)
+
+	_, err = e.execute("SELECT my_script($1)", []interface{}{1}, nil)
+	assertMemoryError(t, err, memoryUndefinedFunction)
+
+	_, err = e.execute("SELECT name FROM players WHERE level = $2", []interface{}{1}, nil)
+	assertMemoryError(t, err, memoryUndefinedParameter)
+}
+
+func TestMemoryEngine_Insert(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	now := time.Now()
+	res := mustExecuteMemory(t, e, nil, "INSERT INTO players (name, created_at) VALUES ($1, $2) RETURNING name, level, created_at, xmax", "dave", now)
+	assert.Equal(pgx.CommandTag("INSERT 0 1"), res.tag)
+	assert.Equal([][]interface{}{{"dave", nil, now, int64(0)}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT id FROM players WHERE name = 'dave'")
+	_, err := uuid.Parse(res.rows[0][0].(string))
+	assert.Nil(err)
+}
+
+func TestMemoryEngine_Insert_ConstraintViolations(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	_, err := e.execute("INSERT INTO players (name) VALUES ($1), ($2)", []interface{}{"dave", "alice"}, nil)
+	assertMemoryError(t, err, memoryUniqueViolation)
+	assert.Equal("players_name_key", err.(pgx.PgError).ConstraintName)
+
+	res := mustExecuteMemory(t, e, nil, "SELECT name FROM players WHERE name = 'dave'")
+	assert.Empty(res.rows)
+
+	_, err = e.execute("INSERT INTO players (level) VALUES (1)", nil, nil)
+	assertMemoryError(t, err, memoryNotNullViolation)
+
+	_, err = e.execute("INSERT INTO players (id, name) VALUES ($1, $2)", []interface{}{"not-a-uuid", "dave"}, nil)
+	assertMemoryError(t, err, memoryInvalidTextRepresentation)
+
+	_, err = e.execute("INSERT INTO players (unknown) VALUES (1)", nil, nil)
+	assertMemoryError(t, err, memoryUndefinedColumn)
+}
+
+func TestMemoryEngine_Insert_OnConflict(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	res := mustExecuteMemory(t, e, nil, "INSERT INTO players (name, level) VALUES ($1, $2) ON CONFLICT (name) DO UPDATE SET level = EXCLUDED.level RETURNING (xmax = 0) AS inserted", "alice", 7)
+	assert.Equal([][]interface{}{{false}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT level FROM players WHERE name = 'alice'")
+	assert.Equal([][]interface{}{{int64(7)}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "INSERT INTO players (name) VALUES ($1) ON CONFLICT ON CONSTRAINT players_name_key DO UPDATE SET level = $2 RETURNING (xmax = 0) AS inserted", "dave", 2)
+	assert.Equal([][]interface{}{{true}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "INSERT INTO players (name) VALUES ('bob') ON CONFLICT DO NOTHING RETURNING name")
+	assert.Equal(pgx.CommandTag("INSERT 0 0"), res.tag)
+	assert.Empty(res.rows)
+
+	_, err := e.execute("INSERT INTO players (name) VALUES ('bob') ON CONFLICT (level) DO NOTHING", nil, nil)
+	assertMemoryError(t, err, memoryInvalidColumnReference)
+
+	_, err = e.execute("INSERT INTO players (name) VALUES ('bob') ON CONFLICT ON CONSTRAINT unknown DO NOTHING", nil, nil)
+	assertMemoryError(t, err, memoryUndefinedObject)
+
+	_, err = e.execute("INSERT INTO players (name) VALUES ('bob'), ('bob') ON CONFLICT (name) DO UPDATE SET level = 1", nil, nil)
+	assertMemoryError(t, err, memoryCardinalityViolation)
+}
+
+func TestMemoryEngine_Update(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	res := mustExecuteMemory(t, e, nil, "UPDATE players SET level = $1 WHERE level IS NOT NULL RETURNING name", 5)
+	assert.Equal(pgx.CommandTag("UPDATE 2"), res.tag)
+	assert.Equal([][]interface{}{{"alice"}, {"bob"}}, res.rows)
+
+	_, err := e.execute("UPDATE players SET name = $1", []interface{}{"same"}, nil)
+	assertMemoryError(t, err, memoryUniqueViolation)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT name, level FROM players ORDER BY name")
+	assert.Equal([][]interface{}{{"alice", int64(5)}, {"bob", int64(5)}, {"carol", nil}}, res.rows)
+
+	_, err = e.execute("UPDATE players SET name = NULL WHERE name = 'bob'", nil, nil)
+	assertMemoryError(t, err, memoryNotNullViolation)
+}
+
+func TestMemoryEngine_Delete(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	res := mustExecuteMemory(t, e, nil, "DELETE FROM scores WHERE player = $1 RETURNING points", "alice")
+	assert.Equal(pgx.CommandTag("DELETE 2"), res.tag)
+	assert.Equal([][]interface{}{{10.0}, {2.5}}, res.rows)
+
+	res = mustExecuteMemory(t, e, nil, "DELETE FROM scores")
+	assert.Equal(pgx.CommandTag("DELETE 1"), res.tag)
+}
+
+func TestMemoryEngine_CopyFrom(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	copied, err := e.copyFrom("scores", []string{"player", "points"}, [][]interface{}{{"carol", 1}, {"carol", 2}}, nil)
+	assert.Nil(err)
+	assert.Equal(2, copied)
+
+	_, err = e.copyFrom("players", []string{"name"}, [][]interface{}{{"dave"}, {"alice"}}, nil)
+	assertMemoryError(t, err, memoryUniqueViolation)
+
+	res := mustExecuteMemory(t, e, nil, "SELECT COUNT(*) FROM players")
+	assert.Equal([][]interface{}{{int64(3)}}, res.rows)
+}
+
+func TestMemoryEngine_Savepoints(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	_, err := e.execute("SAVEPOINT sp_1", nil, nil)
+	assertMemoryError(t, err, memoryNoActiveTransaction)
+
+	tx := e.snapshot()
+	log := &memoryUndoLog{}
+	mustExecuteMemory(t, tx, log, "INSERT INTO players (name) VALUES ('dave')")
+	mustExecuteMemory(t, tx, log, "SAVEPOINT sp_1")
+	mustExecuteMemory(t, tx, log, "DELETE FROM players WHERE name = 'alice'")
+	mustExecuteMemory(t, tx, log, "UPDATE players SET level = 10 WHERE name = 'bob'")
+	mustExecuteMemory(t, tx, log, "ROLLBACK TO SAVEPOINT sp_1")
+
+	res := mustExecuteMemory(t, tx, log, "SELECT name, level FROM players ORDER BY name")
+	assert.Equal([][]interface{}{{"alice", int64(3)}, {"bob", int64(1)}, {"carol", nil}, {"dave", nil}}, res.rows)
+
+	mustExecuteMemory(t, tx, log, "RELEASE SAVEPOINT sp_1")
+	_, err = tx.execute("RELEASE SAVEPOINT sp_1", nil, log)
+	assertMemoryError(t, err, memoryInvalidSavepoint)
+
+	res = mustExecuteMemory(t, e, nil, "SELECT name FROM players ORDER BY name")
+	assert.Equal([][]interface{}{{"alice"}, {"bob"}, {"carol"}}, res.rows)
+}
+
+func TestMemoryEngine_Snapshot(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	tx := e.snapshot()
+	log := &memoryUndoLog{}
+	mustExecuteMemory(t, tx, log, "INSERT INTO players (name) VALUES ('dave')")
+	mustExecuteMemory(t, tx, log, "UPDATE players SET level = 10 WHERE name = 'bob'")
+	mustExecuteMemory(t, e, nil, "INSERT INTO scores (player, points) VALUES ('carol', 1)")
+
+	res := mustExecuteMemory(t, e, nil, "SELECT name, level FROM players ORDER BY name")
+	assert.Equal([][]interface{}{{"alice", int64(3)}, {"bob", int64(1)}, {"carol", nil}}, res.rows)
+	res = mustExecuteMemory(t, tx, log, "SELECT COUNT(*) FROM scores")
+	assert.Equal([][]interface{}{{int64(3)}}, res.rows)
+
+	assert.Nil(e.commit(tx, log))
+
+	res = mustExecuteMemory(t, e, nil, "SELECT name, level FROM players ORDER BY name")
+	assert.Equal([][]interface{}{{"alice", int64(3)}, {"bob", int64(10)}, {"carol", nil}, {"dave", nil}}, res.rows)
+	res = mustExecuteMemory(t, e, nil, "SELECT COUNT(*) FROM scores")
+	assert.Equal([][]interface{}{{int64(4)}}, res.rows)
+}
+
+func TestMemoryEngine_Snapshot_ConcurrentUpdate(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	first, second := e.snapshot(), e.snapshot()
+	mustExecuteMemory(t, first, &memoryUndoLog{}, "UPDATE players SET level = 10 WHERE name = 'bob'")
+	mustExecuteMemory(t, second, &memoryUndoLog{}, "DELETE FROM players WHERE name = 'bob'")
+
+	assert.Nil(e.commit(first, &memoryUndoLog{}))
+	err := e.commit(second, &memoryUndoLog{})
+	assertMemoryError(t, err, memorySerializationFailure)
+
+	res := mustExecuteMemory(t, e, nil, "SELECT name, level FROM players ORDER BY name")
+	assert.Equal([][]interface{}{{"alice", int64(3)}, {"bob", int64(10)}, {"carol", nil}}, res.rows)
+}
+
+func TestMemoryEngine_Snapshot_ConcurrentChanges(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	first, second := e.snapshot(), e.snapshot()
+	mustExecuteMemory(t, first, &memoryUndoLog{}, "UPDATE players SET level = 10 WHERE name = 'bob'")
+	mustExecuteMemory(t, first, &memoryUndoLog{}, "INSERT INTO players (name) VALUES ('dave')")
+	mustExecuteMemory(t, second, &memoryUndoLog{}, "UPDATE players SET level = 4 WHERE name = 'alice'")
+	mustExecuteMemory(t, second, &memoryUndoLog{}, "DELETE FROM players WHERE name = 'carol'")
+	mustExecuteMemory(t, second, &memoryUndoLog{}, "INSERT INTO players (name) VALUES ('erin')")
+
+	assert.Nil(e.commit(first, &memoryUndoLog{}))
+	assert.Nil(e.commit(second, &memoryUndoLog{}))
+
+	res := mustExecuteMemory(t, e, nil, "SELECT name, level FROM players")
+	assert.Equal([][]interface{}{{"alice", int64(4)}, {"bob", int64(10)}, {"dave", nil}, {"erin", nil}}, res.rows)
+}
+
+func TestMemoryEngine_Snapshot_ConcurrentUniqueViolation(t *testing.T) {
+	assert := assert.New(t)
+	e := newTestMemoryEngine(t)
+
+	first, second := e.snapshot(), e.snapshot()
+	mustExecuteMemory(t, first, &memoryUndoLog{}, "INSERT INTO players (name) VALUES ('dave')")
+	mustExecuteMemory(t, second, &memoryUndoLog{}, "UPDATE players SET name = 'dave' WHERE name = 'carol'")
+
+	assert.Nil(e.commit(first, &memoryUndoLog{}))
+	err := e.commit(second, &memoryUndoLog{})
+	assertMemoryError(t, err, memoryUniqueViolation)
+
+	res := mustExecuteMemory(t, e, nil, "SELECT name FROM players")
+	assert.Equal([][]interface{}{{"alice"}, {"bob"}, {"carol"}, {"dave"}}, res.rows)
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx"
+)
+
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	memoryNoActiveTransaction       = "25P01"
+	memoryInvalidSavepoint          = "3B001"
+	memoryNotNullViolation          = "23502"
+	memoryUniqueViolation           = "23505"
+	memoryInvalidTextRepresentation = "22P02"
+	memoryInvalidParameterValue     = "22023"
+	memorySyntaxError               = "42601"
+	memoryUndefinedTable            = "42P01"
+	memoryDuplicateTable            = "42P07"
+	memoryUndefinedColumn           = "42703"
+	memoryUndefinedFunction         = "42883"
+	memoryUndefinedParameter        = "42P02"
+	memoryUndefinedObject           = "42704"
+	memoryAmbiguousColumn           = "42702"
+	memoryDuplicateColumn           = "42701"
+	memoryDatatypeMismatch          = "42804"
+	memoryGroupingError             = "42803"
+	memoryInvalidColumnReference    = "42P10"
+	memoryCardinalityViolation      = "21000"
+	memoryFeatureNotSupported       = "0A000"
+	memorySerializationFailure      = "40001"
+)
+
+// Errors are reported the way pgx does so that callers can't tell
+// the in-memory database apart from a real one.
+func memoryErrorf(code string, format string, args ...interface{}) error {
+	return pgx.PgError{
+		Severity: "ERROR",
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+func memoryNotNullError(table string, column string) error {
+	return pgx.PgError{
+		Severity:   "ERROR",
+		Code:       memoryNotNullViolation,
+		Message:    fmt.Sprintf("null value in column %q of relation %q violates not-null constraint", column, table),
+		TableName:  table,
+		ColumnName: column,
+	}
+}
+
+func memoryUniqueError(table string, column Column, value interface{}) error {
+	constraint := column.constraintName(table)
+	return pgx.PgError{
+		Severity:       "ERROR",
+		Code:           memoryUniqueViolation,
+		Message:        fmt.Sprintf("duplicate key value violates unique constraint %q", constraint),
+		Detail:         fmt.Sprintf("Key (%s)=(%v) already exists.", column.Name, value),
+		TableName:      table,
+		ConstraintName: constraint,
+	}
+}
@@ -0,0 +1,512 @@
+package memory
+
+import (
+	"strings"
+)
+
+type memoryExpr interface {
+	eval(env *memoryEnv) (interface{}, error)
+}
+
+type memoryBinding struct {
+	alias string
+	table *memoryTableData
+	// Nil when the binding is null-extended by an outer join.
+	row *memoryRow
+}
+
+type memoryEnv struct {
+	bindings []memoryBinding
+	args     []interface{}
+	// The row proposed for insertion in an ON CONFLICT DO UPDATE.
+	excluded *memoryBinding
+	// The rows of the group when aggregates are evaluated.
+	group []*memoryEnv
+}
+
+// https://www.postgresql.org/docs/current/sql-insert.html#SQL-ON-CONFLICT
+const memoryExcludedTable = "excluded"
+
+// https://www.postgresql.org/docs/current/ddl-system-columns.html
+const memoryXmaxColumn = "xmax"
+
+type memoryLiteralExpr struct {
+	value interface{}
+}
+
+type memoryParamExpr struct {
+	index int
+}
+
+type memoryColumnExpr struct {
+	table string
+	name  string
+}
+
+type memoryComparisonExpr struct {
+	op  string
+	lhs memoryExpr
+	rhs memoryExpr
+}
+
+type memoryLogicalExpr struct {
+	and bool
+	lhs memoryExpr
+	rhs memoryExpr
+}
+
+type memoryNotExpr struct {
+	expr memoryExpr
+}
+
+type memoryIsNullExpr struct {
+	expr memoryExpr
+	not  bool
+}
+
+type memoryInExpr struct {
+	expr   memoryExpr
+	values []memoryExpr
+	not    bool
+}
+
+type memoryLikeExpr struct {
+	expr        memoryExpr
+	pattern     memoryExpr
+	not         bool
+	insensitive bool
+}
+
+type memoryBetweenExpr struct {
+	expr memoryExpr
+	low  memoryExpr
+	high memoryExpr
+	not  bool
+}
+
+type memoryAggregateExpr struct {
+	function string
+	distinct bool
+	star     bool
+	arg      memoryExpr
+}
+
+func (e memoryLiteralExpr) eval(env *memoryEnv) (interface{}, error) {
+	return e.value, nil
+}
+
+func (e memoryParamExpr) eval(env *memoryEnv) (interface{}, error) {
+	if e.index > len(env.args) {
+		return nil, memoryErrorf(memoryUndefinedParameter, "there is no parameter $%d", e.index)
+	}
+
+	return normalizeMemoryValue(env.args[e.index-1]), nil
+}
+
+func (e memoryColumnExpr) eval(env *memoryEnv) (interface{}, error) {
+	binding, index, err := env.resolve(e)
+	if err != nil {
+		return nil, err
+	}
+
+	if binding.row == nil {
+		return nil, nil
+	}
+	if index < 0 {
+		return binding.row.xmax, nil
+	}
+
+	return binding.row.values[index], nil
+}
+
+func (e memoryComparisonExpr) eval(env *memoryEnv) (interface{}, error) {
+	lhs, err := e.lhs.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := e.rhs.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if lhs == nil || rhs == nil {
+		return nil, nil
+	}
+
+	cmp, err := compareMemoryValues(lhs, rhs)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "=":
+		return cmp == 0, nil
+	case "<>":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+// https://www.postgresql.org/docs/current/functions-logical.html
+func (e memoryLogicalExpr) eval(env *memoryEnv) (interface{}, error) {
+	lhs, err := evalMemoryCondition(e.lhs, env)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := evalMemoryCondition(e.rhs, env)
+	if err != nil {
+		return nil, err
+	}
+
+	decisive := !e.and
+	if lhs == decisive || rhs == decisive {
+		return decisive, nil
+	}
+	if lhs == nil || rhs == nil {
+		return nil, nil
+	}
+
+	return !decisive, nil
+}
+
+func (e memoryNotExpr) eval(env *memoryEnv) (interface{}, error) {
+	value, err := evalMemoryCondition(e.expr, env)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	return !value.(bool), nil
+}
+
+func (e memoryIsNullExpr) eval(env *memoryEnv) (interface{}, error) {
+	value, err := e.expr.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	return (value == nil) != e.not, nil
+}
+
+func (e memoryInExpr) eval(env *memoryEnv) (interface{}, error) {
+	value, err := e.expr.eval(env)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	var out interface{} = false
+	for _, candidate := range e.values {
+		cv, err := candidate.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		if cv == nil {
+			out = nil
+			continue
+		}
+
+		cmp, err := compareMemoryValues(value, cv)
+		if err != nil {
+			return nil, err
+		}
+		if cmp == 0 {
+			out = true
+			break
+		}
+	}
+
+	if out == nil || !e.not {
+		return out, nil
+	}
+	return !out.(bool), nil
+}
+
+func (e memoryLikeExpr) eval(env *memoryEnv) (interface{}, error) {
+	value, err := e.expr.eval(env)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	pattern, err := e.pattern.eval(env)
+	if err != nil || pattern == nil {
+		return nil, err
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return nil, memoryErrorf(memoryUndefinedFunction, "operator does not exist: %T ~~ text", value)
+	}
+	patternStr, ok := pattern.(string)
+	if !ok {
+		return nil, memoryErrorf(memoryUndefinedFunction, "operator does not exist: text ~~ %T", pattern)
+	}
+
+	regex, err := likePatternToRegex(patternStr, e.insensitive)
+	if err != nil {
+		return nil, memoryErrorf(memoryInvalidTextRepresentation, "invalid pattern %q", patternStr)
+	}
+
+	return regex.MatchString(str) != e.not, nil
+}
+
+func (e memoryBetweenExpr) eval(env *memoryEnv) (interface{}, error) {
+	lower := memoryComparisonExpr{op: ">=", lhs: e.expr, rhs: e.low}
+	upper := memoryComparisonExpr{op: "<=", lhs: e.expr, rhs: e.high}
+
+	var expr memoryExpr = memoryLogicalExpr{and: true, lhs: lower, rhs: upper}
+	if e.not {
+		expr = memoryNotExpr{expr: expr}
+	}
+
+	return expr.eval(env)
+}
+
+// https://www.postgresql.org/docs/current/functions-aggregate.html
+func (e memoryAggregateExpr) eval(env *memoryEnv) (interface{}, error) {
+	if env.group == nil {
+		return nil, memoryErrorf(memoryGroupingError, "aggregate functions are not allowed here")
+	}
+
+	var values []interface{}
+	seen := make(map[string]bool)
+	for _, rowEnv := range env.group {
+		if e.star {
+			values = append(values, true)
+			continue
+		}
+
+		value, err := e.arg.eval(rowEnv)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+		if e.distinct {
+			key := memoryValueKey(value)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+
+		values = append(values, value)
+	}
+
+	if e.function == "COUNT" {
+		return int64(len(values)), nil
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	switch e.function {
+	case "MIN", "MAX":
+		out := values[0]
+		for _, value := range values[1:] {
+			cmp, err := compareMemoryValues(value, out)
+			if err != nil {
+				return nil, err
+			}
+			if (e.function == "MIN" && cmp < 0) || (e.function == "MAX" && cmp > 0) {
+				out = value
+			}
+		}
+		return out, nil
+	default:
+		return sumMemoryValues(e.function, values)
+	}
+}
+
+func sumMemoryValues(function string, values []interface{}) (interface{}, error) {
+	var intSum int64
+	var floatSum float64
+	isFloat := false
+
+	for _, value := range values {
+		switch v := value.(type) {
+		case int64:
+			intSum += v
+			floatSum += float64(v)
+		case float64:
+			floatSum += v
+			isFloat = true
+		default:
+			return nil, memoryErrorf(memoryUndefinedFunction, "function %s(%T) does not exist", strings.ToLower(function), value)
+		}
+	}
+
+	if function == "AVG" {
+		return floatSum / float64(len(values)), nil
+	}
+	if isFloat {
+		return floatSum, nil
+	}
+	return intSum, nil
+}
+
+// Conditions evaluate to true, false or nil when the result is
+// unknown.
+func evalMemoryCondition(expr memoryExpr, env *memoryEnv) (interface{}, error) {
+	value, err := expr.eval(env)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	b, ok := value.(bool)
+	if !ok {
+		return nil, memoryErrorf(memoryDatatypeMismatch, "argument of condition must be type boolean, not %T", value)
+	}
+
+	return b, nil
+}
+
+func memoryConditionHolds(expr memoryExpr, env *memoryEnv) (bool, error) {
+	if expr == nil {
+		return true, nil
+	}
+
+	value, err := evalMemoryCondition(expr, env)
+	if err != nil {
+		return false, err
+	}
+
+	return value == true, nil
+}
+
+// The returned index is negative for the xmax system column.
+func (env *memoryEnv) resolve(column memoryColumnExpr) (*memoryBinding, int, error) {
+	if len(column.table) > 0 {
+		binding := env.findBinding(column.table)
+		if binding == nil {
+			return nil, 0, memoryErrorf(memoryUndefinedTable, "missing FROM-clause entry for table %q", column.table)
+		}
+
+		index, ok := binding.table.columns[column.name]
+		if !ok {
+			if column.name == memoryXmaxColumn {
+				return binding, -1, nil
+			}
+			return nil, 0, memoryColumnError(column)
+		}
+		return binding, index, nil
+	}
+
+	var out *memoryBinding
+	index := 0
+	for id := range env.bindings {
+		binding := &env.bindings[id]
+		if columnIndex, ok := binding.table.columns[column.name]; ok {
+			if out != nil {
+				return nil, 0, memoryErrorf(memoryAmbiguousColumn, "column reference %q is ambiguous", column.name)
+			}
+			out = binding
+			index = columnIndex
+		}
+	}
+
+	if out != nil {
+		return out, index, nil
+	}
+	if column.name == memoryXmaxColumn && len(env.bindings) == 1 {
+		return &env.bindings[0], -1, nil
+	}
+
+	return nil, 0, memoryColumnError(column)
+}
+
+func (env *memoryEnv) findBinding(alias string) *memoryBinding {
+	if alias == memoryExcludedTable && env.excluded != nil {
+		return env.excluded
+	}
+
+	for id := range env.bindings {
+		if env.bindings[id].alias == alias {
+			return &env.bindings[id]
+		}
+	}
+
+	return nil
+}
+
+func memoryColumnError(column memoryColumnExpr) error {
+	if len(column.table) > 0 {
+		return memoryErrorf(memoryUndefinedColumn, "column %s.%s does not exist", column.table, column.name)
+	}
+
+	return memoryErrorf(memoryUndefinedColumn, "column %q does not exist", column.name)
+}
+
+func walkMemoryExpr(expr memoryExpr, visit func(expr memoryExpr) error) error {
+	if expr == nil {
+		return nil
+	}
+	if err := visit(expr); err != nil {
+		return err
+	}
+
+	var children []memoryExpr
+	switch e := expr.(type) {
+	case memoryComparisonExpr:
+		children = []memoryExpr{e.lhs, e.rhs}
+	case memoryLogicalExpr:
+		children = []memoryExpr{e.lhs, e.rhs}
+	case memoryNotExpr:
+		children = []memoryExpr{e.expr}
+	case memoryIsNullExpr:
+		children = []memoryExpr{e.expr}
+	case memoryInExpr:
+		children = append([]memoryExpr{e.expr}, e.values...)
+	case memoryLikeExpr:
+		children = []memoryExpr{e.expr, e.pattern}
+	case memoryBetweenExpr:
+		children = []memoryExpr{e.expr, e.low, e.high}
+	case memoryAggregateExpr:
+		children = []memoryExpr{e.arg}
+	}
+
+	for _, child := range children {
+		if err := walkMemoryExpr(child, visit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func containsMemoryAggregate(expr memoryExpr) bool {
+	found := false
+	walkMemoryExpr(expr, func(expr memoryExpr) error {
+		if _, ok := expr.(memoryAggregateExpr); ok {
+			found = true
+		}
+		return nil
+	})
+
+	return found
+}
+
+// Columns are resolved when rows are evaluated: this reports unknown
+// columns even when there are no rows, like Postgres does.
+func checkMemoryColumns(env *memoryEnv, exprs ...memoryExpr) error {
+	for _, expr := range exprs {
+		err := walkMemoryExpr(expr, func(expr memoryExpr) error {
+			column, ok := expr.(memoryColumnExpr)
+			if !ok {
+				return nil
+			}
+
+			_, _, err := env.resolve(column)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/KnoblauchPilze/go-game/pkg/db"
+	"github.com/jackc/pgx"
+)
+
+type memoryDbFacade struct {
+	engine *memoryEngine
+}
+
+type memoryTxFacade struct {
+	engine *memoryEngine
+	// The statements run on the snapshot which replaces the tables of
+	// the engine on commit.
+	snapshot *memoryEngine
+	log      memoryUndoLog
+	closed   atomic.Bool
+}
+
+func (f *memoryDbFacade) Close() {}
+
+func (f *memoryDbFacade) Query(ctx context.Context, sql string, args ...interface{}) (db.DriverRows, error) {
+	return queryMemoryEngine(ctx, f.engine, nil, sql, args)
+}
+
+func (f *memoryDbFacade) Exec(ctx context.Context, sql string, args ...interface{}) (pgx.CommandTag, error) {
+	return execMemoryEngine(ctx, f.engine, nil, sql, args)
+}
+
+func (f *memoryDbFacade) CopyFrom(tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error) {
+	return copyToMemoryEngine(f.engine, nil, tableName, columnNames, rowSrc)
+}
+
+func (f *memoryDbFacade) Begin(ctx context.Context) (db.DriverTx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memoryTxFacade{engine: f.engine, snapshot: f.engine.snapshot()}, nil
+}
+
+func (f *memoryDbFacade) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func (f *memoryTxFacade) Query(ctx context.Context, sql string, args ...interface{}) (db.DriverRows, error) {
+	if f.closed.Load() {
+		return nil, pgx.ErrTxClosed
+	}
+
+	return queryMemoryEngine(ctx, f.snapshot, &f.log, sql, args)
+}
+
+func (f *memoryTxFacade) Exec(ctx context.Context, sql string, args ...interface{}) (pgx.CommandTag, error) {
+	if f.closed.Load() {
+		return "", pgx.ErrTxClosed
+	}
+
+	return execMemoryEngine(ctx, f.snapshot, &f.log, sql, args)
+}
+
+func (f *memoryTxFacade) CopyFrom(tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error) {
+	if f.closed.Load() {
+		return 0, pgx.ErrTxClosed
+	}
+
+	return copyToMemoryEngine(f.snapshot, &f.log, tableName, columnNames, rowSrc)
+}
+
+func (f *memoryTxFacade) Commit(ctx context.Context) error {
+	if !f.closed.CompareAndSwap(false, true) {
+		return pgx.ErrTxClosed
+	}
+
+	return f.engine.commit(f.snapshot, &f.log)
+}
+
+func (f *memoryTxFacade) Rollback(ctx context.Context) error {
+	if !f.closed.CompareAndSwap(false, true) {
+		return pgx.ErrTxClosed
+	}
+
+	f.snapshot = nil
+	return nil
+}
+
+func queryMemoryEngine(ctx context.Context, engine *memoryEngine, log *memoryUndoLog, sql string, args []interface{}) (db.DriverRows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	res, err := engine.execute(sql, args, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMemoryRows(res.columns, res.rows), nil
+}
+
+func execMemoryEngine(ctx context.Context, engine *memoryEngine, log *memoryUndoLog, sql string, args []interface{}) (pgx.CommandTag, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	res, err := engine.execute(sql, args, log)
+	return res.tag, err
+}
+
+func copyToMemoryEngine(engine *memoryEngine, log *memoryUndoLog, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error) {
+	var rows [][]interface{}
+	for rowSrc.Next() {
+		values, err := rowSrc.Values()
+		if err != nil {
+			return 0, err
+		}
+		rows = append(rows, values)
+	}
+	if err := rowSrc.Err(); err != nil {
+		return 0, err
+	}
+
+	return engine.copyFrom(strings.Join(tableName, "."), columnNames, rows, log)
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"strings"
+	"unicode"
+)
+
+type memoryTokenKind int
+
+const (
+	memoryEof memoryTokenKind = iota
+	memoryIdent
+	memoryQuotedIdent
+	memoryNumber
+	memoryString
+	memoryParam
+	memorySymbol
+)
+
+type memoryToken struct {
+	kind memoryTokenKind
+	text string
+	pos  int
+}
+
+// Only the symbols generated by the query builders are recognized.
+var memorySymbols = []string{"<>", "!=", "<=", ">=", "(", ")", ",", ".", "*", "=", "<", ">", ";", "-"}
+
+func tokenizeMemorySql(sql string) ([]memoryToken, error) {
+	var tokens []memoryToken
+
+	runes := []rune(sql)
+	pos := 0
+	for pos < len(runes) {
+		c := runes[pos]
+
+		switch {
+		case unicode.IsSpace(c):
+			pos++
+		case c == '-' && pos+1 < len(runes) && runes[pos+1] == '-':
+			for pos < len(runes) && runes[pos] != '\n' {
+				pos++
+			}
+		case unicode.IsLetter(c) || c == '_':
+			start := pos
+			for pos < len(runes) && isMemoryIdentRune(runes[pos]) {
+				pos++
+			}
+			tokens = append(tokens, memoryToken{kind: memoryIdent, text: string(runes[start:pos]), pos: start})
+		case unicode.IsDigit(c):
+			start := pos
+			for pos < len(runes) && (unicode.IsDigit(runes[pos]) || runes[pos] == '.') {
+				pos++
+			}
+			tokens = append(tokens, memoryToken{kind: memoryNumber, text: string(runes[start:pos]), pos: start})
+		case c == '$':
+			start := pos
+			pos++
+			for pos < len(runes) && unicode.IsDigit(runes[pos]) {
+				pos++
+			}
+			if pos == start+1 {
+				return nil, memoryErrorf(memorySyntaxError, "syntax error at or near \"$\" (position %d)", start)
+			}
+			tokens = append(tokens, memoryToken{kind: memoryParam, text: string(runes[start+1 : pos]), pos: start})
+		case c == '\'' || c == '"':
+			start := pos
+			text, next, ok := readMemoryQuoted(runes, pos, c)
+			if !ok {
+				return nil, memoryErrorf(memorySyntaxError, "unterminated quoted string at position %d", start)
+			}
+			kind := memoryString
+			if c == '"' {
+				kind = memoryQuotedIdent
+			}
+			tokens = append(tokens, memoryToken{kind: kind, text: text, pos: start})
+			pos = next
+		default:
+			symbol := matchMemorySymbol(runes[pos:])
+			if len(symbol) == 0 {
+				return nil, memoryErrorf(memorySyntaxError, "syntax error at or near %q (position %d)", string(c), pos)
+			}
+			tokens = append(tokens, memoryToken{kind: memorySymbol, text: symbol, pos: pos})
+			pos += len(symbol)
+		}
+	}
+
+	tokens = append(tokens, memoryToken{kind: memoryEof, pos: len(runes)})
+	return tokens, nil
+}
+
+func isMemoryIdentRune(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_'
+}
+
+// Quotes are escaped by doubling them both for strings and
+// identifiers.
+func readMemoryQuoted(runes []rune, pos int, quote rune) (string, int, bool) {
+	var out strings.Builder
+
+	pos++
+	for pos < len(runes) {
+		if runes[pos] == quote {
+			if pos+1 < len(runes) && runes[pos+1] == quote {
+				out.WriteRune(quote)
+				pos += 2
+				continue
+			}
+			return out.String(), pos + 1, true
+		}
+
+		out.WriteRune(runes[pos])
+		pos++
+	}
+
+	return "", pos, false
+}
+
+func matchMemorySymbol(runes []rune) string {
+	for _, symbol := range memorySymbols {
+		if len(runes) >= len(symbol) && string(runes[:len(symbol)]) == symbol {
+			return symbol
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jackc/pgx"
+)
+
+// Delivers the notifications like postgres does: a listener receives
+// the ones sent on its channels after it started listening, in order.
+type memoryBroker struct {
+	lock      sync.Mutex
+	listeners map[*memoryListener]bool
+}
+
+// The notifications are queued until they are waited for so that the
+// sender never blocks.
+type memoryListener struct {
+	broker   *memoryBroker
+	lock     sync.Mutex
+	channels map[string]bool
+	pending  []*pgx.Notification
+	signal   chan struct{}
+	closed   chan struct{}
+	close    sync.Once
+}
+
+func newMemoryBroker() *memoryBroker {
+	return &memoryBroker{
+		listeners: make(map[*memoryListener]bool),
+	}
+}
+
+func (b *memoryBroker) newListener() *memoryListener {
+	l := &memoryListener{
+		broker:   b,
+		channels: make(map[string]bool),
+		signal:   make(chan struct{}, 1),
+		closed:   make(chan struct{}),
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.listeners[l] = true
+
+	return l
+}
+
+func (b *memoryBroker) publish(notification *pgx.Notification) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for l := range b.listeners {
+		l.push(notification)
+	}
+}
+
+func (l *memoryListener) Listen(channel string) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.channels[channel] = true
+	return nil
+}
+
+func (l *memoryListener) WaitForNotification(ctx context.Context) (*pgx.Notification, error) {
+	for {
+		if notification := l.pop(); notification != nil {
+			return notification, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-l.closed:
+			return nil, pgx.ErrDeadConn
+		case <-l.signal:
+		}
+	}
+}
+
+func (l *memoryListener) Close() error {
+	l.close.Do(func() {
+		l.broker.lock.Lock()
+		defer l.broker.lock.Unlock()
+
+		delete(l.broker.listeners, l)
+		close(l.closed)
+	})
+
+	return nil
+}
+
+func (l *memoryListener) push(notification *pgx.Notification) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if !l.channels[notification.Channel] {
+		return
+	}
+
+	l.pending = append(l.pending, notification)
+	select {
+	case l.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (l *memoryListener) pop() *pgx.Notification {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if len(l.pending) == 0 {
+		return nil
+	}
+
+	notification := l.pending[0]
+	l.pending = l.pending[1:]
+	return notification
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/db"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+func receiveNotification(t *testing.T, sub db.Subscription) db.Notification {
+	select {
+	case n := <-sub.Notifications():
+		return n
+	case <-time.After(time.Second):
+		t.Fatal("no notification received")
+		return db.Notification{}
+	}
+}
+
+func TestMemoryDatabase_Notify(t *testing.T) {
+	assert := assert.New(t)
+
+	database := newTestMemoryDatabase(t)
+	qe := db.NewQueryExecutor(database)
+	sub, err := database.Subscribe(context.Background(), "players")
+	assert.Nil(err)
+	defer sub.Close()
+
+	assert.Nil(qe.Notify(context.Background(), "other", "ignored"))
+	assert.Nil(qe.Notify(context.Background(), "players", "first"))
+	assert.Nil(qe.Notify(context.Background(), "players", ""))
+
+	assert.Equal(db.Notification{Channel: "players", Payload: "first"}, receiveNotification(t, sub))
+	assert.Equal(db.Notification{Channel: "players"}, receiveNotification(t, sub))
+}
+
+func TestMemoryDatabase_Notify_InTransaction(t *testing.T) {
+	assert := assert.New(t)
+
+	database := newTestMemoryDatabase(t)
+	qe := db.NewQueryExecutor(database)
+	sub, err := database.Subscribe(context.Background(), "players")
+	assert.Nil(err)
+	defer sub.Close()
+
+	err = qe.WithTransaction(context.Background(), func(tx db.QueryExecutor) error {
+		assert.Nil(tx.Notify(context.Background(), "players", "committed"))
+		assert.Equal(0, len(sub.Notifications()))
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal("committed", receiveNotification(t, sub).Payload)
+
+	err = qe.WithTransaction(context.Background(), func(tx db.QueryExecutor) error {
+		assert.Nil(tx.Notify(context.Background(), "players", "rolled back"))
+		return errDefault
+	})
+	assert.Equal(errDefault, err)
+
+	assert.Nil(qe.Notify(context.Background(), "players", "last"))
+	assert.Equal("last", receiveNotification(t, sub).Payload)
+}
+
+func TestMemoryDatabase_Notify_RollbackToSavepoint(t *testing.T) {
+	assert := assert.New(t)
+
+	database := newTestMemoryDatabase(t)
+	sub, err := database.Subscribe(context.Background(), "players")
+	assert.Nil(err)
+	defer sub.Close()
+
+	tx, err := database.Begin(context.Background())
+	assert.Nil(err)
+	notify := "SELECT pg_notify($1, $2)"
+	assert.Nil(tx.Execute(context.Background(), newMemoryTestQuery(t, notify, "players", "kept")).Err())
+	assert.Nil(tx.Execute(context.Background(), newMemoryTestQuery(t, "SAVEPOINT s")).Err())
+	assert.Nil(tx.Execute(context.Background(), newMemoryTestQuery(t, notify, "players", "reverted")).Err())
+	assert.Nil(tx.Execute(context.Background(), newMemoryTestQuery(t, "ROLLBACK TO SAVEPOINT s")).Err())
+	assert.Nil(tx.Commit(context.Background()))
+
+	assert.Equal("kept", receiveNotification(t, sub).Payload)
+	assert.Equal(0, len(sub.Notifications()))
+}
+
+func TestMemoryListener_Close(t *testing.T) {
+	assert := assert.New(t)
+
+	broker := newMemoryBroker()
+	l := broker.newListener()
+	assert.Nil(l.Listen("channel"))
+
+	assert.Nil(l.Close())
+	assert.Nil(l.Close())
+	broker.publish(&pgx.Notification{Channel: "channel"})
+
+	_, err := l.WaitForNotification(context.Background())
+	assert.Equal(pgx.ErrDeadConn, err)
+	assert.Equal(0, len(broker.listeners))
+}
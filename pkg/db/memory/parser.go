@@ -0,0 +1,960 @@
+package memory
+
+import (
+	"strconv"
+	"strings"
+)
+
+type memoryStatement interface{}
+
+type memoryTableRef struct {
+	name  string
+	alias string
+}
+
+type memorySelectItem struct {
+	expr  memoryExpr
+	alias string
+	// Set for '*' and 'alias.*' items.
+	star      bool
+	starTable string
+}
+
+type memoryJoin struct {
+	kind  string
+	table memoryTableRef
+	on    memoryExpr
+}
+
+type memoryOrderTerm struct {
+	expr       memoryExpr
+	desc       bool
+	nullsFirst *bool
+}
+
+type memorySelect struct {
+	items   []memorySelectItem
+	from    *memoryTableRef
+	joins   []memoryJoin
+	where   memoryExpr
+	groupBy []memoryExpr
+	having  memoryExpr
+	orderBy []memoryOrderTerm
+	limit   *int64
+	offset  *int64
+}
+
+type memoryAssignment struct {
+	column string
+	value  memoryExpr
+}
+
+type memoryOnConflict struct {
+	columns    []string
+	constraint string
+	doNothing  bool
+	updates    []memoryAssignment
+}
+
+type memoryInsert struct {
+	table      string
+	columns    []string
+	rows       [][]memoryExpr
+	onConflict *memoryOnConflict
+	returning  []memorySelectItem
+}
+
+type memoryUpdate struct {
+	table     memoryTableRef
+	updates   []memoryAssignment
+	where     memoryExpr
+	returning []memorySelectItem
+}
+
+type memoryDelete struct {
+	table     memoryTableRef
+	where     memoryExpr
+	returning []memorySelectItem
+}
+
+// Sent by 'SELECT pg_notify(channel, payload)'.
+type memoryNotify struct {
+	channel memoryExpr
+	payload memoryExpr
+}
+
+type memorySavepointKind int
+
+const (
+	memoryCreateSavepoint memorySavepointKind = iota
+	memoryReleaseSavepoint
+	memoryRollbackToSavepoint
+)
+
+type memorySavepoint struct {
+	kind memorySavepointKind
+	name string
+}
+
+// Keywords which can't be used as bare aliases or column names.
+var memoryReservedKeywords = map[string]bool{
+	"ALL": true, "AND": true, "AS": true, "ASC": true, "BETWEEN": true, "BY": true,
+	"CONFLICT": true, "DELETE": true, "DESC": true, "DISTINCT": true, "DO": true,
+	"FALSE": true, "FROM": true, "GROUP": true, "HAVING": true, "ILIKE": true,
+	"IN": true, "INNER": true, "INSERT": true, "INTO": true, "IS": true, "JOIN": true,
+	"LEFT": true, "LIKE": true, "LIMIT": true, "NOT": true, "NULL": true,
+	"OFFSET": true, "ON": true, "OR": true, "ORDER": true, "OUTER": true,
+	"RETURNING": true, "RIGHT": true, "SELECT": true, "SET": true, "TRUE": true,
+	"UPDATE": true, "VALUES": true, "WHERE": true,
+}
+
+type memoryParser struct {
+	tokens []memoryToken
+	pos    int
+}
+
+func parseMemorySql(sql string) (memoryStatement, error) {
+	tokens, err := tokenizeMemorySql(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	p := memoryParser{tokens: tokens}
+	stmt, err := p.parseStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	p.acceptSymbol(";")
+	if p.peek().kind != memoryEof {
+		return nil, p.unexpected()
+	}
+
+	return stmt, nil
+}
+
+func (p *memoryParser) parseStatement() (memoryStatement, error) {
+	switch {
+	case p.acceptKeyword("SELECT"):
+		if p.acceptKeyword("pg_notify") {
+			return p.parseNotify()
+		}
+		return p.parseSelect()
+	case p.acceptKeyword("INSERT"):
+		return p.parseInsert()
+	case p.acceptKeyword("UPDATE"):
+		return p.parseUpdate()
+	case p.acceptKeyword("DELETE"):
+		return p.parseDelete()
+	case p.acceptKeyword("SAVEPOINT"):
+		name, err := p.expectIdent()
+		return memorySavepoint{kind: memoryCreateSavepoint, name: name}, err
+	case p.acceptKeyword("RELEASE"):
+		p.acceptKeyword("SAVEPOINT")
+		name, err := p.expectIdent()
+		return memorySavepoint{kind: memoryReleaseSavepoint, name: name}, err
+	case p.acceptKeyword("ROLLBACK"):
+		if err := p.expectKeyword("TO"); err != nil {
+			return nil, memoryErrorf(memoryFeatureNotSupported, "only ROLLBACK TO SAVEPOINT is supported")
+		}
+		p.acceptKeyword("SAVEPOINT")
+		name, err := p.expectIdent()
+		return memorySavepoint{kind: memoryRollbackToSavepoint, name: name}, err
+	default:
+		return nil, memoryErrorf(memoryFeatureNotSupported, "unsupported statement near %q", p.peek().text)
+	}
+}
+
+func (p *memoryParser) parseSelect() (memoryStatement, error) {
+	var err error
+	stmt := memorySelect{}
+
+	if stmt.items, err = p.parseSelectItems(); err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("FROM") {
+		table, err := p.parseTableRef()
+		if err != nil {
+			return nil, err
+		}
+		if p.peekSymbol("(") {
+			return nil, memoryErrorf(memoryUndefinedFunction, "function %s does not exist", table.name)
+		}
+		stmt.from = &table
+
+		for {
+			join, ok, err := p.parseJoin()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				break
+			}
+			stmt.joins = append(stmt.joins, join)
+		}
+	}
+
+	if p.acceptKeyword("WHERE") {
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.acceptKeyword("GROUP") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			stmt.groupBy = append(stmt.groupBy, expr)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	if p.acceptKeyword("HAVING") {
+		if stmt.having, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.acceptKeyword("ORDER") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		if stmt.orderBy, err = p.parseOrderBy(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.acceptKeyword("LIMIT") {
+		if stmt.limit, err = p.parseCount(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.acceptKeyword("OFFSET") {
+		if stmt.offset, err = p.parseCount(); err != nil {
+			return nil, err
+		}
+	}
+
+	return stmt, nil
+}
+
+func (p *memoryParser) parseNotify() (memoryStatement, error) {
+	var err error
+	stmt := memoryNotify{}
+
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	if stmt.channel, err = p.parseExpr(); err != nil {
+		return nil, err
+	}
+	if err := p.expectSymbol(","); err != nil {
+		return nil, err
+	}
+	if stmt.payload, err = p.parseExpr(); err != nil {
+		return nil, err
+	}
+
+	return stmt, p.expectSymbol(")")
+}
+
+func (p *memoryParser) parseSelectItems() ([]memorySelectItem, error) {
+	var items []memorySelectItem
+
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		if !p.acceptSymbol(",") {
+			return items, nil
+		}
+	}
+}
+
+func (p *memoryParser) parseSelectItem() (memorySelectItem, error) {
+	if p.acceptSymbol("*") {
+		return memorySelectItem{star: true}, nil
+	}
+
+	tok := p.peek()
+	if tok.kind == memoryIdent || tok.kind == memoryQuotedIdent {
+		next := p.tokens[p.pos+1]
+		if next.kind == memorySymbol && next.text == "." && p.tokens[p.pos+2].text == "*" && p.tokens[p.pos+2].kind == memorySymbol {
+			p.pos += 3
+			return memorySelectItem{star: true, starTable: identText(tok)}, nil
+		}
+	}
+
+	expr, err := p.parseExpr()
+	if err != nil {
+		return memorySelectItem{}, err
+	}
+
+	item := memorySelectItem{expr: expr}
+	if p.acceptKeyword("AS") {
+		if item.alias, err = p.expectIdent(); err != nil {
+			return memorySelectItem{}, err
+		}
+	} else if p.peekAlias() {
+		item.alias, _ = p.expectIdent()
+	}
+
+	return item, nil
+}
+
+func (p *memoryParser) parseReturning() ([]memorySelectItem, error) {
+	if !p.acceptKeyword("RETURNING") {
+		return nil, nil
+	}
+
+	return p.parseSelectItems()
+}
+
+func (p *memoryParser) parseTableRef() (memoryTableRef, error) {
+	name, err := p.expectIdent()
+	if err != nil {
+		return memoryTableRef{}, err
+	}
+
+	ref := memoryTableRef{name: name, alias: name}
+	if p.acceptKeyword("AS") {
+		if ref.alias, err = p.expectIdent(); err != nil {
+			return memoryTableRef{}, err
+		}
+	} else if p.peekAlias() {
+		ref.alias, _ = p.expectIdent()
+	}
+
+	return ref, nil
+}
+
+func (p *memoryParser) parseJoin() (memoryJoin, bool, error) {
+	join := memoryJoin{}
+
+	switch {
+	case p.acceptKeyword("INNER"):
+		join.kind = "INNER"
+	case p.acceptKeyword("LEFT"):
+		join.kind = "LEFT"
+		p.acceptKeyword("OUTER")
+	case p.acceptKeyword("RIGHT"):
+		join.kind = "RIGHT"
+		p.acceptKeyword("OUTER")
+	case p.peekKeyword("JOIN"):
+		join.kind = "INNER"
+	default:
+		return join, false, nil
+	}
+
+	if err := p.expectKeyword("JOIN"); err != nil {
+		return join, false, err
+	}
+
+	var err error
+	if join.table, err = p.parseTableRef(); err != nil {
+		return join, false, err
+	}
+	if err := p.expectKeyword("ON"); err != nil {
+		return join, false, err
+	}
+	if join.on, err = p.parseExpr(); err != nil {
+		return join, false, err
+	}
+
+	return join, true, nil
+}
+
+func (p *memoryParser) parseOrderBy() ([]memoryOrderTerm, error) {
+	var terms []memoryOrderTerm
+
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		term := memoryOrderTerm{expr: expr}
+		if p.acceptKeyword("DESC") {
+			term.desc = true
+		} else {
+			p.acceptKeyword("ASC")
+		}
+
+		if p.acceptKeyword("NULLS") {
+			first := p.acceptKeyword("FIRST")
+			if !first {
+				if err := p.expectKeyword("LAST"); err != nil {
+					return nil, err
+				}
+			}
+			term.nullsFirst = &first
+		}
+
+		terms = append(terms, term)
+		if !p.acceptSymbol(",") {
+			return terms, nil
+		}
+	}
+}
+
+func (p *memoryParser) parseCount() (*int64, error) {
+	tok := p.next()
+	if tok.kind != memoryNumber {
+		return nil, p.unexpectedToken(tok)
+	}
+
+	count, err := strconv.ParseInt(tok.text, 10, 64)
+	if err != nil {
+		return nil, p.unexpectedToken(tok)
+	}
+
+	return &count, nil
+}
+
+func (p *memoryParser) parseInsert() (memoryStatement, error) {
+	if err := p.expectKeyword("INTO"); err != nil {
+		return nil, err
+	}
+
+	var err error
+	stmt := memoryInsert{}
+	if stmt.table, err = p.expectIdent(); err != nil {
+		return nil, err
+	}
+	if stmt.columns, err = p.parseIdentList(); err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeyword("VALUES"); err != nil {
+		return nil, err
+	}
+	for {
+		row, err := p.parseExprList()
+		if err != nil {
+			return nil, err
+		}
+		if len(row) != len(stmt.columns) {
+			return nil, memoryErrorf(memorySyntaxError, "INSERT has %d target columns but %d expressions", len(stmt.columns), len(row))
+		}
+		stmt.rows = append(stmt.rows, row)
+
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	if p.acceptKeyword("ON") {
+		if stmt.onConflict, err = p.parseOnConflict(); err != nil {
+			return nil, err
+		}
+	}
+
+	if stmt.returning, err = p.parseReturning(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+func (p *memoryParser) parseOnConflict() (*memoryOnConflict, error) {
+	if err := p.expectKeyword("CONFLICT"); err != nil {
+		return nil, err
+	}
+
+	var err error
+	clause := &memoryOnConflict{}
+	if p.acceptKeyword("ON") {
+		if err := p.expectKeyword("CONSTRAINT"); err != nil {
+			return nil, err
+		}
+		if clause.constraint, err = p.expectIdent(); err != nil {
+			return nil, err
+		}
+	} else if p.peekSymbol("(") {
+		if clause.columns, err = p.parseIdentList(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expectKeyword("DO"); err != nil {
+		return nil, err
+	}
+	if p.acceptKeyword("NOTHING") {
+		clause.doNothing = true
+		return clause, nil
+	}
+
+	if err := p.expectKeyword("UPDATE"); err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("SET"); err != nil {
+		return nil, err
+	}
+	if clause.updates, err = p.parseAssignments(); err != nil {
+		return nil, err
+	}
+
+	return clause, nil
+}
+
+func (p *memoryParser) parseUpdate() (memoryStatement, error) {
+	var err error
+	stmt := memoryUpdate{}
+
+	if stmt.table, err = p.parseTableRef(); err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("SET"); err != nil {
+		return nil, err
+	}
+	if stmt.updates, err = p.parseAssignments(); err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("WHERE") {
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if stmt.returning, err = p.parseReturning(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+func (p *memoryParser) parseDelete() (memoryStatement, error) {
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+
+	var err error
+	stmt := memoryDelete{}
+	if stmt.table, err = p.parseTableRef(); err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("WHERE") {
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if stmt.returning, err = p.parseReturning(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+func (p *memoryParser) parseAssignments() ([]memoryAssignment, error) {
+	var assignments []memoryAssignment
+
+	for {
+		column, err := p.expectIdent()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol("="); err != nil {
+			return nil, err
+		}
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		assignments = append(assignments, memoryAssignment{column: column, value: value})
+		if !p.acceptSymbol(",") {
+			return assignments, nil
+		}
+	}
+}
+
+func (p *memoryParser) parseIdentList() ([]string, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+
+	var idents []string
+	for {
+		ident, err := p.expectIdent()
+		if err != nil {
+			return nil, err
+		}
+		idents = append(idents, ident)
+
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	return idents, p.expectSymbol(")")
+}
+
+func (p *memoryParser) parseExprList() ([]memoryExpr, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+
+	var exprs []memoryExpr
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	return exprs, p.expectSymbol(")")
+}
+
+// https://www.postgresql.org/docs/current/sql-syntax-lexical.html#SQL-PRECEDENCE
+func (p *memoryParser) parseExpr() (memoryExpr, error) {
+	return p.parseOr()
+}
+
+func (p *memoryParser) parseOr() (memoryExpr, error) {
+	lhs, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptKeyword("OR") {
+		rhs, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		lhs = memoryLogicalExpr{and: false, lhs: lhs, rhs: rhs}
+	}
+
+	return lhs, nil
+}
+
+func (p *memoryParser) parseAnd() (memoryExpr, error) {
+	lhs, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptKeyword("AND") {
+		rhs, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		lhs = memoryLogicalExpr{and: true, lhs: lhs, rhs: rhs}
+	}
+
+	return lhs, nil
+}
+
+func (p *memoryParser) parseNot() (memoryExpr, error) {
+	if p.acceptKeyword("NOT") {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return memoryNotExpr{expr: expr}, nil
+	}
+
+	return p.parsePredicate()
+}
+
+func (p *memoryParser) parsePredicate() (memoryExpr, error) {
+	lhs, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind == memorySymbol {
+		switch tok.text {
+		case "=", "<>", "!=", "<", "<=", ">", ">=":
+			p.pos++
+			rhs, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			op := tok.text
+			if op == "!=" {
+				op = "<>"
+			}
+			return memoryComparisonExpr{op: op, lhs: lhs, rhs: rhs}, nil
+		}
+	}
+
+	if p.acceptKeyword("IS") {
+		not := p.acceptKeyword("NOT")
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		return memoryIsNullExpr{expr: lhs, not: not}, nil
+	}
+
+	not := p.acceptKeyword("NOT")
+	switch {
+	case p.acceptKeyword("IN"):
+		values, err := p.parseExprList()
+		if err != nil {
+			return nil, err
+		}
+		return memoryInExpr{expr: lhs, values: values, not: not}, nil
+	case p.acceptKeyword("LIKE"):
+		pattern, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return memoryLikeExpr{expr: lhs, pattern: pattern, not: not}, nil
+	case p.acceptKeyword("ILIKE"):
+		pattern, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return memoryLikeExpr{expr: lhs, pattern: pattern, not: not, insensitive: true}, nil
+	case p.acceptKeyword("BETWEEN"):
+		low, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+		high, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return memoryBetweenExpr{expr: lhs, low: low, high: high, not: not}, nil
+	}
+
+	if not {
+		return nil, p.unexpected()
+	}
+
+	return lhs, nil
+}
+
+func (p *memoryParser) parseOperand() (memoryExpr, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case memoryNumber:
+		return parseMemoryNumber(tok, false)
+	case memoryString:
+		return memoryLiteralExpr{value: tok.text}, nil
+	case memoryParam:
+		index, err := strconv.Atoi(tok.text)
+		if err != nil || index < 1 {
+			return nil, p.unexpectedToken(tok)
+		}
+		return memoryParamExpr{index: index}, nil
+	case memorySymbol:
+		switch tok.text {
+		case "(":
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expectSymbol(")")
+		case "-":
+			number := p.next()
+			if number.kind != memoryNumber {
+				return nil, p.unexpectedToken(number)
+			}
+			return parseMemoryNumber(number, true)
+		}
+	case memoryIdent, memoryQuotedIdent:
+		return p.parseIdentOperand(tok)
+	}
+
+	return nil, p.unexpectedToken(tok)
+}
+
+func (p *memoryParser) parseIdentOperand(tok memoryToken) (memoryExpr, error) {
+	if tok.kind == memoryIdent {
+		switch strings.ToUpper(tok.text) {
+		case "NULL":
+			return memoryLiteralExpr{value: nil}, nil
+		case "TRUE":
+			return memoryLiteralExpr{value: true}, nil
+		case "FALSE":
+			return memoryLiteralExpr{value: false}, nil
+		}
+		if memoryReservedKeywords[strings.ToUpper(tok.text)] {
+			return nil, p.unexpectedToken(tok)
+		}
+	}
+
+	name := identText(tok)
+	if p.acceptSymbol("(") {
+		return p.parseFunction(name)
+	}
+
+	if p.acceptSymbol(".") {
+		column, err := p.expectIdent()
+		if err != nil {
+			return nil, err
+		}
+		return memoryColumnExpr{table: name, name: column}, nil
+	}
+
+	return memoryColumnExpr{name: name}, nil
+}
+
+func (p *memoryParser) parseFunction(name string) (memoryExpr, error) {
+	function := strings.ToUpper(name)
+	switch function {
+	case "COUNT", "SUM", "AVG", "MIN", "MAX":
+	default:
+		return nil, memoryErrorf(memoryUndefinedFunction, "function %s does not exist", name)
+	}
+
+	aggregate := memoryAggregateExpr{function: function}
+	if p.acceptSymbol("*") {
+		if function != "COUNT" {
+			return nil, memoryErrorf(memorySyntaxError, "%s(*) is not allowed", function)
+		}
+		aggregate.star = true
+		return aggregate, p.expectSymbol(")")
+	}
+
+	aggregate.distinct = p.acceptKeyword("DISTINCT")
+	arg, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	aggregate.arg = arg
+
+	return aggregate, p.expectSymbol(")")
+}
+
+func parseMemoryNumber(tok memoryToken, negative bool) (memoryExpr, error) {
+	text := tok.text
+	if negative {
+		text = "-" + text
+	}
+
+	if !strings.Contains(text, ".") {
+		if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return memoryLiteralExpr{value: i}, nil
+		}
+	}
+
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, memoryErrorf(memorySyntaxError, "invalid number %q", text)
+	}
+	return memoryLiteralExpr{value: f}, nil
+}
+
+// Unquoted identifiers are folded to lower case like Postgres does.
+func identText(tok memoryToken) string {
+	if tok.kind == memoryQuotedIdent {
+		return tok.text
+	}
+
+	return strings.ToLower(tok.text)
+}
+
+func (p *memoryParser) peek() memoryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *memoryParser) next() memoryToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != memoryEof {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *memoryParser) peekKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == memoryIdent && strings.EqualFold(tok.text, keyword)
+}
+
+func (p *memoryParser) acceptKeyword(keyword string) bool {
+	if !p.peekKeyword(keyword) {
+		return false
+	}
+
+	p.pos++
+	return true
+}
+
+func (p *memoryParser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return p.unexpected()
+	}
+
+	return nil
+}
+
+func (p *memoryParser) peekSymbol(symbol string) bool {
+	tok := p.peek()
+	return tok.kind == memorySymbol && tok.text == symbol
+}
+
+func (p *memoryParser) acceptSymbol(symbol string) bool {
+	if !p.peekSymbol(symbol) {
+		return false
+	}
+
+	p.pos++
+	return true
+}
+
+func (p *memoryParser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.unexpected()
+	}
+
+	return nil
+}
+
+func (p *memoryParser) peekAlias() bool {
+	tok := p.peek()
+	if tok.kind == memoryQuotedIdent {
+		return true
+	}
+
+	return tok.kind == memoryIdent && !memoryReservedKeywords[strings.ToUpper(tok.text)]
+}
+
+func (p *memoryParser) expectIdent() (string, error) {
+	tok := p.next()
+	if tok.kind == memoryQuotedIdent {
+		return tok.text, nil
+	}
+	if tok.kind != memoryIdent || memoryReservedKeywords[strings.ToUpper(tok.text)] {
+		return "", p.unexpectedToken(tok)
+	}
+
+	return identText(tok), nil
+}
+
+func (p *memoryParser) unexpected() error {
+	return p.unexpectedToken(p.peek())
+}
+
+func (p *memoryParser) unexpectedToken(tok memoryToken) error {
+	if tok.kind == memoryEof {
+		return memoryErrorf(memorySyntaxError, "syntax error at end of input")
+	}
+
+	return memoryErrorf(memorySyntaxError, "syntax error at or near %q (position %d)", tok.text, tok.pos)
+}
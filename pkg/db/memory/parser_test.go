@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizeMemorySql(t *testing.T) {
+	assert := assert.New(t)
+
+	tokens, err := tokenizeMemorySql(`SELECT "Quoted""Name", 'it''s' -- comment
+	FROM t WHERE a <> $12 AND b >= -1.5;`)
+	assert.Nil(err)
+
+	var texts []string
+	for _, tok := range tokens {
+		texts = append(texts, tok.text)
+	}
+	assert.Equal([]string{"SELECT", `Quoted"Name`, ",", "it's", "FROM", "t", "WHERE", "a", "<>", "12", "AND", "b", ">=", "-", "1.5", ";", ""}, texts)
+	assert.Equal(memoryQuotedIdent, tokens[1].kind)
+	assert.Equal(memoryString, tokens[3].kind)
+	assert.Equal(memoryParam, tokens[9].kind)
+	assert.Equal(memoryEof, tokens[len(tokens)-1].kind)
+}
+
+func TestTokenizeMemorySql_Errors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := tokenizeMemorySql("SELECT 'unterminated")
+	assert.NotNil(err)
+
+	_, err = tokenizeMemorySql("SELECT $ FROM t")
+	assert.NotNil(err)
+
+	_, err = tokenizeMemorySql("SELECT a FROM t WHERE a ~ b")
+	assert.NotNil(err)
+}
+
+func TestParseMemorySql_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	stmt, err := parseMemorySql("SELECT t.*, COUNT(DISTINCT b) AS total FROM t AS a LEFT JOIN u ON a.id = u.id WHERE NOT a.x IS NULL GROUP BY a.id HAVING COUNT(*) > 1 ORDER BY total DESC NULLS LAST LIMIT 2 OFFSET 3")
+	assert.Nil(err)
+
+	s, ok := stmt.(memorySelect)
+	assert.True(ok)
+	assert.Equal(memorySelectItem{star: true, starTable: "t"}, s.items[0])
+	assert.Equal("total", s.items[1].alias)
+	assert.Equal(memoryAggregateExpr{function: "COUNT", distinct: true, arg: memoryColumnExpr{name: "b"}}, s.items[1].expr)
+	assert.Equal(&memoryTableRef{name: "t", alias: "a"}, s.from)
+	assert.Equal("LEFT", s.joins[0].kind)
+	assert.Equal(memoryTableRef{name: "u", alias: "u"}, s.joins[0].table)
+	assert.Equal(memoryNotExpr{expr: memoryIsNullExpr{expr: memoryColumnExpr{table: "a", name: "x"}}}, s.where)
+	assert.Equal([]memoryExpr{memoryColumnExpr{table: "a", name: "id"}}, s.groupBy)
+	assert.True(s.orderBy[0].desc)
+	assert.False(*s.orderBy[0].nullsFirst)
+	assert.Equal(int64(2), *s.limit)
+	assert.Equal(int64(3), *s.offset)
+}
+
+func TestParseMemorySql_Insert(t *testing.T) {
+	assert := assert.New(t)
+
+	stmt, err := parseMemorySql("INSERT INTO t (a, b) VALUES ($1, $2), ($3, NULL) ON CONFLICT (a) DO UPDATE SET b = EXCLUDED.b RETURNING (xmax = 0) AS inserted")
+	assert.Nil(err)
+
+	s, ok := stmt.(memoryInsert)
+	assert.True(ok)
+	assert.Equal("t", s.table)
+	assert.Equal([]string{"a", "b"}, s.columns)
+	assert.Equal([]memoryExpr{memoryParamExpr{index: 3}, memoryLiteralExpr{value: nil}}, s.rows[1])
+	assert.Equal([]string{"a"}, s.onConflict.columns)
+	assert.Equal([]memoryAssignment{{column: "b", value: memoryColumnExpr{table: "excluded", name: "b"}}}, s.onConflict.updates)
+	assert.Equal("inserted", s.returning[0].alias)
+}
+
+func TestParseMemorySql_Savepoints(t *testing.T) {
+	assert := assert.New(t)
+
+	stmt, err := parseMemorySql("SAVEPOINT sp_1")
+	assert.Nil(err)
+	assert.Equal(memorySavepoint{kind: memoryCreateSavepoint, name: "sp_1"}, stmt)
+
+	stmt, err = parseMemorySql("RELEASE SAVEPOINT sp_1")
+	assert.Nil(err)
+	assert.Equal(memorySavepoint{kind: memoryReleaseSavepoint, name: "sp_1"}, stmt)
+
+	stmt, err = parseMemorySql("ROLLBACK TO SAVEPOINT sp_1")
+	assert.Nil(err)
+	assert.Equal(memorySavepoint{kind: memoryRollbackToSavepoint, name: "sp_1"}, stmt)
+}
+
+func TestParseMemorySql_Notify(t *testing.T) {
+	assert := assert.New(t)
+
+	stmt, err := parseMemorySql("SELECT pg_notify($1, 'payload')")
+	assert.Nil(err)
+	expected := memoryNotify{
+		channel: memoryParamExpr{index: 1},
+		payload: memoryLiteralExpr{value: "payload"},
+	}
+	assert.Equal(expected, stmt)
+
+	_, err = parseMemorySql("SELECT pg_notify($1)")
+	assertMemoryError(t, err, memorySyntaxError)
+}
+
+func TestParseMemorySql_Errors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := parseMemorySql("CREATE TABLE t (a text)")
+	assertMemoryError(t, err, memoryFeatureNotSupported)
+
+	_, err = parseMemorySql("SELECT * FROM my_script($1)")
+	assertMemoryError(t, err, memoryUndefinedFunction)
+
+	_, err = parseMemorySql("INSERT INTO t (a, b) VALUES ($1)")
+	assertMemoryError(t, err, memorySyntaxError)
+
+	_, err = parseMemorySql("SELECT a FROM t extra tokens")
+	assertMemoryError(t, err, memorySyntaxError)
+
+	_, err = parseMemorySql("SELECT SUM(*) FROM t")
+	assert.NotNil(err)
+}
@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/db"
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var memoryTestPlayersTable = db.RepositoryTable{
+	Name:     "players",
+	IdColumn: "id",
+	OrderBy:  []db.OrderBy{{Column: "name"}},
+}
+
+func newTestRepository(t *testing.T) db.Repository[memoryTestPlayer, uuid.UUID] {
+	repo, err := db.NewRepository[memoryTestPlayer, uuid.UUID](db.NewQueryExecutor(newTestMemoryDatabase(t)), memoryTestPlayersTable)
+	assert.Nil(t, err)
+	return repo
+}
+
+func newTestPlayer(name string, level int) memoryTestPlayer {
+	return memoryTestPlayer{Name: name, Level: level}
+}
+
+func TestRepository_CreateAndGet(t *testing.T) {
+	assert := assert.New(t)
+	repo := newTestRepository(t)
+
+	created, err := repo.Create(context.Background(), newTestPlayer("name", 2))
+	assert.Nil(err)
+	assert.NotEqual(uuid.Nil, created.Id)
+	assert.Equal("name", created.Name)
+	assert.Equal(2, created.Level)
+
+	actual, err := repo.Get(context.Background(), created.Id)
+	assert.Nil(err)
+	assert.Equal(created, actual)
+}
+
+func TestRepository_Create_Error(t *testing.T) {
+	assert := assert.New(t)
+	repo := newTestRepository(t)
+
+	_, err := repo.Create(context.Background(), newTestPlayer("name", 2))
+	assert.Nil(err)
+
+	_, err = repo.Create(context.Background(), newTestPlayer("name", 3))
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbEntityCreationFailure))
+}
+
+func TestRepository_Get_NotFound(t *testing.T) {
+	assert := assert.New(t)
+	repo := newTestRepository(t)
+
+	_, err := repo.Get(context.Background(), uuid.New())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbEntityGetFailure))
+	cause := errors.Unwrap(errors.Unwrap(err))
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrNoRowsReturnedForSqlQuery))
+}
+
+func TestRepository_Update(t *testing.T) {
+	assert := assert.New(t)
+	repo := newTestRepository(t)
+
+	created, err := repo.Create(context.Background(), newTestPlayer("name", 2))
+	assert.Nil(err)
+
+	updated, err := repo.Update(context.Background(), created.Id, newTestPlayer("other", 3))
+	assert.Nil(err)
+	assert.Equal(created.Id, updated.Id)
+	assert.Equal("other", updated.Name)
+	assert.Equal(3, updated.Level)
+
+	updated, err = repo.Update(context.Background(), created.Id, newTestPlayer("ignored", 4), "level")
+	assert.Nil(err)
+	assert.Equal("other", updated.Name)
+	assert.Equal(4, updated.Level)
+
+	_, err = repo.Update(context.Background(), uuid.New(), newTestPlayer("name", 2))
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbEntityUpdateFailure))
+
+	_, err = repo.Update(context.Background(), created.Id, newTestPlayer("name", 2), "unknown")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbRequestCreationFailed))
+}
+
+func TestRepository_Delete(t *testing.T) {
+	assert := assert.New(t)
+	repo := newTestRepository(t)
+
+	created, err := repo.Create(context.Background(), newTestPlayer("name", 2))
+	assert.Nil(err)
+
+	assert.Nil(repo.Delete(context.Background(), created.Id))
+	exists, err := repo.Exists(context.Background(), created.Id)
+	assert.Nil(err)
+	assert.False(exists)
+
+	err = repo.Delete(context.Background(), created.Id)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbEntityDeletionFailure))
+}
+
+func TestRepository_ListAndCount(t *testing.T) {
+	assert := assert.New(t)
+	repo := newTestRepository(t)
+
+	players, err := repo.List(context.Background(), nil)
+	assert.Nil(err)
+	assert.Equal([]memoryTestPlayer{}, players)
+
+	for _, name := range []string{"carol", "alice", "bob"} {
+		_, err := repo.Create(context.Background(), newTestPlayer(name, len(name)))
+		assert.Nil(err)
+	}
+
+	players, err = repo.List(context.Background(), nil)
+	assert.Nil(err)
+	var names []string
+	for _, player := range players {
+		names = append(names, player.Name)
+	}
+	assert.Equal([]string{"alice", "bob", "carol"}, names)
+
+	fb := db.NewComparisonFilterBuilder()
+	fb.SetKey("level")
+	fb.SetOperator(db.GreaterThan)
+	fb.SetValue(3)
+	f, err := fb.Build()
+	assert.Nil(err)
+
+	players, err = repo.List(context.Background(), f)
+	assert.Nil(err)
+	assert.Equal(2, len(players))
+
+	count, err := repo.Count(context.Background(), nil)
+	assert.Nil(err)
+	assert.Equal(3, count)
+	count, err = repo.Count(context.Background(), f)
+	assert.Nil(err)
+	assert.Equal(2, count)
+}
+
+func TestRepository_Exists(t *testing.T) {
+	assert := assert.New(t)
+	repo := newTestRepository(t)
+
+	created, err := repo.Create(context.Background(), newTestPlayer("name", 2))
+	assert.Nil(err)
+
+	exists, err := repo.Exists(context.Background(), created.Id)
+	assert.Nil(err)
+	assert.True(exists)
+
+	exists, err = repo.Exists(context.Background(), uuid.New())
+	assert.Nil(err)
+	assert.False(exists)
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/KnoblauchPilze/go-game/pkg/db"
+)
+
+type memoryRows struct {
+	columns []string
+	rows    [][]interface{}
+	current int
+}
+
+func newMemoryRows(columns []string, rows [][]interface{}) db.DriverRows {
+	return &memoryRows{
+		columns: columns,
+		rows:    rows,
+		current: -1,
+	}
+}
+
+func (r *memoryRows) Columns() []string {
+	return r.columns
+}
+
+func (r *memoryRows) Next() bool {
+	if r.current < len(r.rows) {
+		r.current++
+	}
+
+	return r.current < len(r.rows)
+}
+
+func (r *memoryRows) Scan(dest ...interface{}) error {
+	if r.current < 0 || r.current >= len(r.rows) {
+		return fmt.Errorf("no row to scan")
+	}
+
+	row := r.rows[r.current]
+	if len(dest) != len(row) {
+		return fmt.Errorf("scan received wrong number of arguments, got %d but expected %d", len(dest), len(row))
+	}
+
+	for id, value := range row {
+		if err := assignMemoryValue(dest[id], value); err != nil {
+			return fmt.Errorf("can't scan into dest[%d]: %v", id, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *memoryRows) Close() {
+	r.current = len(r.rows)
+}
+
+// The errors are reported when the query is executed.
+func (r *memoryRows) Err() error {
+	return nil
+}
+
+// Mirrors the conversions pgx performs for the types stored by the
+// in-memory database.
+// https://github.com/jackc/pgx/blob/v3.6.2/query.go#L211
+func assignMemoryValue(dest interface{}, value interface{}) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(value)
+	}
+	if out, ok := dest.(*interface{}); ok {
+		*out = value
+		return nil
+	}
+
+	ptr := reflect.ValueOf(dest)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		return fmt.Errorf("destination %T is not a pointer", dest)
+	}
+	target := ptr.Elem()
+
+	if value == nil {
+		if target.Kind() != reflect.Ptr {
+			return fmt.Errorf("cannot assign NULL to %T", dest)
+		}
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	if target.Kind() == reflect.Ptr {
+		allocated := reflect.New(target.Type().Elem())
+		if err := assignMemoryValue(allocated.Interface(), value); err != nil {
+			return err
+		}
+		target.Set(allocated)
+		return nil
+	}
+
+	source := reflect.ValueOf(value)
+	if source.Type().AssignableTo(target.Type()) {
+		target.Set(source)
+		return nil
+	}
+
+	// Go converts integers to strings as runes so only compatible kinds
+	// are converted.
+	if memoryKindsCompatible(source.Kind(), target.Kind()) && source.Type().ConvertibleTo(target.Type()) {
+		target.Set(source.Convert(target.Type()))
+		return nil
+	}
+
+	return fmt.Errorf("cannot assign %v (%T) to %T", value, value, dest)
+}
+
+func memoryKindsCompatible(source reflect.Kind, target reflect.Kind) bool {
+	switch {
+	case isMemoryNumericKind(source):
+		return isMemoryNumericKind(target)
+	case source == reflect.String:
+		return target == reflect.String || target == reflect.Slice
+	default:
+		return source == target
+	}
+}
+
+func isMemoryNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
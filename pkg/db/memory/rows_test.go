@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRows_Next(t *testing.T) {
+	assert := assert.New(t)
+
+	r := newMemoryRows(nil, nil)
+	assert.False(r.Next())
+	assert.NotNil(r.Scan())
+
+	r = newMemoryRows(nil, [][]interface{}{{int64(1)}, {int64(2)}})
+	assert.True(r.Next())
+	assert.True(r.Next())
+	assert.False(r.Next())
+	assert.False(r.Next())
+}
+
+func TestMemoryRows_Close(t *testing.T) {
+	assert := assert.New(t)
+
+	r := newMemoryRows(nil, [][]interface{}{{int64(1)}})
+	r.Close()
+	assert.False(r.Next())
+}
+
+func TestMemoryRows_Scan(t *testing.T) {
+	assert := assert.New(t)
+
+	id := uuid.New()
+	now := time.Now()
+	r := newMemoryRows(nil, [][]interface{}{{id.String(), "name", int64(12), 2.5, true, now, nil, "text"}})
+	assert.True(r.Next())
+
+	var outId uuid.UUID
+	var name string
+	var level int
+	var score float32
+	var active bool
+	var createdAt time.Time
+	var missing *string
+	var text *string
+	err := r.Scan(&outId, &name, &level, &score, &active, &createdAt, &missing, &text)
+	assert.Nil(err)
+	assert.Equal(id, outId)
+	assert.Equal("name", name)
+	assert.Equal(12, level)
+	assert.Equal(float32(2.5), score)
+	assert.True(active)
+	assert.Equal(now, createdAt)
+	assert.Nil(missing)
+	assert.Equal("text", *text)
+}
+
+func TestMemoryRows_Scan_Errors(t *testing.T) {
+	assert := assert.New(t)
+
+	r := newMemoryRows(nil, [][]interface{}{{int64(12)}, {nil}})
+	assert.True(r.Next())
+
+	var str string
+	assert.NotNil(r.Scan(&str))
+	assert.NotNil(r.Scan(str))
+	assert.NotNil(r.Scan(&str, &str))
+
+	var value interface{}
+	assert.Nil(r.Scan(&value))
+	assert.Equal(int64(12), value)
+
+	assert.True(r.Next())
+	var level int
+	assert.NotNil(r.Scan(&level))
+}
@@ -0,0 +1,48 @@
+package memory
+
+import "fmt"
+
+type ColumnType int
+
+const (
+	Text ColumnType = iota
+	Integer
+	Float
+	Boolean
+	Timestamp
+	Uuid
+)
+
+type Column struct {
+	Name       string
+	Type       ColumnType
+	PrimaryKey bool
+	Unique     bool
+	NotNull    bool
+	Default    func() interface{}
+}
+
+// Describes a table of the in-memory database: only single column
+// constraints are supported.
+type Table struct {
+	Name    string
+	Columns []Column
+}
+
+func (c Column) unique() bool {
+	return c.PrimaryKey || c.Unique
+}
+
+func (c Column) notNull() bool {
+	return c.PrimaryKey || c.NotNull
+}
+
+// https://www.postgresql.org/docs/current/ddl-constraints.html
+// Mirrors the names Postgres generates for the constraints.
+func (c Column) constraintName(table string) string {
+	if c.PrimaryKey {
+		return fmt.Sprintf("%s_pkey", table)
+	}
+
+	return fmt.Sprintf("%s_%s_key", table, c.Name)
+}
@@ -0,0 +1,262 @@
+package memory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func normalizeMemoryValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint64:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case uuid.UUID:
+		return v.String()
+	default:
+		return v
+	}
+}
+
+func coerceMemoryValue(value interface{}, columnType ColumnType) (interface{}, error) {
+	value = normalizeMemoryValue(value)
+	if value == nil {
+		return nil, nil
+	}
+
+	switch columnType {
+	case Text:
+		if str, ok := value.(string); ok {
+			return str, nil
+		}
+		return memoryValueToText(value)
+	case Integer:
+		return convertMemoryValue(value, int64(0))
+	case Float:
+		return convertMemoryValue(value, float64(0))
+	case Boolean:
+		return convertMemoryValue(value, false)
+	case Timestamp:
+		return convertMemoryValue(value, time.Time{})
+	case Uuid:
+		str, ok := value.(string)
+		if !ok {
+			return nil, memoryErrorf(memoryInvalidTextRepresentation, "invalid input syntax for type uuid: %v", value)
+		}
+		id, err := uuid.Parse(str)
+		if err != nil {
+			return nil, memoryErrorf(memoryInvalidTextRepresentation, "invalid input syntax for type uuid: %q", str)
+		}
+		return id.String(), nil
+	default:
+		return nil, memoryErrorf(memoryFeatureNotSupported, "unsupported column type %d", columnType)
+	}
+}
+
+// Converts the value to the type of the reference: this is mostly
+// used to interpret the text arguments sent for non-text columns.
+func convertMemoryValue(value interface{}, reference interface{}) (interface{}, error) {
+	value = normalizeMemoryValue(value)
+
+	switch reference.(type) {
+	case int64:
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case float64:
+			if v == float64(int64(v)) {
+				return int64(v), nil
+			}
+		case string:
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return i, nil
+			}
+		}
+	case float64:
+		switch v := value.(type) {
+		case int64:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, nil
+			}
+		}
+	case bool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, nil
+			}
+		}
+	case time.Time:
+		switch v := value.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t, nil
+			}
+		}
+	case string:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case []byte:
+			return string(v), nil
+		}
+	case []byte:
+		switch v := value.(type) {
+		case []byte:
+			return v, nil
+		case string:
+			return []byte(v), nil
+		}
+	}
+
+	return nil, memoryErrorf(memoryInvalidTextRepresentation, "cannot convert %v (%T) to %T", value, value, reference)
+}
+
+func compareMemoryValues(lhs interface{}, rhs interface{}) (int, error) {
+	lhs = normalizeMemoryValue(lhs)
+	rhs = normalizeMemoryValue(rhs)
+
+	if _, ok := lhs.(string); ok {
+		if _, ok := rhs.(string); !ok {
+			converted, err := convertMemoryValue(lhs, rhs)
+			if err != nil {
+				return 0, err
+			}
+			lhs = converted
+		}
+	} else if _, ok := rhs.(string); ok {
+		converted, err := convertMemoryValue(rhs, lhs)
+		if err != nil {
+			return 0, err
+		}
+		rhs = converted
+	}
+
+	switch l := lhs.(type) {
+	case int64:
+		switch r := rhs.(type) {
+		case int64:
+			return compareOrdered(l, r), nil
+		case float64:
+			return compareOrdered(float64(l), r), nil
+		}
+	case float64:
+		switch r := rhs.(type) {
+		case int64:
+			return compareOrdered(l, float64(r)), nil
+		case float64:
+			return compareOrdered(l, r), nil
+		}
+	case string:
+		if r, ok := rhs.(string); ok {
+			return strings.Compare(l, r), nil
+		}
+	case bool:
+		if r, ok := rhs.(bool); ok {
+			if l == r {
+				return 0, nil
+			}
+			if !l {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	case time.Time:
+		if r, ok := rhs.(time.Time); ok {
+			return l.Compare(r), nil
+		}
+	case []byte:
+		if r, ok := rhs.([]byte); ok {
+			return bytes.Compare(l, r), nil
+		}
+	}
+
+	return 0, memoryErrorf(memoryUndefinedFunction, "cannot compare %T with %T", lhs, rhs)
+}
+
+func compareOrdered[T int64 | float64](lhs T, rhs T) int {
+	if lhs < rhs {
+		return -1
+	}
+	if lhs > rhs {
+		return 1
+	}
+	return 0
+}
+
+// Used to detect duplicates when grouping rows or computing distinct
+// aggregates.
+func memoryValueKey(value interface{}) string {
+	value = normalizeMemoryValue(value)
+	if t, ok := value.(time.Time); ok {
+		return fmt.Sprintf("time:%s", t.UTC().Format(time.RFC3339Nano))
+	}
+
+	return fmt.Sprintf("%T:%v", value, value)
+}
+
+// https://www.postgresql.org/docs/current/functions-matching.html#FUNCTIONS-LIKE
+func likePatternToRegex(pattern string, caseInsensitive bool) (*regexp.Regexp, error) {
+	var out strings.Builder
+	if caseInsensitive {
+		out.WriteString("(?i)")
+	}
+	out.WriteString("^")
+
+	escaped := false
+	for _, c := range pattern {
+		switch {
+		case escaped:
+			out.WriteString(regexp.QuoteMeta(string(c)))
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '%':
+			out.WriteString("(?s:.*)")
+		case c == '_':
+			out.WriteString("(?s:.)")
+		default:
+			out.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	out.WriteString("$")
+	return regexp.Compile(out.String())
+}
+
+// The arguments bound to a text column are already converted by the
+// query builders: the remaining values are formatted as JSON like
+// postgres receives them.
+func memoryValueToText(value interface{}) (string, error) {
+	raw, err := json.Marshal(value)
+	return string(raw), err
+}
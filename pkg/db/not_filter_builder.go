@@ -0,0 +1,43 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+type NotFilterBuilder interface {
+	FilterBuilder
+
+	SetFilter(filter Filter) error
+}
+
+type notFilterBuilder struct {
+	filter Filter
+}
+
+func NewNotFilterBuilder() NotFilterBuilder {
+	return &notFilterBuilder{}
+}
+
+func (b *notFilterBuilder) SetFilter(filter Filter) error {
+	if filter == nil || !filter.Valid() {
+		return errors.NewCode(errors.ErrInvalidSqlFilter)
+	}
+
+	b.filter = filter
+	return nil
+}
+
+func (b *notFilterBuilder) Build() (Filter, error) {
+	if b.filter == nil {
+		return filterImpl{}, errors.WrapCode(errors.NewCode(errors.ErrNoFilterInSqlCombination), errors.ErrSqlTranslationFailed)
+	}
+
+	filter := filterImpl{
+		sqlCode: fmt.Sprintf("NOT (%s)", b.filter.ToSql()),
+		args:    b.filter.Args(),
+	}
+
+	return filter, nil
+}
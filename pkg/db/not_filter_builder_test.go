@@ -0,0 +1,47 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotFilterBuilder_SetFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewNotFilterBuilder()
+
+	err := b.SetFilter(nil)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlFilter))
+
+	err = b.SetFilter(filterImpl{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlFilter))
+
+	err = b.SetFilter(filterImpl{sqlCode: "someSqlCode"})
+	assert.Nil(err)
+}
+
+func TestNotFilterBuilder_Build_NoFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewNotFilterBuilder()
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrNoFilterInSqlCombination))
+}
+
+func TestNotFilterBuilder_Build(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewNotFilterBuilder()
+	b.SetFilter(filterImpl{sqlCode: "key in ($1, $2)", args: []interface{}{1, 2}})
+
+	filter, err := b.Build()
+	assert.Nil(err)
+	assert.True(filter.Valid())
+	assert.Equal("NOT (key in ($1, $2))", filter.ToSql())
+	assert.Equal([]interface{}{1, 2}, filter.Args())
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+type NullFilterBuilder interface {
+	FilterBuilder
+
+	SetKey(key string) error
+	SetNegated(negated bool)
+}
+
+type nullFilterBuilder struct {
+	key     string
+	negated bool
+}
+
+func NewNullFilterBuilder() NullFilterBuilder {
+	return &nullFilterBuilder{}
+}
+
+func (b *nullFilterBuilder) SetKey(key string) error {
+	if len(key) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlComparisonKey)
+	}
+
+	b.key = key
+	return nil
+}
+
+func (b *nullFilterBuilder) SetNegated(negated bool) {
+	b.negated = negated
+}
+
+func (b *nullFilterBuilder) Build() (Filter, error) {
+	if len(b.key) == 0 {
+		return filterImpl{}, errors.WrapCode(errors.NewCode(errors.ErrInvalidSqlComparisonKey), errors.ErrSqlTranslationFailed)
+	}
+
+	sqlFilter := fmt.Sprintf("%s IS NULL", b.key)
+	if b.negated {
+		sqlFilter = fmt.Sprintf("%s IS NOT NULL", b.key)
+	}
+
+	filter := filterImpl{
+		sqlCode: sqlFilter,
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullFilterBuilder_SetKey(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewNullFilterBuilder()
+
+	err := b.SetKey("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlComparisonKey))
+
+	err = b.SetKey("key")
+	assert.Nil(err)
+}
+
+func TestNullFilterBuilder_Build_NoKey(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewNullFilterBuilder()
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrInvalidSqlComparisonKey))
+}
+
+func TestNullFilterBuilder_Build(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewNullFilterBuilder()
+	b.SetKey("mail")
+
+	filter, err := b.Build()
+	assert.Nil(err)
+	assert.True(filter.Valid())
+	assert.Equal("mail IS NULL", filter.ToSql())
+	assert.Nil(filter.Args())
+}
+
+func TestNullFilterBuilder_Build_Negated(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewNullFilterBuilder()
+	b.SetKey("mail")
+	b.SetNegated(true)
+
+	filter, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("mail IS NOT NULL", filter.ToSql())
+}
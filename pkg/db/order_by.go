@@ -0,0 +1,146 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+type SortOrder int
+
+const (
+	Ascending SortOrder = iota
+	Descending
+)
+
+type NullsOrder int
+
+const (
+	NullsDefault NullsOrder = iota
+	NullsFirst
+	NullsLast
+)
+
+type OrderBy struct {
+	Column string
+	Order  SortOrder
+	Nulls  NullsOrder
+	// Set for the columns which can hold NULL values: only then does
+	// the seek predicate of the cursors look for them, which prevents
+	// an index range scan.
+	Nullable bool
+}
+
+func (o OrderBy) valid() error {
+	if len(o.Column) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlColumn)
+	}
+	if o.Order != Ascending && o.Order != Descending {
+		return errors.NewCode(errors.ErrInvalidSqlOrdering)
+	}
+	if o.Nulls != NullsDefault && o.Nulls != NullsFirst && o.Nulls != NullsLast {
+		return errors.NewCode(errors.ErrInvalidSqlOrdering)
+	}
+
+	return nil
+}
+
+// https://www.postgresql.org/docs/current/queries-order.html
+func (o OrderBy) toSql() string {
+	out := o.Column
+	if o.Order == Descending {
+		out += " DESC"
+	}
+
+	switch o.Nulls {
+	case NullsFirst:
+		out += " NULLS FIRST"
+	case NullsLast:
+		out += " NULLS LAST"
+	}
+
+	return out
+}
+
+func (o OrderBy) seekOperator() string {
+	if o.Order == Descending {
+		return "<"
+	}
+	return ">"
+}
+
+// nullsLast returns whether the NULL values come after the other ones
+// with this ordering: by default they are larger than any other value.
+func (o OrderBy) nullsLast() bool {
+	if o.Nulls == NullsDefault {
+		return o.Order == Ascending
+	}
+	return o.Nulls == NullsLast
+}
+
+// https://use-the-index-luke.com/no-offset
+// The NULL values can't be compared: the seek predicate uses IS NULL
+// checks instead, depending on where the ordering puts them.
+func seekFilter(orderBy []OrderBy, values []*string) Filter {
+	args := make([]interface{}, 0, len(values))
+	placeholders := make([]string, len(values))
+	for id, value := range values {
+		if value != nil {
+			args = append(args, *value)
+			placeholders[id] = fmt.Sprintf("$%d", len(args))
+		}
+	}
+
+	conditions := make([]string, 0, len(orderBy))
+	for id, order := range orderBy {
+		after, ok := seekAfter(order, placeholders[id])
+		if !ok {
+			continue
+		}
+
+		var terms []string
+		for prev := 0; prev < id; prev++ {
+			terms = append(terms, seekEqual(orderBy[prev], placeholders[prev]))
+		}
+		if len(terms) > 0 && strings.Contains(after, " OR ") {
+			after = fmt.Sprintf("(%s)", after)
+		}
+		terms = append(terms, after)
+
+		conditions = append(conditions, fmt.Sprintf("(%s)", strings.Join(terms, " AND ")))
+	}
+
+	sqlCode := strings.Join(conditions, " OR ")
+	if len(conditions) == 0 {
+		sqlCode = "FALSE"
+	}
+
+	return filterImpl{
+		sqlCode: sqlCode,
+		args:    args,
+	}
+}
+
+func seekEqual(order OrderBy, placeholder string) string {
+	if len(placeholder) == 0 {
+		return fmt.Sprintf("%s IS NULL", order.Column)
+	}
+	return fmt.Sprintf("%s = %s", order.Column, placeholder)
+}
+
+func seekAfter(order OrderBy, placeholder string) (string, bool) {
+	if len(placeholder) == 0 {
+		if order.nullsLast() {
+			return "", false
+		}
+		return fmt.Sprintf("%s IS NOT NULL", order.Column), true
+	}
+
+	after := fmt.Sprintf("%s %s %s", order.Column, order.seekOperator(), placeholder)
+	if order.Nullable && order.nullsLast() {
+		after += fmt.Sprintf(" OR %s IS NULL", order.Column)
+	}
+
+	return after, true
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderBy_Valid(t *testing.T) {
+	assert := assert.New(t)
+
+	o := OrderBy{}
+	assert.True(errors.IsErrorWithCode(o.valid(), errors.ErrInvalidSqlColumn))
+
+	o = OrderBy{Column: "column", Order: SortOrder(2)}
+	assert.True(errors.IsErrorWithCode(o.valid(), errors.ErrInvalidSqlOrdering))
+
+	o = OrderBy{Column: "column", Nulls: NullsOrder(3)}
+	assert.True(errors.IsErrorWithCode(o.valid(), errors.ErrInvalidSqlOrdering))
+
+	o = OrderBy{Column: "column", Order: Descending, Nulls: NullsLast}
+	assert.Nil(o.valid())
+}
+
+func TestOrderBy_ToSql(t *testing.T) {
+	assert := assert.New(t)
+
+	o := OrderBy{Column: "column"}
+	assert.Equal("column", o.toSql())
+
+	o.Order = Descending
+	assert.Equal("column DESC", o.toSql())
+
+	o.Nulls = NullsFirst
+	assert.Equal("column DESC NULLS FIRST", o.toSql())
+
+	o.Order = Ascending
+	o.Nulls = NullsLast
+	assert.Equal("column NULLS LAST", o.toSql())
+}
+
+func TestSeekFilter_SingleColumn(t *testing.T) {
+	assert := assert.New(t)
+
+	orderBy := []OrderBy{{Column: "id"}}
+
+	f := seekFilter(orderBy, cursorValues("12"))
+	assert.Equal("(id > $1)", f.ToSql())
+	assert.Equal([]interface{}{"12"}, f.Args())
+
+	orderBy[0].Nullable = true
+	f = seekFilter(orderBy, cursorValues("12"))
+	assert.Equal("(id > $1 OR id IS NULL)", f.ToSql())
+}
+
+func TestSeekFilter_MultiColumns(t *testing.T) {
+	assert := assert.New(t)
+
+	orderBy := []OrderBy{
+		{Column: "created_at", Order: Descending},
+		{Column: "id"},
+	}
+
+	f := seekFilter(orderBy, cursorValues("2023-01-01", "12"))
+	assert.Equal("(created_at < $1) OR (created_at = $1 AND id > $2)", f.ToSql())
+	assert.Equal([]interface{}{"2023-01-01", "12"}, f.Args())
+}
+
+func TestSeekFilter_NullValues(t *testing.T) {
+	assert := assert.New(t)
+
+	orderBy := []OrderBy{
+		{Column: "name", Nulls: NullsFirst, Nullable: true},
+		{Column: "created_at", Order: Descending, Nulls: NullsLast, Nullable: true},
+		{Column: "id", Order: Descending},
+	}
+
+	f := seekFilter(orderBy, cursorValues(nil, "2023-01-01", "12"))
+	expected := "(name IS NOT NULL) OR " +
+		"(name IS NULL AND (created_at < $1 OR created_at IS NULL)) OR " +
+		"(name IS NULL AND created_at = $1 AND id < $2)"
+	assert.Equal(expected, f.ToSql())
+	assert.Equal([]interface{}{"2023-01-01", "12"}, f.Args())
+
+	f = seekFilter(orderBy, cursorValues("alice", nil, nil))
+	expected = "(name > $1) OR " +
+		"(name = $1 AND created_at IS NULL AND id IS NOT NULL)"
+	assert.Equal(expected, f.ToSql())
+	assert.Equal([]interface{}{"alice"}, f.Args())
+}
+
+func TestSeekFilter_NoValueAfterCursor(t *testing.T) {
+	assert := assert.New(t)
+
+	orderBy := []OrderBy{{Column: "id"}}
+
+	f := seekFilter(orderBy, cursorValues(nil))
+	assert.Equal("FALSE", f.ToSql())
+	assert.Equal(0, len(f.Args()))
+}
+
+func cursorValues(values ...interface{}) []*string {
+	out := make([]*string, 0, len(values))
+	for _, value := range values {
+		if value == nil {
+			out = append(out, nil)
+			continue
+		}
+
+		str := value.(string)
+		out = append(out, &str)
+	}
+
+	return out
+}
@@ -1,9 +1,67 @@
 package db
 
-import "github.com/jackc/pgx"
+import (
+	"context"
+
+	"github.com/jackc/pgx"
+)
 
 type pgxDbConn interface {
 	Close()
-	Query(sql string, args ...interface{}) (*pgx.Rows, error)
-	Exec(sql string, arguments ...interface{}) (pgx.CommandTag, error)
+	AcquireEx(ctx context.Context) (pgxConn, error)
+	Release(conn pgxConn)
+	CopyFrom(tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error)
+}
+
+// A connection acquired from the pool: it is used exclusively until it
+// is released.
+type pgxConn interface {
+	QueryEx(ctx context.Context, sql string, options *pgx.QueryExOptions, args ...interface{}) (pgxRows, error)
+	ExecEx(ctx context.Context, sql string, options *pgx.QueryExOptions, arguments ...interface{}) (pgx.CommandTag, error)
+	BeginEx(ctx context.Context, txOptions *pgx.TxOptions) (*pgx.Tx, error)
+	Ping(ctx context.Context) error
+	IsAlive() bool
+	// The statements prepared on a connection are only known to it.
+	PrepareEx(ctx context.Context, name, sql string, opts *pgx.PrepareExOptions) (*pgx.PreparedStatement, error)
+	Deallocate(name string) error
+}
+
+// The rows of pgx also describe their columns.
+type pgxRows interface {
+	DriverRows
+	FieldDescriptions() []pgx.FieldDescription
+}
+
+type pgxConnPool struct {
+	*pgx.ConnPool
+}
+
+type pgxPooledConn struct {
+	*pgx.Conn
+}
+
+// https://github.com/jackc/pgx/blob/v3.6.2/conn_pool.go#L113
+// Contrary to the helpers of the pool (QueryEx, ExecEx...) the wait for
+// a connection stops at the deadline of the context. A cancellation is
+// not noticed though.
+func (p pgxConnPool) AcquireEx(ctx context.Context) (pgxConn, error) {
+	conn, err := p.ConnPool.AcquireEx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgxPooledConn{conn}, nil
+}
+
+func (p pgxConnPool) Release(conn pgxConn) {
+	p.ConnPool.Release(conn.(pgxPooledConn).Conn)
+}
+
+func (c pgxPooledConn) QueryEx(ctx context.Context, sql string, options *pgx.QueryExOptions, args ...interface{}) (pgxRows, error) {
+	rows, err := c.Conn.QueryEx(ctx, sql, options, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
 }
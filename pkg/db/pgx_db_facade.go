@@ -1,23 +1,40 @@
 package db
 
-import "github.com/jackc/pgx"
+import (
+	"context"
 
-type pgxDbFacade interface {
+	"github.com/jackc/pgx"
+)
+
+// The connections of a Driver: the queries acquire one of them to run.
+type DriverPool interface {
 	Close()
-	Query(sql string, args ...interface{}) (sqlRows, error)
-	Exec(sql string, arguments ...interface{}) (pgx.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (DriverRows, error)
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgx.CommandTag, error)
+	CopyFrom(tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error)
+	Begin(ctx context.Context) (DriverTx, error)
+	Ping(ctx context.Context) error
+}
+
+// Implemented by the facades which can prepare the statements they run
+// on their connections.
+type statementCacheFacade interface {
+	useStatementCache(cache *statementCache)
+	statementCacheSize() int
 }
 
 type pgxDbFacadeImpl struct {
 	pool pgxDbConn
+	// Nil unless the statements are prepared.
+	statements *statementCache
 }
 
 var pgxConnectionFunc = pgx.NewConnPool
 
-func newPgxDbFacadeImpl(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+func newPgxDbFacadeImpl(config pgx.ConnPoolConfig) (DriverPool, error) {
 	pool, err := pgxConnectionFunc(config)
 	f := pgxDbFacadeImpl{
-		pool: pool,
+		pool: pgxConnPool{pool},
 	}
 	return &f, err
 }
@@ -26,10 +43,137 @@ func (f *pgxDbFacadeImpl) Close() {
 	f.pool.Close()
 }
 
-func (f *pgxDbFacadeImpl) Query(sql string, args ...interface{}) (sqlRows, error) {
-	return f.pool.Query(sql, args...)
+// The connection is acquired with the context: when the pool is busy
+// the call fails once the deadline of the context is reached. It is
+// released when the rows are closed.
+// When the context is done while the query runs pgx sends a cancel
+// request to the server.
+func (f *pgxDbFacadeImpl) Query(ctx context.Context, sql string, args ...interface{}) (DriverRows, error) {
+	conn, err := f.pool.AcquireEx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := f.query(ctx, conn, sql, args)
+	if err != nil {
+		f.release(conn)
+		return nil, err
+	}
+
+	return &pooledRows{pgxRows: rows, release: func() { f.release(conn) }}, nil
+}
+
+func (f *pgxDbFacadeImpl) Exec(ctx context.Context, sql string, args ...interface{}) (pgx.CommandTag, error) {
+	conn, err := f.pool.AcquireEx(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer f.release(conn)
+
+	if f.statements != nil {
+		return f.statements.exec(ctx, conn, sql, args)
+	}
+
+	return conn.ExecEx(ctx, sql, nil, args...)
+}
+
+func (f *pgxDbFacadeImpl) CopyFrom(tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error) {
+	return f.pool.CopyFrom(tableName, columnNames, rowSrc)
+}
+
+// Same as the BeginEx of the pool but acquiring the connection with the
+// context: a dead connection is released and another one is tried.
+// https://github.com/jackc/pgx/blob/v3.6.2/conn_pool.go#L537
+func (f *pgxDbFacadeImpl) Begin(ctx context.Context) (DriverTx, error) {
+	for {
+		conn, err := f.pool.AcquireEx(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		tx, err := conn.BeginEx(ctx, nil)
+		if err != nil {
+			alive := conn.IsAlive()
+			f.release(conn)
+
+			if alive || ctx.Err() != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		return &pgxTxFacadeImpl{tx: tx, release: func() { f.release(conn) }}, nil
+	}
+}
+
+// https://github.com/jackc/pgx/blob/v3.6.2/conn.go#L2099
+func (f *pgxDbFacadeImpl) Ping(ctx context.Context) error {
+	conn, err := f.pool.AcquireEx(ctx)
+	if err != nil {
+		return err
+	}
+	defer f.release(conn)
+
+	return conn.Ping(ctx)
+}
+
+func (f *pgxDbFacadeImpl) useStatementCache(cache *statementCache) {
+	f.statements = cache
+}
+
+func (f *pgxDbFacadeImpl) statementCacheSize() int {
+	if f.statements == nil {
+		return 0
+	}
+
+	return f.statements.size()
+}
+
+func (f *pgxDbFacadeImpl) query(ctx context.Context, conn pgxConn, sql string, args []interface{}) (pgxRows, error) {
+	if f.statements != nil {
+		return f.statements.query(ctx, conn, sql, args)
+	}
+
+	return conn.QueryEx(ctx, sql, nil, args...)
+}
+
+// The pool drops the connections which are not alive: so does the
+// cache of statements.
+func (f *pgxDbFacadeImpl) release(conn pgxConn) {
+	if f.statements != nil && !conn.IsAlive() {
+		f.statements.forget(conn)
+	}
+
+	f.pool.Release(conn)
+}
+
+// pooledRows releases the connection they were read from once closed.
+// The pgx rows close themselves when the last one is read so the same
+// is done here.
+type pooledRows struct {
+	pgxRows
+	release  func()
+	released bool
+}
+
+func (r *pooledRows) Next() bool {
+	if r.pgxRows.Next() {
+		return true
+	}
+
+	r.Close()
+	return false
+}
+
+func (r *pooledRows) Close() {
+	r.pgxRows.Close()
+
+	if !r.released {
+		r.released = true
+		r.release()
+	}
 }
 
-func (f *pgxDbFacadeImpl) Exec(sql string, args ...interface{}) (pgx.CommandTag, error) {
-	return f.pool.Exec(sql, args...)
+func (r *pooledRows) Columns() []string {
+	return fieldNames(r.FieldDescriptions())
 }
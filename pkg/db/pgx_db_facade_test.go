@@ -1,8 +1,10 @@
 package db
 
 import (
+	"context"
 	"testing"
 
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
 	"github.com/jackc/pgx"
 	"github.com/stretchr/testify/assert"
 )
@@ -35,50 +37,276 @@ func TestPgxDbFacade_Query(t *testing.T) {
 	assert := assert.New(t)
 
 	m := &mockPgxDbConn{
-		queryErr: errDefault,
+		conn: &mockPgxConn{
+			queryErr: errDefault,
+		},
 	}
 	f := pgxDbFacadeImpl{
 		pool: m,
 	}
+	ctx := context.WithValue(context.TODO(), mockContextKey{}, "query")
 
-	rows, err := f.Query("someSql")
+	rows, err := f.Query(ctx, "someSql")
 	assert.Nil(rows)
 	assert.Equal(errDefault, err)
+	assert.Equal(ctx, m.ctxReceived)
+	assert.Equal(ctx, m.conn.ctxReceived)
+	assert.Equal(1, m.releaseCalled)
+}
+
+func TestPgxDbFacade_Query_Columns(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxDbConn{
+		conn: &mockPgxConn{
+			rows: &mockPgxRows{
+				mockSqlRows: mockSqlRows{numberOfRows: 1},
+				fields:      []pgx.FieldDescription{{Name: "id"}, {Name: "score"}},
+			},
+		},
+	}
+	f := &pgxDbFacadeImpl{
+		pool: m,
+	}
+
+	rows := runQuery(context.TODO(), f, queryImpl{sqlCode: "SELECT id, score FROM players"}, 0, nil)
+	assert.Nil(rows.Err())
+
+	err := rows.GetAll(MustNewStructMapper[structMapperTestPlayer]().NewParser())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlRowParsingFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrMismatchedSqlColumns))
+	assert.Equal(1, m.releaseCalled)
 }
 
 func TestPgxDbFacade_Exec(t *testing.T) {
 	assert := assert.New(t)
 
 	m := &mockPgxDbConn{
-		execError: errDefault,
+		conn: &mockPgxConn{
+			execError: errDefault,
+		},
 	}
 	f := pgxDbFacadeImpl{
 		pool: m,
 	}
+	ctx := context.WithValue(context.TODO(), mockContextKey{}, "exec")
 
-	tag, err := f.Exec("someSql")
+	tag, err := f.Exec(ctx, "someSql")
 	assert.Equal(pgx.CommandTag(""), tag)
 	assert.Equal(errDefault, err)
+	assert.Equal(ctx, m.ctxReceived)
+	assert.Equal(ctx, m.conn.ctxReceived)
+	assert.Equal(1, m.releaseCalled)
+}
+
+func TestPgxDbFacade_AcquireError(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxDbConn{
+		acquireErr: pgx.ErrAcquireTimeout,
+	}
+	f := pgxDbFacadeImpl{
+		pool: m,
+	}
+	ctx := context.TODO()
+
+	_, err := f.Query(ctx, "someSql")
+	assert.Equal(pgx.ErrAcquireTimeout, err)
+	_, err = f.Exec(ctx, "someSql")
+	assert.Equal(pgx.ErrAcquireTimeout, err)
+	_, err = f.Begin(ctx)
+	assert.Equal(pgx.ErrAcquireTimeout, err)
+	err = f.Ping(ctx)
+	assert.Equal(pgx.ErrAcquireTimeout, err)
+
+	assert.Equal(4, m.acquireCalled)
+	assert.Equal(0, m.releaseCalled)
+}
+
+func TestPgxDbFacade_CopyFrom(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxDbConn{
+		copyErr: errDefault,
+	}
+	f := pgxDbFacadeImpl{
+		pool: m,
+	}
+
+	_, err := f.CopyFrom(pgx.Identifier{"table"}, []string{"a"}, pgx.CopyFromRows(nil))
+	assert.Equal(errDefault, err)
+}
+
+func TestPgxDbFacade_Begin(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxDbConn{
+		conn: &mockPgxConn{
+			alive:    true,
+			beginErr: errDefault,
+		},
+	}
+	f := pgxDbFacadeImpl{
+		pool: m,
+	}
+	ctx := context.WithValue(context.TODO(), mockContextKey{}, "begin")
+
+	tx, err := f.Begin(ctx)
+	assert.Nil(tx)
+	assert.Equal(errDefault, err)
+	assert.Equal(1, m.conn.beginCalled)
+	assert.Equal(ctx, m.conn.ctxReceived)
+	assert.Equal(1, m.releaseCalled)
+}
+
+func TestPgxDbFacade_Begin_DeadConnection(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxDbConn{
+		conn: &mockPgxConn{
+			beginErr: errDefault,
+		},
+		acquireErrAfter: 2,
+		acquireErr:      pgx.ErrAcquireTimeout,
+	}
+	f := pgxDbFacadeImpl{
+		pool: m,
+	}
+
+	_, err := f.Begin(context.TODO())
+	assert.Equal(pgx.ErrAcquireTimeout, err)
+	assert.Equal(2, m.conn.beginCalled)
+	assert.Equal(2, m.releaseCalled)
+}
+
+func TestPgxDbFacade_Ping(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxDbConn{
+		conn: &mockPgxConn{
+			pingErr: errDefault,
+		},
+	}
+	f := pgxDbFacadeImpl{
+		pool: m,
+	}
+	ctx := context.WithValue(context.TODO(), mockContextKey{}, "ping")
+
+	err := f.Ping(ctx)
+	assert.Equal(errDefault, err)
+	assert.Equal(ctx, m.conn.ctxReceived)
+	assert.Equal(1, m.releaseCalled)
 }
 
 type mockPgxDbConn struct {
-	closeCalled int
-	queryErr    error
-	execError   error
+	closeCalled     int
+	conn            *mockPgxConn
+	acquireCalled   int
+	acquireErr      error
+	acquireErrAfter int
+	releaseCalled   int
+	copyErr         error
+	ctxReceived     context.Context
 }
 
 func (m *mockPgxDbConn) Close() {
 	m.closeCalled++
 }
 
-func (m *mockPgxDbConn) Query(sql string, args ...interface{}) (*pgx.Rows, error) {
-	return nil, m.queryErr
+func (m *mockPgxDbConn) AcquireEx(ctx context.Context) (pgxConn, error) {
+	m.ctxReceived = ctx
+	m.acquireCalled++
+	if m.acquireErr != nil && m.acquireCalled > m.acquireErrAfter {
+		return nil, m.acquireErr
+	}
+	return m.conn, nil
+}
+
+func (m *mockPgxDbConn) Release(conn pgxConn) {
+	m.releaseCalled++
+}
+
+func (m *mockPgxDbConn) CopyFrom(tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error) {
+	return 0, m.copyErr
+}
+
+type mockPgxConn struct {
+	rows        pgxRows
+	queryErr    error
+	tag         pgx.CommandTag
+	execError   error
+	beginCalled int
+	beginErr    error
+	pingErr     error
+	alive       bool
+	ctxReceived context.Context
+	sqlReceived []string
+
+	prepared    map[string]string
+	prepareErr  error
+	deallocated []string
+}
+
+func (m *mockPgxConn) QueryEx(ctx context.Context, sql string, options *pgx.QueryExOptions, args ...interface{}) (pgxRows, error) {
+	m.ctxReceived = ctx
+	m.sqlReceived = append(m.sqlReceived, sql)
+	if m.queryErr != nil {
+		return nil, m.queryErr
+	}
+	return m.rows, nil
+}
+
+func (m *mockPgxConn) ExecEx(ctx context.Context, sql string, options *pgx.QueryExOptions, arguments ...interface{}) (pgx.CommandTag, error) {
+	m.ctxReceived = ctx
+	m.sqlReceived = append(m.sqlReceived, sql)
+	return m.tag, m.execError
+}
+
+func (m *mockPgxConn) BeginEx(ctx context.Context, txOptions *pgx.TxOptions) (*pgx.Tx, error) {
+	m.ctxReceived = ctx
+	m.beginCalled++
+	return nil, m.beginErr
+}
+
+func (m *mockPgxConn) Ping(ctx context.Context) error {
+	m.ctxReceived = ctx
+	return m.pingErr
+}
+
+func (m *mockPgxConn) IsAlive() bool {
+	return m.alive
+}
+
+func (m *mockPgxConn) PrepareEx(ctx context.Context, name, sql string, opts *pgx.PrepareExOptions) (*pgx.PreparedStatement, error) {
+	m.ctxReceived = ctx
+	if m.prepareErr != nil {
+		return nil, m.prepareErr
+	}
+	if m.prepared == nil {
+		m.prepared = make(map[string]string)
+	}
+	m.prepared[name] = sql
+	return &pgx.PreparedStatement{Name: name, SQL: sql}, nil
+}
+
+func (m *mockPgxConn) Deallocate(name string) error {
+	delete(m.prepared, name)
+	m.deallocated = append(m.deallocated, name)
+	return nil
 }
 
-func (m *mockPgxDbConn) Exec(sql string, arguments ...interface{}) (pgx.CommandTag, error) {
-	return "", m.execError
+type mockPgxRows struct {
+	mockSqlRows
+	fields []pgx.FieldDescription
 }
 
+func (m *mockPgxRows) FieldDescriptions() []pgx.FieldDescription {
+	return m.fields
+}
+
+type mockContextKey struct{}
+
 func resetPgxConnFunc() {
 	pgxConnectionFunc = pgx.NewConnPool
 }
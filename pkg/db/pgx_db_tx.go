@@ -0,0 +1,15 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx"
+)
+
+type pgxDbTx interface {
+	QueryEx(ctx context.Context, sql string, options *pgx.QueryExOptions, args ...interface{}) (*pgx.Rows, error)
+	ExecEx(ctx context.Context, sql string, options *pgx.QueryExOptions, arguments ...interface{}) (pgx.CommandTag, error)
+	CopyFrom(tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error)
+	CommitEx(ctx context.Context) error
+	RollbackEx(ctx context.Context) error
+}
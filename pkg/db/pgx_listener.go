@@ -0,0 +1,27 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx"
+)
+
+// Satisfied by a pgx connection: the notifications of a Driver are
+// received on a connection which is not part of a pool as it stays busy
+// waiting.
+type DriverListener interface {
+	Listen(channel string) error
+	WaitForNotification(ctx context.Context) (*pgx.Notification, error)
+	Close() error
+}
+
+var pgxListenerFunc = pgx.Connect
+
+func newPgxListener(config pgx.ConnConfig) (DriverListener, error) {
+	conn, err := pgxListenerFunc(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/common"
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
+	"github.com/jackc/pgx"
+)
+
+type pgxQuerier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (DriverRows, error)
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgx.CommandTag, error)
+	CopyFrom(tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error)
+}
+
+// The context given to pgx stays attached to the rows until they
+// are closed: cancelling it before would abort the iteration. This
+// is also when the query is considered done by the hooks.
+// https://github.com/jackc/pgx/blob/v3.6.2/query.go#L67
+type cancellableRows struct {
+	DriverRows
+	ctx     context.Context
+	cancel  context.CancelFunc
+	count   int
+	closed  bool
+	onClose func(count int, err error)
+}
+
+func (r *cancellableRows) Next() bool {
+	next := r.DriverRows.Next()
+	if next {
+		r.count++
+	}
+
+	return next
+}
+
+func (r *cancellableRows) Close() {
+	if r.closed {
+		return
+	}
+	r.closed = true
+
+	r.DriverRows.Close()
+	err := r.Err()
+	r.cancel()
+	r.onClose(r.count, err)
+}
+
+// The errors of the server are only known once the rows are read.
+func (r *cancellableRows) Err() error {
+	if err := r.DriverRows.Err(); err != nil {
+		return wrapQueryError(r.ctx, err)
+	}
+
+	return nil
+}
+
+func (r *cancellableRows) Columns() []string {
+	return sqlRowsColumns(r.DriverRows)
+}
+
+// The rows wrapping others only expose the methods of the interface
+// so the names of the columns are forwarded explicitly.
+func sqlRowsColumns(rows DriverRows) []string {
+	switch rows := rows.(type) {
+	case columnsDescriber:
+		return rows.Columns()
+	case *pgx.Rows:
+		return fieldNames(rows.FieldDescriptions())
+	default:
+		return nil
+	}
+}
+
+func fieldNames(fields []pgx.FieldDescription) []string {
+	var columns []string
+	for _, field := range fields {
+		columns = append(columns, field.Name)
+	}
+
+	return columns
+}
+
+func runQuery(ctx context.Context, querier pgxQuerier, query Query, timeout time.Duration, hooks []QueryHook) Rows {
+	if !query.Valid() {
+		return newRows(nil, errors.NewCode(errors.ErrInvalidQuery))
+	}
+
+	event := QueryEvent{
+		Operation: QueryRows,
+		Query:     query,
+		Label:     QueryLabelFromContext(ctx),
+	}
+	start := time.Now()
+	ctx = runBeforeQueryHooks(ctx, hooks, event)
+
+	done := func(count int, err error) {
+		event.Duration = time.Since(start)
+		event.AffectedRows = count
+		event.Err = err
+		runAfterQueryHooks(ctx, hooks, event)
+	}
+
+	queryCtx, cancel := withQueryTimeout(ctx, timeout)
+	rows, err := querier.Query(queryCtx, query.ToSql(), query.Args()...)
+	if err != nil {
+		cancel()
+		err = wrapQueryError(queryCtx, err)
+		done(0, err)
+		return newRows(nil, err)
+	}
+	if common.IsInterfaceNil(rows) {
+		cancel()
+		done(0, nil)
+		return newRows(nil, nil)
+	}
+
+	out := &cancellableRows{
+		DriverRows: rows,
+		ctx:        queryCtx,
+		cancel:     cancel,
+		onClose:    done,
+	}
+	return newRows(out, nil)
+}
+
+func runExecute(ctx context.Context, querier pgxQuerier, query Query, timeout time.Duration, hooks []QueryHook) Result {
+	if !query.Valid() {
+		return newResult("", errors.NewCode(errors.ErrInvalidQuery))
+	}
+
+	event := QueryEvent{
+		Operation: QueryExecution,
+		Query:     query,
+		Label:     QueryLabelFromContext(ctx),
+	}
+	start := time.Now()
+	ctx = runBeforeQueryHooks(ctx, hooks, event)
+
+	res := execute(ctx, querier, query, timeout)
+
+	event.Duration = time.Since(start)
+	event.AffectedRows = res.AffectedRows()
+	event.Err = res.Err()
+	runAfterQueryHooks(ctx, hooks, event)
+
+	return res
+}
+
+func execute(ctx context.Context, querier pgxQuerier, query Query, timeout time.Duration) Result {
+	queryCtx, cancel := withQueryTimeout(ctx, timeout)
+	defer cancel()
+
+	tag, err := querier.Exec(queryCtx, query.ToSql(), query.Args()...)
+	if err != nil {
+		return newResult("", wrapQueryError(queryCtx, err))
+	}
+
+	return newResult(tag, nil)
+}
+
+// pgx does not accept a context for COPY operations so the statement
+// can't be cancelled: it is only abandoned when the timeout expires.
+// https://github.com/jackc/pgx/blob/v3.6.2/copy_from.go#L274
+func runCopyFrom(ctx context.Context, querier pgxQuerier, copy CopyFrom, timeout time.Duration) Result {
+	if !copy.Valid() {
+		return newResult("", errors.NewCode(errors.ErrInvalidQuery))
+	}
+
+	if copy.Verbose() {
+		logger.ScopedTracef(ctx, "executing: %s", copyFromToDebugStr(copy))
+	}
+
+	var copied int
+	p := common.Process{
+		WorkFunc: func() error {
+			var err error
+			copied, err = querier.CopyFrom(tableToIdentifier(copy.Table()), copy.Columns(), pgx.CopyFromRows(copy.Rows()))
+			return err
+		},
+	}
+
+	err := common.ExecuteWithContext(p, ctx, timeout)
+	if err != nil {
+		if err == context.DeadlineExceeded {
+			return newResult("", errors.WrapCode(err, errors.ErrDbRequestTimeout))
+		}
+		return newResult("", wrapSqlStateError(err))
+	}
+
+	return newResult(copyFromResultTag(copied), nil)
+}
+
+func withQueryTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+func wrapQueryError(queryCtx context.Context, err error) error {
+	// The pool may notice the deadline slightly before the context.
+	if queryCtx.Err() == context.DeadlineExceeded || err == pgx.ErrAcquireTimeout {
+		return errors.WrapCode(context.DeadlineExceeded, errors.ErrDbRequestTimeout)
+	}
+
+	return wrapSqlStateError(err)
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx"
+)
+
+// A transaction started on a connection of a DriverPool.
+type DriverTx interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (DriverRows, error)
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgx.CommandTag, error)
+	CopyFrom(tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error)
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
+type pgxTxFacadeImpl struct {
+	tx pgxDbTx
+	// Gives the connection back to the pool once the transaction ends.
+	release func()
+}
+
+func (f *pgxTxFacadeImpl) Query(ctx context.Context, sql string, args ...interface{}) (DriverRows, error) {
+	return f.tx.QueryEx(ctx, sql, nil, args...)
+}
+
+func (f *pgxTxFacadeImpl) Exec(ctx context.Context, sql string, args ...interface{}) (pgx.CommandTag, error) {
+	return f.tx.ExecEx(ctx, sql, nil, args...)
+}
+
+func (f *pgxTxFacadeImpl) CopyFrom(tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error) {
+	return f.tx.CopyFrom(tableName, columnNames, rowSrc)
+}
+
+func (f *pgxTxFacadeImpl) Commit(ctx context.Context) error {
+	err := f.tx.CommitEx(ctx)
+	f.end(err)
+	return err
+}
+
+func (f *pgxTxFacadeImpl) Rollback(ctx context.Context) error {
+	err := f.tx.RollbackEx(ctx)
+	f.end(err)
+	return err
+}
+
+func (f *pgxTxFacadeImpl) end(err error) {
+	// The transaction was already over: the connection is released.
+	if err == pgx.ErrTxClosed || f.release == nil {
+		return
+	}
+
+	f.release()
+	f.release = nil
+}
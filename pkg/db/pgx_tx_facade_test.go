@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPgxTxFacade_Query(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxDbTx{
+		queryErr: errDefault,
+	}
+	f := pgxTxFacadeImpl{
+		tx: m,
+	}
+	ctx := context.WithValue(context.TODO(), mockContextKey{}, "tx")
+
+	rows, err := f.Query(ctx, "someSql")
+	assert.Nil(rows)
+	assert.Equal(errDefault, err)
+	assert.Equal(ctx, m.ctxReceived)
+}
+
+func TestPgxTxFacade_Exec(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxDbTx{
+		execErr: errDefault,
+	}
+	f := pgxTxFacadeImpl{
+		tx: m,
+	}
+	ctx := context.WithValue(context.TODO(), mockContextKey{}, "tx")
+
+	tag, err := f.Exec(ctx, "someSql")
+	assert.Equal(pgx.CommandTag(""), tag)
+	assert.Equal(errDefault, err)
+	assert.Equal(ctx, m.ctxReceived)
+}
+
+func TestPgxTxFacade_CopyFrom(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxDbTx{
+		copyErr: errDefault,
+	}
+	f := pgxTxFacadeImpl{
+		tx: m,
+	}
+
+	_, err := f.CopyFrom(pgx.Identifier{"table"}, []string{"a"}, pgx.CopyFromRows(nil))
+	assert.Equal(errDefault, err)
+}
+
+func TestPgxTxFacade_Commit(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxDbTx{
+		commitErr: errDefault,
+	}
+	f := pgxTxFacadeImpl{
+		tx: m,
+	}
+	ctx := context.WithValue(context.TODO(), mockContextKey{}, "tx")
+
+	err := f.Commit(ctx)
+	assert.Equal(errDefault, err)
+	assert.Equal(1, m.commitCalled)
+	assert.Equal(ctx, m.ctxReceived)
+}
+
+func TestPgxTxFacade_Rollback(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxDbTx{
+		rollbackErr: errDefault,
+	}
+	f := pgxTxFacadeImpl{
+		tx: m,
+	}
+	ctx := context.WithValue(context.TODO(), mockContextKey{}, "tx")
+
+	err := f.Rollback(ctx)
+	assert.Equal(errDefault, err)
+	assert.Equal(1, m.rollbackCalled)
+	assert.Equal(ctx, m.ctxReceived)
+}
+
+func TestPgxTxFacade_ReleasesConnection(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxDbTx{}
+	released := 0
+	f := pgxTxFacadeImpl{
+		tx:      m,
+		release: func() { released++ },
+	}
+
+	assert.Nil(f.Commit(context.TODO()))
+	assert.Equal(1, released)
+
+	m.rollbackErr = pgx.ErrTxClosed
+	err := f.Rollback(context.TODO())
+	assert.Equal(pgx.ErrTxClosed, err)
+	assert.Equal(1, released)
+}
+
+type mockPgxDbTx struct {
+	queryErr       error
+	execErr        error
+	copyErr        error
+	commitCalled   int
+	commitErr      error
+	rollbackCalled int
+	rollbackErr    error
+	ctxReceived    context.Context
+}
+
+func (m *mockPgxDbTx) QueryEx(ctx context.Context, sql string, options *pgx.QueryExOptions, args ...interface{}) (*pgx.Rows, error) {
+	m.ctxReceived = ctx
+	return nil, m.queryErr
+}
+
+func (m *mockPgxDbTx) ExecEx(ctx context.Context, sql string, options *pgx.QueryExOptions, arguments ...interface{}) (pgx.CommandTag, error) {
+	m.ctxReceived = ctx
+	return "", m.execErr
+}
+
+func (m *mockPgxDbTx) CopyFrom(tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error) {
+	return 0, m.copyErr
+}
+
+func (m *mockPgxDbTx) CommitEx(ctx context.Context) error {
+	m.ctxReceived = ctx
+	m.commitCalled++
+	return m.commitErr
+}
+
+func (m *mockPgxDbTx) RollbackEx(ctx context.Context) error {
+	m.ctxReceived = ctx
+	m.rollbackCalled++
+	return m.rollbackErr
+}
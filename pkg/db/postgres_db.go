@@ -10,44 +10,130 @@ import (
 	"github.com/jackc/pgx"
 )
 
+// Used by pgx for a pool size of 0: it is set explicitly so that the
+// configuration does not rely on it.
+const defaultConnectionsPoolSize = 5
+
 // https://www.sohamkamani.com/golang/sql-database/
 // https://betterprogramming.pub/how-to-work-with-sql-in-go-ca8bc0b30722
 type postgresDb struct {
-	config Config
-	pool   pgxDbFacade
-	lock   sync.Mutex
+	config  Config
+	pool    DriverPool
+	monitor *connectionMonitor
+	lock    sync.RWMutex
+	breaker *circuitBreaker
+	retry   retryPolicy
+	// The hooks of the queries run outside of a transaction, with the
+	// circuit breaker for the ones returning rows.
+	hooks      []QueryHook
+	queryHooks []QueryHook
+	explain    *explainHook
+	// Survive the reconnections unlike the prepared statements.
+	statements statementCounters
 }
 
 func NewPostgresDatabase(conf Config) Database {
-	db := postgresDb{
-		config: conf,
+	db := &postgresDb{
+		config:  conf,
+		breaker: newCircuitBreaker(conf.DbCircuitBreakerThreshold),
+		retry:   newRetryPolicy(conf),
+	}
+
+	// The explain hook is not part of the configuration as it is not
+	// used by the transactions. The hooks are copied as the slice might
+	// be shared with the other databases, e.g. the replicas.
+	db.hooks = append([]QueryHook{}, conf.Hooks...)
+	if explainEnabled(conf) {
+		db.explain = newExplainHook(conf, db.availablePool)
+		db.hooks = append(db.hooks, db.explain)
 	}
 
-	return &db
+	hooks := append([]QueryHook{}, db.hooks...)
+	db.queryHooks = append(hooks, circuitBreakerHook{breaker: db.breaker})
+
+	return db
 }
 
 func (db *postgresDb) Connect(ctx context.Context) error {
 	logger.ScopedInfof(ctx, "connection attempt to %s", db.config)
 
+	pool, err := db.createPool(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.ScopedInfof(ctx, "connected to %s", db.config)
+
+	db.lock.Lock()
+	func() {
+		defer db.lock.Unlock()
+		db.pool = pool
+		db.breaker.reset()
+
+		if db.monitor == nil {
+			db.monitor = newConnectionMonitor()
+			go db.watchConnection(db.monitor)
+		}
+	}()
+
+	return nil
+}
+
+func (db *postgresDb) createPool(ctx context.Context) (DriverPool, error) {
+	connConf, err := db.config.connConfig()
+	if err != nil {
+		return nil, errors.WrapCode(err, errors.ErrDbConnectionFailed)
+	}
+
+	poolSize := db.config.DbConnectionsPoolSize
+	if poolSize == 0 {
+		poolSize = defaultConnectionsPoolSize
+	}
+
 	pgxConf := pgx.ConnPoolConfig{
-		ConnConfig: pgx.ConnConfig{
-			Host:     db.config.DbHost,
-			Database: db.config.DbName,
-			Port:     db.config.DbPort,
-			User:     db.config.DbUser,
-			Password: db.config.DbPassword,
-		},
-		MaxConnections: int(db.config.DbConnectionsPoolSize),
-		AcquireTimeout: 0,
-	}
-
-	var pool pgxDbFacade
+		ConnConfig:     connConf,
+		MaxConnections: int(poolSize),
+		// Only used by the operations not acquiring a connection with
+		// the context of the query, such as CopyFrom.
+		AcquireTimeout: db.config.DbQueryTimeout,
+	}
+
+	var pool DriverPool
+	err = db.connectWithTimeout(ctx, func() error {
+		var err error
+		pool, err = db.config.creationFunc(pgxConf)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cached, ok := pool.(statementCacheFacade); ok && db.config.DbStatementCacheSize > 0 {
+		cached.useStatementCache(newStatementCache(db.config.DbStatementCacheSize, &db.statements))
+	}
+
+	return pool, nil
+}
+
+func (db *postgresDb) createListener(ctx context.Context) (DriverListener, error) {
+	connConf, err := db.config.connConfig()
+	if err != nil {
+		return nil, errors.WrapCode(err, errors.ErrDbConnectionFailed)
+	}
+
+	var listener DriverListener
+	err = db.connectWithTimeout(ctx, func() error {
+		var err error
+		listener, err = db.config.listenerFunc(connConf)
+		return err
+	})
+
+	return listener, err
+}
+
+func (db *postgresDb) connectWithTimeout(ctx context.Context, connect func() error) error {
 	p := common.Process{
-		WorkFunc: func() error {
-			var err error
-			pool, err = db.config.creationFunc(pgxConf)
-			return err
-		},
+		WorkFunc: connect,
 	}
 
 	err := common.ExecuteWithContext(p, ctx, db.config.DbConnectionTimeout)
@@ -58,27 +144,35 @@ func (db *postgresDb) Connect(ctx context.Context) error {
 		return errors.WrapCode(err, errors.ErrDbConnectionFailed)
 	}
 
-	logger.ScopedInfof(ctx, "connected to %s", db.config)
-
-	db.lock.Lock()
-	func() {
-		defer db.lock.Unlock()
-		db.pool = pool
-	}()
-
 	return nil
 }
 
+// The monitor is stopped first as it might be replacing the pool.
 func (db *postgresDb) Disconnect(ctx context.Context) error {
 	db.lock.Lock()
-	defer db.lock.Unlock()
+	monitor := db.monitor
+	db.monitor = nil
+	db.lock.Unlock()
 
-	if db.pool == nil {
-		return nil
+	if monitor != nil {
+		monitor.shutdown()
 	}
 
-	db.pool.Close()
+	db.lock.Lock()
+	pool := db.pool
 	db.pool = nil
+	db.lock.Unlock()
+
+	if pool == nil {
+		return nil
+	}
+
+	// The plans in progress use the pool: the new ones see that it is
+	// gone.
+	if db.explain != nil {
+		db.explain.wait()
+	}
+	pool.Close()
 
 	logger.ScopedInfof(ctx, "connection to %s closed", db.config)
 
@@ -86,79 +180,123 @@ func (db *postgresDb) Disconnect(ctx context.Context) error {
 }
 
 func (db *postgresDb) Query(ctx context.Context, query Query) Rows {
-	db.lock.Lock()
-	defer db.lock.Unlock()
+	var rows Rows
+	db.retry.run(ctx, func() error {
+		// The rows discarded by a retry still hold a connection.
+		if rows != nil {
+			rows.Close()
+		}
+		rows = db.query(ctx, query)
+		return rows.Err()
+	})
 
-	if db.pool == nil {
+	return rows
+}
+
+func (db *postgresDb) query(ctx context.Context, query Query) Rows {
+	pool := db.availablePool()
+	if pool == nil {
 		return newRows(nil, errors.NewCode(errors.ErrDbConnectionInvalid))
 	}
 
-	if !query.Valid() {
-		return newRows(nil, errors.NewCode(errors.ErrInvalidQuery))
+	return runQuery(ctx, pool, query, db.config.DbQueryTimeout, db.queryHooks)
+}
+
+func (db *postgresDb) Execute(ctx context.Context, query Query) Result {
+	var res Result
+	db.retry.run(ctx, func() error {
+		res = db.execute(ctx, query)
+		return res.Err()
+	})
+
+	return res
+}
+
+func (db *postgresDb) execute(ctx context.Context, query Query) Result {
+	pool := db.availablePool()
+	if pool == nil {
+		return newResult("", errors.NewCode(errors.ErrDbConnectionInvalid))
 	}
 
-	sqlQuery := query.ToSql()
-	if query.Verbose() {
-		logger.ScopedTracef(ctx, "executing: %s", query.ToSql())
+	res := runExecute(ctx, pool, query, db.config.DbQueryTimeout, db.hooks)
+	db.breaker.report(ctx, res.Err())
+	return res
+}
+
+// The rows of a COPY are consumed by the first attempt so it is never
+// retried.
+func (db *postgresDb) CopyFrom(ctx context.Context, copy CopyFrom) Result {
+	pool := db.availablePool()
+	if pool == nil {
+		return newResult("", errors.NewCode(errors.ErrDbConnectionInvalid))
 	}
 
-	var rows sqlRows
-	p := common.Process{
-		WorkFunc: func() error {
-			var err error
-			rows, err = db.pool.Query(sqlQuery)
-			return err
-		},
-		CleanUpIfFailFunc: func() {
-			logger.ScopedTracef(ctx, "closing rows after query failure")
-			rows.Close()
-		},
+	res := runCopyFrom(ctx, pool, copy, db.config.DbQueryTimeout)
+	db.breaker.report(ctx, res.Err())
+	return res
+}
+
+func (db *postgresDb) Begin(ctx context.Context) (Transaction, error) {
+	pool := db.availablePool()
+	if pool == nil {
+		return nil, errors.NewCode(errors.ErrDbConnectionInvalid)
 	}
 
-	err := common.ExecuteWithContext(p, ctx, db.config.DbQueryTimeout)
+	beginCtx, cancel := withQueryTimeout(ctx, db.config.DbQueryTimeout)
+	defer cancel()
+
+	tx, err := pool.Begin(beginCtx)
+	db.breaker.report(ctx, err)
 	if err != nil {
-		if err == context.DeadlineExceeded {
-			return newRows(nil, errors.WrapCode(err, errors.ErrDbRequestTimeout))
+		if beginCtx.Err() == context.DeadlineExceeded {
+			return nil, errors.WrapCode(context.DeadlineExceeded, errors.ErrDbRequestTimeout)
 		}
-		return newRows(nil, errors.WrapCode(err, errors.ErrDbRequestFailed))
+		return nil, errors.WrapCode(err, errors.ErrDbTransactionBeginFailed)
 	}
 
-	return newRows(rows, nil)
+	return newPostgresTransaction(tx, db.config), nil
 }
 
-func (db *postgresDb) Execute(ctx context.Context, query Query) Result {
-	db.lock.Lock()
-	defer db.lock.Unlock()
-
-	if db.pool == nil {
-		return newResult("", errors.NewCode(errors.ErrDbConnectionInvalid))
+// The notifications are received on a dedicated connection which does
+// not depend on the pool: the database does not need to be connected.
+func (db *postgresDb) Subscribe(ctx context.Context, channel string) (Subscription, error) {
+	if len(channel) == 0 {
+		return nil, errors.NewCode(errors.ErrInvalidSqlNotificationChannel)
 	}
 
-	if !query.Valid() {
-		return newResult("", errors.NewCode(errors.ErrInvalidQuery))
-	}
+	return newSubscription(ctx, channel, db.config, db.createListener)
+}
 
-	sqlQuery := query.ToSql()
-	if query.Verbose() {
-		logger.ScopedTracef(ctx, "executing: %s", query.ToSql())
+func (db *postgresDb) StatementCacheStats() StatementCacheStats {
+	stats := StatementCacheStats{
+		Hits:          db.statements.hits.Load(),
+		Misses:        db.statements.misses.Load(),
+		Invalidations: db.statements.invalidations.Load(),
 	}
-
-	var tag pgx.CommandTag
-	p := common.Process{
-		WorkFunc: func() error {
-			var err error
-			tag, err = db.pool.Exec(sqlQuery)
-			return err
-		},
+	if cached, ok := db.currentPool().(statementCacheFacade); ok {
+		stats.Size = cached.statementCacheSize()
 	}
 
-	err := common.ExecuteWithContext(p, ctx, db.config.DbQueryTimeout)
-	if err != nil {
-		if err == context.DeadlineExceeded {
-			return newResult("", errors.WrapCode(err, errors.ErrDbRequestTimeout))
-		}
-		return newResult("", errors.WrapCode(err, errors.ErrDbRequestFailed))
+	return stats
+}
+
+// The lock only protects the pool handle: queries run concurrently
+// and rely on the pool to dispatch them on distinct connections.
+// Closing the pool while queries are in flight is safe as pgx only
+// releases the acquired connections once they are returned.
+// https://github.com/jackc/pgx/blob/v3.6.2/conn_pool.go#L260
+func (db *postgresDb) currentPool() DriverPool {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	return db.pool
+}
+
+// While the circuit breaker is open the pool is not used so that the
+// queries fail fast instead of waiting for the connection timeout.
+func (db *postgresDb) availablePool() DriverPool {
+	if db.breaker.isOpen() {
+		return nil
 	}
 
-	return newResult(tag, nil)
+	return db.currentPool()
 }
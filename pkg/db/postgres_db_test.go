@@ -3,6 +3,8 @@ package db
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -35,6 +37,28 @@ func TestPostgresDatabase_Connect(t *testing.T) {
 	assert.Nil(err)
 }
 
+func TestPostgresDatabase_Connect_PoolSize(t *testing.T) {
+	assert := assert.New(t)
+
+	var received []pgx.ConnPoolConfig
+	config := testConfig
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		received = append(received, config)
+		return &mockPgxDbFacade{}, nil
+	}
+
+	db := NewPostgresDatabase(config)
+	assert.Nil(db.Connect(context.TODO()))
+
+	config.DbConnectionsPoolSize = 0
+	db = NewPostgresDatabase(config)
+	assert.Nil(db.Connect(context.TODO()))
+
+	assert.Equal(2, len(received))
+	assert.Equal(2, received[0].MaxConnections)
+	assert.Equal(defaultConnectionsPoolSize, received[1].MaxConnections)
+}
+
 func TestPostgresDatabase_Connect_Fail(t *testing.T) {
 	assert := assert.New(t)
 
@@ -49,13 +73,33 @@ func TestPostgresDatabase_Connect_Fail(t *testing.T) {
 	assert.Equal(errDefault, cause)
 }
 
+func TestPostgresDatabase_Connect_InvalidConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	var received []pgx.ConnPoolConfig
+	config := testConfig
+	config.DbSslMode = "sometimes"
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		received = append(received, config)
+		return &mockPgxDbFacade{}, nil
+	}
+
+	db := NewPostgresDatabase(config)
+
+	err := db.Connect(context.TODO())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbConnectionFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrInvalidDbConfig))
+	assert.Nil(received)
+}
+
 func TestPostgresDatabase_Connect_Timeout(t *testing.T) {
 	assert := assert.New(t)
 
 	timeout := 100 * time.Millisecond
 	config := testConfig
 	config.DbConnectionTimeout = timeout
-	config.creationFunc = func(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
 		time.Sleep(2 * timeout)
 		return &mockPgxDbFacade{}, nil
 	}
@@ -82,7 +126,7 @@ func TestPostgresDatabase_Disconnect(t *testing.T) {
 
 	config := testConfig
 	mockDb := &mockPgxDbFacade{}
-	config.creationFunc = func(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
 		return mockDb, nil
 	}
 	ctx := context.TODO()
@@ -92,7 +136,7 @@ func TestPostgresDatabase_Disconnect(t *testing.T) {
 
 	err := db.Disconnect(ctx)
 	assert.Nil(err)
-	assert.Equal(1, mockDb.closeCalled)
+	assert.Equal(int32(1), mockDb.closeCalled.Load())
 }
 
 func TestPostgresDatabase_Query_NotConnected(t *testing.T) {
@@ -125,7 +169,7 @@ func TestPostgresDatabase_Query(t *testing.T) {
 
 	config := testConfig
 	mockDb := &mockPgxDbFacade{}
-	config.creationFunc = func(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
 		return mockDb, nil
 	}
 	ctx := context.TODO()
@@ -147,7 +191,7 @@ func TestPostgresDatabase_Query_Verbose(t *testing.T) {
 
 	config := testConfig
 	mockDb := &mockPgxDbFacade{}
-	config.creationFunc = func(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
 		return mockDb, nil
 	}
 	ctx := context.TODO()
@@ -165,6 +209,30 @@ func TestPostgresDatabase_Query_Verbose(t *testing.T) {
 	assert.Equal("someSqlCode", mockDb.sqlQueriesReceived[0])
 }
 
+func TestPostgresDatabase_Query_WithArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	mockDb := &mockPgxDbFacade{}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return mockDb, nil
+	}
+	ctx := context.TODO()
+
+	db := NewPostgresDatabase(config)
+	db.Connect(ctx)
+
+	q := queryImpl{
+		sqlCode: "someSqlCode $1",
+		args:    []interface{}{"it's"},
+		verbose: true,
+	}
+	rows := db.Query(ctx, q)
+	assert.Nil(rows.Err())
+	assert.Equal(1, len(mockDb.sqlArgsReceived))
+	assert.Equal([]interface{}{"it's"}, mockDb.sqlArgsReceived[0])
+}
+
 func TestPostgresDatabase_Query_Fail(t *testing.T) {
 	assert := assert.New(t)
 
@@ -172,7 +240,7 @@ func TestPostgresDatabase_Query_Fail(t *testing.T) {
 	mockDb := &mockPgxDbFacade{
 		queryError: errDefault,
 	}
-	config.creationFunc = func(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
 		return mockDb, nil
 	}
 	ctx := context.TODO()
@@ -199,7 +267,7 @@ func TestPostgresDatabase_Query_Timeout(t *testing.T) {
 		queryDelay: defaultSleep,
 		rows:       mockRows,
 	}
-	config.creationFunc = func(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
 		return mockDb, nil
 	}
 	ctx := context.TODO()
@@ -211,13 +279,41 @@ func TestPostgresDatabase_Query_Timeout(t *testing.T) {
 		sqlCode: "someSqlCode",
 	}
 	rows := db.Query(ctx, q)
-	// Wait for query to finish with some margin.
-	time.Sleep(2 * defaultSleep)
 	err := rows.Err()
 	assert.True(errors.IsErrorWithCode(err, errors.ErrDbRequestTimeout))
 	cause := errors.Unwrap(err)
 	assert.Equal(context.DeadlineExceeded, cause)
+	assert.Equal(int32(1), mockDb.cancelled.Load())
+	assert.Equal(int32(0), mockRows.closeCalls.Load())
+}
+
+func TestPostgresDatabase_Query_ContextReleasedWhenRowsAreClosed(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	config.DbQueryTimeout = defaultSleep
+	mockRows := &mockSqlRows{}
+	mockDb := &mockPgxDbFacade{
+		rows: mockRows,
+	}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return mockDb, nil
+	}
+	ctx := context.TODO()
+
+	db := NewPostgresDatabase(config)
+	db.Connect(ctx)
+
+	q := queryImpl{
+		sqlCode: "someSqlCode",
+	}
+	rows := db.Query(ctx, q)
+	assert.Nil(rows.Err())
+	assert.Nil(mockDb.ctxReceived.Err())
+
+	rows.Close()
 	assert.Equal(int32(1), mockRows.closeCalls.Load())
+	assert.Equal(context.Canceled, mockDb.ctxReceived.Err())
 }
 
 func TestPostgresDatabase_Execute_NotConnected(t *testing.T) {
@@ -235,7 +331,7 @@ func TestPostgresDatabase_Execute_Invalid(t *testing.T) {
 
 	config := testConfig
 	mockDb := &mockPgxDbFacade{}
-	config.creationFunc = func(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
 		return mockDb, nil
 	}
 	ctx := context.TODO()
@@ -255,7 +351,7 @@ func TestPostgresDatabase_Execute(t *testing.T) {
 	mockDb := &mockPgxDbFacade{
 		tag: "INSERT 0 12",
 	}
-	config.creationFunc = func(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
 		return mockDb, nil
 	}
 	ctx := context.TODO()
@@ -280,7 +376,7 @@ func TestPostgresDatabase_Execute_Verbose(t *testing.T) {
 	mockDb := &mockPgxDbFacade{
 		tag: "INSERT 0 12",
 	}
-	config.creationFunc = func(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
 		return mockDb, nil
 	}
 	ctx := context.TODO()
@@ -299,6 +395,31 @@ func TestPostgresDatabase_Execute_Verbose(t *testing.T) {
 	assert.Equal("someSqlCode", mockDb.sqlExecuteReceived[0])
 }
 
+func TestPostgresDatabase_Execute_WithArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	mockDb := &mockPgxDbFacade{
+		tag: "INSERT 0 1",
+	}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return mockDb, nil
+	}
+	ctx := context.TODO()
+
+	db := NewPostgresDatabase(config)
+	db.Connect(ctx)
+
+	q := queryImpl{
+		sqlCode: "someSqlCode $1 $2",
+		args:    []interface{}{"it's", 32},
+	}
+	result := db.Execute(ctx, q)
+	assert.Nil(result.Err())
+	assert.Equal(1, len(mockDb.sqlExecArgsReceived))
+	assert.Equal([]interface{}{"it's", 32}, mockDb.sqlExecArgsReceived[0])
+}
+
 func TestPostgresDatabase_Execute_Fail(t *testing.T) {
 	assert := assert.New(t)
 
@@ -306,7 +427,7 @@ func TestPostgresDatabase_Execute_Fail(t *testing.T) {
 	mockDb := &mockPgxDbFacade{
 		execError: errDefault,
 	}
-	config.creationFunc = func(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
 		return mockDb, nil
 	}
 	ctx := context.TODO()
@@ -331,7 +452,7 @@ func TestPostgresDatabase_Execute_Timeout(t *testing.T) {
 	mockDb := &mockPgxDbFacade{
 		execDelay: defaultSleep,
 	}
-	config.creationFunc = func(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
 		return mockDb, nil
 	}
 	ctx := context.TODO()
@@ -343,53 +464,450 @@ func TestPostgresDatabase_Execute_Timeout(t *testing.T) {
 		sqlCode: "someSqlCode",
 	}
 	result := db.Execute(ctx, q)
-	// Wait for query to finish with some margin.
-	time.Sleep(2 * defaultSleep)
 	assert.True(errors.IsErrorWithCode(result.Err(), errors.ErrDbRequestTimeout))
 	cause := errors.Unwrap(result.Err())
 	assert.Equal(context.DeadlineExceeded, cause)
+	assert.Equal(int32(1), mockDb.cancelled.Load())
+}
+
+func TestPostgresDatabase_Execute_AcquireTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	config.DbQueryTimeout = defaultSleep
+	mockDb := &mockPgxDbFacade{
+		execError: pgx.ErrAcquireTimeout,
+	}
+	var poolConf pgx.ConnPoolConfig
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		poolConf = config
+		return mockDb, nil
+	}
+	ctx := context.TODO()
+
+	db := NewPostgresDatabase(config)
+	db.Connect(ctx)
+	assert.Equal(defaultSleep, poolConf.AcquireTimeout)
+
+	q := queryImpl{
+		sqlCode: "someSqlCode",
+	}
+	result := db.Execute(ctx, q)
+	assert.True(errors.IsErrorWithCode(result.Err(), errors.ErrDbRequestTimeout))
+}
+
+func TestPostgresDatabase_CopyFrom_NotConnected(t *testing.T) {
+	assert := assert.New(t)
+
+	db := NewPostgresDatabase(testConfig)
+
+	c := copyFromImpl{}
+	result := db.CopyFrom(context.TODO(), c)
+	assert.True(errors.IsErrorWithCode(result.Err(), errors.ErrDbConnectionInvalid))
+}
+
+func TestPostgresDatabase_CopyFrom_Invalid(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	mockDb := &mockPgxDbFacade{}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return mockDb, nil
+	}
+	ctx := context.TODO()
+
+	db := NewPostgresDatabase(config)
+	db.Connect(ctx)
+
+	c := copyFromImpl{}
+	result := db.CopyFrom(ctx, c)
+	assert.True(errors.IsErrorWithCode(result.Err(), errors.ErrInvalidQuery))
+}
+
+func TestPostgresDatabase_CopyFrom(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	mockDb := &mockPgxDbFacade{
+		copied: 2,
+	}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return mockDb, nil
+	}
+	ctx := context.TODO()
+
+	db := NewPostgresDatabase(config)
+	db.Connect(ctx)
+
+	c := copyFromImpl{
+		table:   "schema.table",
+		columns: []string{"a", "b"},
+		rows:    [][]interface{}{{1, "haha"}, {2, "hihi"}},
+		verbose: true,
+	}
+	result := db.CopyFrom(ctx, c)
+	assert.Nil(result.Err())
+	assert.Equal(2, result.AffectedRows())
+	assert.Equal(pgx.Identifier{"schema", "table"}, mockDb.copyTableReceived)
+	assert.Equal(c.rows, mockDb.copyRowsReceived)
+}
+
+func TestPostgresDatabase_CopyFrom_Fail(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	mockDb := &mockPgxDbFacade{
+		copyError: errDefault,
+	}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return mockDb, nil
+	}
+	ctx := context.TODO()
+
+	db := NewPostgresDatabase(config)
+	db.Connect(ctx)
+
+	c := copyFromImpl{
+		table:   "table",
+		columns: []string{"a"},
+	}
+	result := db.CopyFrom(ctx, c)
+	assert.True(errors.IsErrorWithCode(result.Err(), errors.ErrDbRequestFailed))
+	cause := errors.Unwrap(result.Err())
+	assert.Equal(errDefault, cause)
+}
+
+func TestPostgresDatabase_CopyFrom_Timeout(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	config.DbQueryTimeout = defaultSleep / 2
+	mockDb := &mockPgxDbFacade{
+		copyDelay: defaultSleep,
+	}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return mockDb, nil
+	}
+	ctx := context.TODO()
+
+	db := NewPostgresDatabase(config)
+	db.Connect(ctx)
+
+	c := copyFromImpl{
+		table:   "table",
+		columns: []string{"a"},
+	}
+	result := db.CopyFrom(ctx, c)
+	// Wait for copy to finish with some margin.
+	time.Sleep(2 * defaultSleep)
+	assert.True(errors.IsErrorWithCode(result.Err(), errors.ErrDbRequestTimeout))
+}
+
+func TestPostgresDatabase_Query_Concurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	mockDb := &mockPgxDbFacade{
+		queryDelay: defaultSleep,
+	}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return mockDb, nil
+	}
+	ctx := context.TODO()
+
+	db := NewPostgresDatabase(config)
+	db.Connect(ctx)
+
+	const queriesCount = 10
+	q := queryImpl{
+		sqlCode: "someSqlCode",
+	}
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for id := 0; id < queriesCount; id++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rows := db.Query(ctx, q)
+			assert.Nil(rows.Err())
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	assert.Equal(queriesCount, len(mockDb.sqlQueriesReceived))
+	assert.Less(int32(1), mockDb.maxInFlight.Load())
+	assert.Less(elapsed, queriesCount*defaultSleep/2)
+}
+
+func TestPostgresDatabase_Disconnect_WhileQueriesInFlight(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	mockDb := &mockPgxDbFacade{
+		execDelay: defaultSleep,
+		tag:       "DELETE 1",
+	}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return mockDb, nil
+	}
+	ctx := context.TODO()
+
+	db := NewPostgresDatabase(config)
+	db.Connect(ctx)
+
+	const queriesCount = 5
+	q := queryImpl{
+		sqlCode: "someSqlCode",
+	}
+
+	var wg sync.WaitGroup
+	results := make([]Result, queriesCount)
+	for id := 0; id < queriesCount; id++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			results[id] = db.Execute(ctx, q)
+		}(id)
+	}
+
+	// Wait for all queries to be in flight.
+	for mockDb.inFlight.Load() < queriesCount {
+		time.Sleep(time.Millisecond)
+	}
+
+	err := db.Disconnect(ctx)
+	assert.Nil(err)
+	assert.Equal(int32(1), mockDb.closeCalled.Load())
+
+	wg.Wait()
+	for _, result := range results {
+		assert.Nil(result.Err())
+		assert.Equal(1, result.AffectedRows())
+	}
+
+	result := db.Execute(ctx, q)
+	assert.True(errors.IsErrorWithCode(result.Err(), errors.ErrDbConnectionInvalid))
+}
+
+func TestPostgresDatabase_Begin_NotConnected(t *testing.T) {
+	assert := assert.New(t)
+
+	db := NewPostgresDatabase(testConfig)
+
+	_, err := db.Begin(context.TODO())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbConnectionInvalid))
+}
+
+func TestPostgresDatabase_Begin(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	mockDb := &mockPgxDbFacade{
+		tx: &mockPgxTxFacade{},
+	}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return mockDb, nil
+	}
+	ctx := context.TODO()
+
+	db := NewPostgresDatabase(config)
+	db.Connect(ctx)
+
+	tx, err := db.Begin(ctx)
+	assert.Nil(err)
+	assert.NotNil(tx)
+}
+
+func TestPostgresDatabase_Begin_Fail(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	mockDb := &mockPgxDbFacade{
+		beginError: errDefault,
+	}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return mockDb, nil
+	}
+	ctx := context.TODO()
+
+	db := NewPostgresDatabase(config)
+	db.Connect(ctx)
+
+	_, err := db.Begin(ctx)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbTransactionBeginFailed))
+	cause := errors.Unwrap(err)
+	assert.Equal(errDefault, cause)
+}
+
+func TestPostgresDatabase_Begin_Timeout(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	config.DbQueryTimeout = defaultSleep / 2
+	mockTx := &mockPgxTxFacade{}
+	mockDb := &mockPgxDbFacade{
+		beginDelay: defaultSleep,
+		tx:         mockTx,
+	}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return mockDb, nil
+	}
+	ctx := context.TODO()
+
+	db := NewPostgresDatabase(config)
+	db.Connect(ctx)
+
+	_, err := db.Begin(ctx)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbRequestTimeout))
+	assert.Equal(int32(1), mockDb.cancelled.Load())
+	assert.Equal(int32(0), mockTx.rollbackCalled.Load())
 }
 
 type mockPgxDbFacade struct {
+	lock sync.Mutex
+
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	cancelled   atomic.Int32
+	ctxReceived context.Context
+
 	queryDelay time.Duration
-	rows       sqlRows
+	rows       DriverRows
 	queryError error
 
 	sqlQueriesReceived []string
+	sqlArgsReceived    [][]interface{}
 
 	execDelay time.Duration
 	tag       pgx.CommandTag
 	execError error
 
-	sqlExecuteReceived []string
+	sqlExecuteReceived  []string
+	sqlExecArgsReceived [][]interface{}
 
-	closeCalled int
+	copyDelay         time.Duration
+	copied            int
+	copyError         error
+	copyTableReceived pgx.Identifier
+	copyRowsReceived  [][]interface{}
+
+	closeCalled atomic.Int32
+
+	beginDelay time.Duration
+	tx         *mockPgxTxFacade
+	beginError error
+
+	pingCalled atomic.Int32
+	pingError  error
 }
 
 func (m *mockPgxDbFacade) Close() {
-	m.closeCalled++
+	m.closeCalled.Add(1)
 }
 
-func (m *mockPgxDbFacade) Query(sql string, args ...interface{}) (sqlRows, error) {
-	m.sqlQueriesReceived = append(m.sqlQueriesReceived, sql)
-	if m.queryDelay > 0 {
-		time.Sleep(m.queryDelay)
+func (m *mockPgxDbFacade) Query(ctx context.Context, sql string, args ...interface{}) (DriverRows, error) {
+	func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		m.ctxReceived = ctx
+		m.sqlQueriesReceived = append(m.sqlQueriesReceived, sql)
+		m.sqlArgsReceived = append(m.sqlArgsReceived, args)
+	}()
+
+	m.startRequest()
+	defer m.inFlight.Add(-1)
+
+	if err := m.wait(ctx, m.queryDelay); err != nil {
+		return nil, err
 	}
 	return m.rows, m.queryError
 }
 
-func (m *mockPgxDbFacade) Exec(sql string, arguments ...interface{}) (pgx.CommandTag, error) {
-	m.sqlExecuteReceived = append(m.sqlExecuteReceived, sql)
-	if m.execDelay > 0 {
-		time.Sleep(m.execDelay)
+func (m *mockPgxDbFacade) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgx.CommandTag, error) {
+	func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		m.sqlExecuteReceived = append(m.sqlExecuteReceived, sql)
+		m.sqlExecArgsReceived = append(m.sqlExecArgsReceived, arguments)
+	}()
+
+	m.startRequest()
+	defer m.inFlight.Add(-1)
+
+	if err := m.wait(ctx, m.execDelay); err != nil {
+		return "", err
 	}
 	return m.tag, m.execError
 }
 
-func mockDbCreationFunc(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+func (m *mockPgxDbFacade) startRequest() {
+	current := m.inFlight.Add(1)
+	for {
+		max := m.maxInFlight.Load()
+		if current <= max || m.maxInFlight.CompareAndSwap(max, current) {
+			return
+		}
+	}
+}
+
+// Simulates the behavior of pgx which aborts the statement as soon
+// as the context is done.
+func (m *mockPgxDbFacade) wait(ctx context.Context, delay time.Duration) error {
+	if delay == 0 {
+		return nil
+	}
+
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		m.cancelled.Add(1)
+		return ctx.Err()
+	}
+}
+
+func (m *mockPgxDbFacade) CopyFrom(tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error) {
+	m.copyTableReceived = tableName
+	for rowSrc.Next() {
+		values, _ := rowSrc.Values()
+		m.copyRowsReceived = append(m.copyRowsReceived, values)
+	}
+	if m.copyDelay > 0 {
+		time.Sleep(m.copyDelay)
+	}
+	return m.copied, m.copyError
+}
+
+func (m *mockPgxDbFacade) Begin(ctx context.Context) (DriverTx, error) {
+	if err := m.wait(ctx, m.beginDelay); err != nil {
+		return nil, err
+	}
+	if m.beginError != nil {
+		return nil, m.beginError
+	}
+	return m.tx, nil
+}
+
+func (m *mockPgxDbFacade) Ping(ctx context.Context) error {
+	m.pingCalled.Add(1)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.pingError
+}
+
+func (m *mockPgxDbFacade) setPingError(err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.pingError = err
+}
+
+func mockDbCreationFunc(config pgx.ConnPoolConfig) (DriverPool, error) {
 	return &mockPgxDbFacade{}, nil
 }
 
-func mockDbCreationFuncWithErr(config pgx.ConnPoolConfig) (pgxDbFacade, error) {
+func mockDbCreationFuncWithErr(config pgx.ConnPoolConfig) (DriverPool, error) {
 	return &mockPgxDbFacade{}, errDefault
 }
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+type postgresTransaction struct {
+	tx     DriverTx
+	config Config
+
+	// https://www.postgresql.org/docs/current/sql-savepoint.html
+	savepoint  string
+	savepoints *savepointStack
+
+	closed bool
+}
+
+// savepointStack is shared by a transaction and all the ones nested
+// in it. Releasing or rolling back a savepoint also destroys the ones
+// established after it, so the matching transactions are closed too.
+type savepointStack struct {
+	created int
+	open    []*postgresTransaction
+}
+
+func newPostgresTransaction(tx DriverTx, config Config) Transaction {
+	return &postgresTransaction{
+		tx:         tx,
+		config:     config,
+		savepoints: &savepointStack{},
+	}
+}
+
+func (t *postgresTransaction) Query(ctx context.Context, query Query) Rows {
+	if t.closed {
+		return newRows(nil, errors.NewCode(errors.ErrDbTransactionClosed))
+	}
+
+	return runQuery(ctx, t.tx, query, t.config.DbQueryTimeout, t.config.Hooks)
+}
+
+func (t *postgresTransaction) Execute(ctx context.Context, query Query) Result {
+	if t.closed {
+		return newResult("", errors.NewCode(errors.ErrDbTransactionClosed))
+	}
+
+	return runExecute(ctx, t.tx, query, t.config.DbQueryTimeout, t.config.Hooks)
+}
+
+func (t *postgresTransaction) CopyFrom(ctx context.Context, copy CopyFrom) Result {
+	if t.closed {
+		return newResult("", errors.NewCode(errors.ErrDbTransactionClosed))
+	}
+
+	return runCopyFrom(ctx, t.tx, copy, t.config.DbQueryTimeout)
+}
+
+func (t *postgresTransaction) Begin(ctx context.Context) (Transaction, error) {
+	if t.closed {
+		return nil, errors.NewCode(errors.ErrDbTransactionClosed)
+	}
+
+	t.savepoints.created++
+	nested := &postgresTransaction{
+		tx:         t.tx,
+		config:     t.config,
+		savepoint:  fmt.Sprintf("sp_%d", t.savepoints.created),
+		savepoints: t.savepoints,
+	}
+
+	if err := t.exec(ctx, fmt.Sprintf("SAVEPOINT %s", nested.savepoint)); err != nil {
+		return nil, errors.WrapCode(err, errors.ErrDbTransactionBeginFailed)
+	}
+
+	t.savepoints.open = append(t.savepoints.open, nested)
+	return nested, nil
+}
+
+func (t *postgresTransaction) Commit(ctx context.Context) error {
+	if t.closed {
+		return errors.NewCode(errors.ErrDbTransactionClosed)
+	}
+	t.close()
+
+	var err error
+	if len(t.savepoint) == 0 {
+		err = t.run(ctx, t.tx.Commit)
+	} else {
+		err = t.exec(ctx, fmt.Sprintf("RELEASE SAVEPOINT %s", t.savepoint))
+	}
+
+	if err != nil {
+		return errors.WrapCode(err, errors.ErrDbTransactionCommitFailed)
+	}
+
+	return nil
+}
+
+func (t *postgresTransaction) Rollback(ctx context.Context) error {
+	if t.closed {
+		return errors.NewCode(errors.ErrDbTransactionClosed)
+	}
+	t.close()
+
+	var err error
+	if len(t.savepoint) == 0 {
+		err = t.run(ctx, t.tx.Rollback)
+	} else {
+		err = t.exec(ctx, fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", t.savepoint))
+	}
+
+	if err != nil {
+		return errors.WrapCode(err, errors.ErrDbTransactionRollbackFailed)
+	}
+
+	return nil
+}
+
+func (t *postgresTransaction) close() {
+	t.closed = true
+
+	from := 0
+	for id, nested := range t.savepoints.open {
+		if nested == t {
+			from = id
+			break
+		}
+	}
+
+	for _, nested := range t.savepoints.open[from:] {
+		nested.closed = true
+	}
+	t.savepoints.open = t.savepoints.open[:from]
+}
+
+func (t *postgresTransaction) exec(ctx context.Context, sql string) error {
+	return t.run(ctx, func(ctx context.Context) error {
+		_, err := t.tx.Exec(ctx, sql)
+		return err
+	})
+}
+
+func (t *postgresTransaction) run(ctx context.Context, work func(ctx context.Context) error) error {
+	runCtx, cancel := withQueryTimeout(ctx, t.config.DbQueryTimeout)
+	defer cancel()
+
+	return work(runCtx)
+}
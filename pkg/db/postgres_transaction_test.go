@@ -0,0 +1,337 @@
+package db
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresTransaction_Query(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{}
+	tx := newPostgresTransaction(m, testConfig)
+
+	q := queryImpl{
+		sqlCode: "someSqlCode",
+		args:    []interface{}{"value"},
+	}
+	rows := tx.Query(context.TODO(), q)
+	assert.Nil(rows.Err())
+	assert.Equal([]string{"someSqlCode"}, m.sqlQueriesReceived)
+}
+
+func TestPostgresTransaction_Query_Fail(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{
+		queryError: errDefault,
+	}
+	tx := newPostgresTransaction(m, testConfig)
+
+	q := queryImpl{
+		sqlCode: "someSqlCode",
+	}
+	rows := tx.Query(context.TODO(), q)
+	assert.True(errors.IsErrorWithCode(rows.Err(), errors.ErrDbRequestFailed))
+	cause := errors.Unwrap(rows.Err())
+	assert.Equal(errDefault, cause)
+}
+
+func TestPostgresTransaction_Execute(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{
+		tag: "DELETE 2",
+	}
+	tx := newPostgresTransaction(m, testConfig)
+
+	q := queryImpl{
+		sqlCode: "someSqlCode",
+	}
+	result := tx.Execute(context.TODO(), q)
+	assert.Nil(result.Err())
+	assert.Equal(2, result.AffectedRows())
+	assert.Equal([]string{"someSqlCode"}, m.sqlExecuteReceived)
+}
+
+func TestPostgresTransaction_CopyFrom(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{
+		copied: 3,
+	}
+	tx := newPostgresTransaction(m, testConfig)
+
+	c := copyFromImpl{
+		table:   "table",
+		columns: []string{"a"},
+	}
+	result := tx.CopyFrom(context.TODO(), c)
+	assert.Nil(result.Err())
+	assert.Equal(3, result.AffectedRows())
+}
+
+func TestPostgresTransaction_Commit(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{}
+	tx := newPostgresTransaction(m, testConfig)
+
+	err := tx.Commit(context.TODO())
+	assert.Nil(err)
+	assert.Equal(int32(1), m.commitCalled.Load())
+
+	err = tx.Commit(context.TODO())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbTransactionClosed))
+}
+
+func TestPostgresTransaction_Commit_Fail(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{
+		commitError: errDefault,
+	}
+	tx := newPostgresTransaction(m, testConfig)
+
+	err := tx.Commit(context.TODO())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbTransactionCommitFailed))
+	cause := errors.Unwrap(err)
+	assert.Equal(errDefault, cause)
+}
+
+func TestPostgresTransaction_Rollback(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{}
+	tx := newPostgresTransaction(m, testConfig)
+
+	err := tx.Rollback(context.TODO())
+	assert.Nil(err)
+	assert.Equal(int32(1), m.rollbackCalled.Load())
+
+	err = tx.Rollback(context.TODO())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbTransactionClosed))
+}
+
+func TestPostgresTransaction_Rollback_Fail(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{
+		rollbackError: errDefault,
+	}
+	tx := newPostgresTransaction(m, testConfig)
+
+	err := tx.Rollback(context.TODO())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbTransactionRollbackFailed))
+	cause := errors.Unwrap(err)
+	assert.Equal(errDefault, cause)
+}
+
+func TestPostgresTransaction_Closed(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{}
+	tx := newPostgresTransaction(m, testConfig)
+	tx.Commit(context.TODO())
+
+	q := queryImpl{
+		sqlCode: "someSqlCode",
+	}
+
+	rows := tx.Query(context.TODO(), q)
+	assert.True(errors.IsErrorWithCode(rows.Err(), errors.ErrDbTransactionClosed))
+	result := tx.Execute(context.TODO(), q)
+	assert.True(errors.IsErrorWithCode(result.Err(), errors.ErrDbTransactionClosed))
+	_, err := tx.Begin(context.TODO())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbTransactionClosed))
+}
+
+func TestPostgresTransaction_Nested_Commit(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{}
+	tx := newPostgresTransaction(m, testConfig)
+	ctx := context.TODO()
+
+	nested, err := tx.Begin(ctx)
+	assert.Nil(err)
+	nestedTwice, err := nested.Begin(ctx)
+	assert.Nil(err)
+
+	assert.Nil(nestedTwice.Commit(ctx))
+	assert.Nil(nested.Commit(ctx))
+	assert.Nil(tx.Commit(ctx))
+
+	expected := []string{
+		"SAVEPOINT sp_1",
+		"SAVEPOINT sp_2",
+		"RELEASE SAVEPOINT sp_2",
+		"RELEASE SAVEPOINT sp_1",
+	}
+	assert.Equal(expected, m.sqlExecuteReceived)
+	assert.Equal(int32(1), m.commitCalled.Load())
+}
+
+func TestPostgresTransaction_Nested_Rollback(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{}
+	tx := newPostgresTransaction(m, testConfig)
+	ctx := context.TODO()
+
+	nested, err := tx.Begin(ctx)
+	assert.Nil(err)
+
+	assert.Nil(nested.Rollback(ctx))
+	assert.Nil(tx.Commit(ctx))
+
+	expected := []string{
+		"SAVEPOINT sp_1",
+		"ROLLBACK TO SAVEPOINT sp_1",
+	}
+	assert.Equal(expected, m.sqlExecuteReceived)
+	assert.Equal(int32(0), m.rollbackCalled.Load())
+	assert.Equal(int32(1), m.commitCalled.Load())
+}
+
+func TestPostgresTransaction_Nested_UniqueSavepoints(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{}
+	tx := newPostgresTransaction(m, testConfig)
+	ctx := context.TODO()
+
+	first, err := tx.Begin(ctx)
+	assert.Nil(err)
+	assert.Nil(first.Commit(ctx))
+	second, err := tx.Begin(ctx)
+	assert.Nil(err)
+	assert.Nil(second.Rollback(ctx))
+	assert.Nil(tx.Commit(ctx))
+
+	expected := []string{
+		"SAVEPOINT sp_1",
+		"RELEASE SAVEPOINT sp_1",
+		"SAVEPOINT sp_2",
+		"ROLLBACK TO SAVEPOINT sp_2",
+	}
+	assert.Equal(expected, m.sqlExecuteReceived)
+}
+
+func TestPostgresTransaction_Nested_ParentClosesChildren(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{}
+	tx := newPostgresTransaction(m, testConfig)
+	ctx := context.TODO()
+
+	nested, err := tx.Begin(ctx)
+	assert.Nil(err)
+	nestedTwice, err := nested.Begin(ctx)
+	assert.Nil(err)
+
+	assert.Nil(tx.Commit(ctx))
+
+	err = nested.Commit(ctx)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbTransactionClosed))
+	err = nestedTwice.Rollback(ctx)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbTransactionClosed))
+
+	expected := []string{
+		"SAVEPOINT sp_1",
+		"SAVEPOINT sp_2",
+	}
+	assert.Equal(expected, m.sqlExecuteReceived)
+	assert.Equal(int32(1), m.commitCalled.Load())
+}
+
+func TestPostgresTransaction_Nested_RollbackClosesLaterSavepoints(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{}
+	tx := newPostgresTransaction(m, testConfig)
+	ctx := context.TODO()
+
+	first, err := tx.Begin(ctx)
+	assert.Nil(err)
+	second, err := tx.Begin(ctx)
+	assert.Nil(err)
+
+	assert.Nil(first.Rollback(ctx))
+
+	rows := second.Query(ctx, queryImpl{sqlCode: "someSqlCode"})
+	assert.True(errors.IsErrorWithCode(rows.Err(), errors.ErrDbTransactionClosed))
+	_, err = tx.Begin(ctx)
+	assert.Nil(err)
+
+	expected := []string{
+		"SAVEPOINT sp_1",
+		"SAVEPOINT sp_2",
+		"ROLLBACK TO SAVEPOINT sp_1",
+		"SAVEPOINT sp_3",
+	}
+	assert.Equal(expected, m.sqlExecuteReceived)
+}
+
+func TestPostgresTransaction_Nested_BeginFail(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxTxFacade{
+		execError: errDefault,
+	}
+	tx := newPostgresTransaction(m, testConfig)
+
+	_, err := tx.Begin(context.TODO())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbTransactionBeginFailed))
+	cause := errors.Unwrap(err)
+	assert.Equal(errDefault, cause)
+}
+
+type mockPgxTxFacade struct {
+	rows       DriverRows
+	queryError error
+
+	sqlQueriesReceived []string
+
+	tag       pgx.CommandTag
+	execError error
+
+	sqlExecuteReceived []string
+
+	copied    int
+	copyError error
+
+	commitCalled   atomic.Int32
+	commitError    error
+	rollbackCalled atomic.Int32
+	rollbackError  error
+}
+
+func (m *mockPgxTxFacade) Query(ctx context.Context, sql string, args ...interface{}) (DriverRows, error) {
+	m.sqlQueriesReceived = append(m.sqlQueriesReceived, sql)
+	return m.rows, m.queryError
+}
+
+func (m *mockPgxTxFacade) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgx.CommandTag, error) {
+	m.sqlExecuteReceived = append(m.sqlExecuteReceived, sql)
+	return m.tag, m.execError
+}
+
+func (m *mockPgxTxFacade) CopyFrom(tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int, error) {
+	return m.copied, m.copyError
+}
+
+func (m *mockPgxTxFacade) Commit(ctx context.Context) error {
+	m.commitCalled.Add(1)
+	return m.commitError
+}
+
+func (m *mockPgxTxFacade) Rollback(ctx context.Context) error {
+	m.rollbackCalled.Add(1)
+	return m.rollbackError
+}
@@ -3,11 +3,13 @@ package db
 type Query interface {
 	Valid() bool
 	ToSql() string
+	Args() []interface{}
 	Verbose() bool
 }
 
 type queryImpl struct {
 	sqlCode string
+	args    []interface{}
 	verbose bool
 }
 
@@ -19,6 +21,14 @@ func (q queryImpl) ToSql() string {
 	return q.sqlCode
 }
 
+func (q queryImpl) Args() []interface{} {
+	return q.args
+}
+
 func (q queryImpl) Verbose() bool {
 	return q.verbose
 }
+
+func queryToDebugStr(q Query) string {
+	return inlinePlaceholders(q.ToSql(), q.Args())
+}
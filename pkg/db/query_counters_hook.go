@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type QueryStats struct {
+	Count         int
+	Errors        int
+	AffectedRows  int
+	TotalDuration time.Duration
+}
+
+type QueryCountersHook interface {
+	QueryHook
+	Stats() map[string]QueryStats
+}
+
+type queryCountersHook struct {
+	lock  sync.Mutex
+	stats map[string]QueryStats
+}
+
+// Counts the queries per label: see WithQueryLabel.
+func NewQueryCountersHook() QueryCountersHook {
+	return &queryCountersHook{
+		stats: make(map[string]QueryStats),
+	}
+}
+
+func (h *queryCountersHook) BeforeQuery(ctx context.Context, event QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *queryCountersHook) AfterQuery(ctx context.Context, event QueryEvent) {
+	label := event.Label
+	if len(label) == 0 {
+		label = UnlabeledQuery
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	stats := h.stats[label]
+	stats.Count++
+	if event.Err != nil {
+		stats.Errors++
+	}
+	stats.AffectedRows += event.AffectedRows
+	stats.TotalDuration += event.Duration
+	h.stats[label] = stats
+}
+
+func (h *queryCountersHook) Stats() map[string]QueryStats {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	out := make(map[string]QueryStats, len(h.stats))
+	for label, stats := range h.stats {
+		out[label] = stats
+	}
+
+	return out
+}
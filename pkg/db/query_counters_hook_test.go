@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryCountersHook(t *testing.T) {
+	assert := assert.New(t)
+
+	h := NewQueryCountersHook()
+	assert.Empty(h.Stats())
+
+	ctx := h.BeforeQuery(context.Background(), QueryEvent{})
+	h.AfterQuery(ctx, QueryEvent{Label: "a", AffectedRows: 2, Duration: time.Second})
+	h.AfterQuery(ctx, QueryEvent{Label: "a", Err: errDefault, Duration: time.Second})
+	h.AfterQuery(ctx, QueryEvent{AffectedRows: 1})
+
+	expected := map[string]QueryStats{
+		"a":            {Count: 2, Errors: 1, AffectedRows: 2, TotalDuration: 2 * time.Second},
+		UnlabeledQuery: {Count: 1, AffectedRows: 1},
+	}
+	assert.Equal(expected, h.Stats())
+}
+
+func TestQueryCountersHook_Concurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	h := NewQueryCountersHook()
+	db := newHookedDatabase(t, &mockPgxDbFacade{tag: "INSERT 0 1"}, h)
+
+	var wg sync.WaitGroup
+	for id := 0; id < 10; id++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := WithQueryLabel(context.Background(), "insert")
+			db.Execute(ctx, queryImpl{sqlCode: "INSERT INTO scores (player) VALUES ('alice')"})
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(10, h.Stats()["insert"].Count)
+	assert.Equal(10, h.Stats()["insert"].AffectedRows)
+}
@@ -4,16 +4,42 @@ import (
 	"context"
 
 	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
 )
 
 type QueryExecutor interface {
 	RunQueryAndScanSingleResult(ctx context.Context, qb QueryBuilder, parser RowParser) error
 	RunQueryAndScanAllResults(ctx context.Context, qb QueryBuilder, parser RowParser) error
+	// The rows are streamed rather than parsed all at once which suits
+	// large result sets. The iteration stops when the context is done
+	// and the rows should be closed if it is not run to its end.
+	RunQueryAndIterate(ctx context.Context, qb QueryBuilder) (Rows, error)
 	ExecuteQueryAffectingSingleRow(ctx context.Context, qb QueryBuilder) error
+	ExecuteQueryAffectingAtMostOneRow(ctx context.Context, qb QueryBuilder) (int, error)
+	ExecuteQueryAffectingAtLeastOneRow(ctx context.Context, qb QueryBuilder) (int, error)
+	ExecuteQueryAffectingRows(ctx context.Context, qb QueryBuilder, expected int) (int, error)
+	ExecuteQueryAffectingAnyRows(ctx context.Context, qb QueryBuilder) (int, error)
+	ExecuteQueryAndScanReturnedRow(ctx context.Context, qb QueryBuilder, parser RowParser) error
+	ExecuteQueryAndScanReturnedRows(ctx context.Context, qb QueryBuilder, parser RowParser) error
+	ExecuteUpsert(ctx context.Context, qb QueryBuilder) (UpsertStatus, error)
+	ExecuteCopyFrom(ctx context.Context, cb CopyFromBuilder) (int, error)
+	// Within a transaction the notification is only sent if it commits.
+	Notify(ctx context.Context, channel string, payload string) error
+
+	WithTransaction(ctx context.Context, fn TransactionFunc) error
+}
+
+type TransactionFunc func(tx QueryExecutor) error
+
+type queryRunner interface {
+	Query(ctx context.Context, query Query) Rows
+	Execute(ctx context.Context, query Query) Result
+	CopyFrom(ctx context.Context, copy CopyFrom) Result
+	Begin(ctx context.Context) (Transaction, error)
 }
 
 type queryExecutorImpl struct {
-	db Database
+	db queryRunner
 }
 
 func NewQueryExecutor(db Database) QueryExecutor {
@@ -31,7 +57,7 @@ func (qe *queryExecutorImpl) RunQueryAndScanSingleResult(ctx context.Context, qb
 	defer rows.Close()
 
 	if err := rows.GetSingleValue(parser); err != nil {
-		return errors.WrapCode(err, errors.ErrDbCorruptedData)
+		return wrapScanError(rows, err)
 	}
 
 	return nil
@@ -46,12 +72,21 @@ func (qe *queryExecutorImpl) RunQueryAndScanAllResults(ctx context.Context, qb Q
 	defer rows.Close()
 
 	if err := rows.GetAll(parser); err != nil {
-		return errors.WrapCode(err, errors.ErrDbCorruptedData)
+		return wrapScanError(rows, err)
 	}
 
 	return nil
 }
 
+func (qe *queryExecutorImpl) RunQueryAndIterate(ctx context.Context, qb QueryBuilder) (Rows, error) {
+	rows, err := qe.runQueryAndReturnRows(ctx, qb)
+	if err != nil {
+		return nil, err
+	}
+
+	return newContextRows(ctx, rows), nil
+}
+
 func (qe *queryExecutorImpl) ExecuteQueryAffectingSingleRow(ctx context.Context, qb QueryBuilder) error {
 	res, err := qe.executeQueryAndReturn(ctx, qb)
 	if err != nil {
@@ -68,6 +103,158 @@ func (qe *queryExecutorImpl) ExecuteQueryAffectingSingleRow(ctx context.Context,
 	return nil
 }
 
+func (qe *queryExecutorImpl) ExecuteQueryAffectingAtMostOneRow(ctx context.Context, qb QueryBuilder) (int, error) {
+	return qe.executeQueryAndCheckAffectedRows(ctx, qb, func(affected int) error {
+		if affected > 1 {
+			return errors.NewCode(errors.ErrSqlQueryAffectedMultipleRows)
+		}
+		return nil
+	})
+}
+
+func (qe *queryExecutorImpl) ExecuteQueryAffectingAtLeastOneRow(ctx context.Context, qb QueryBuilder) (int, error) {
+	return qe.executeQueryAndCheckAffectedRows(ctx, qb, func(affected int) error {
+		if affected == 0 {
+			return errors.NewCode(errors.ErrSqlQueryDidNotAffectAnyRow)
+		}
+		return nil
+	})
+}
+
+func (qe *queryExecutorImpl) ExecuteQueryAffectingRows(ctx context.Context, qb QueryBuilder, expected int) (int, error) {
+	return qe.executeQueryAndCheckAffectedRows(ctx, qb, func(affected int) error {
+		if affected != expected {
+			err := errors.Newf("expected %d row(s), got %d", expected, affected)
+			return errors.WrapCode(err, errors.ErrSqlQueryAffectedUnexpectedRows)
+		}
+		return nil
+	})
+}
+
+func (qe *queryExecutorImpl) ExecuteQueryAffectingAnyRows(ctx context.Context, qb QueryBuilder) (int, error) {
+	return qe.executeQueryAndCheckAffectedRows(ctx, qb, func(affected int) error {
+		return nil
+	})
+}
+
+// The queries returning rows are sent with Query: as they write they
+// have to reach the primary rather than a replica.
+func (qe *queryExecutorImpl) ExecuteQueryAndScanReturnedRow(ctx context.Context, qb QueryBuilder, parser RowParser) error {
+	rows, err := qe.runQueryAndReturnRows(WithReadYourWrites(ctx), qb)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	err = rows.GetSingleValue(parser)
+	if errors.IsErrorWithCode(err, errors.ErrNoRowsReturnedForSqlQuery) {
+		return errors.NewCode(errors.ErrSqlQueryDidNotAffectSingleRow)
+	}
+	if errors.IsErrorWithCode(err, errors.ErrMultiValuedDbElement) {
+		return errors.NewCode(errors.ErrSqlQueryAffectedMultipleRows)
+	}
+	if err != nil {
+		return wrapScanError(rows, err)
+	}
+
+	return nil
+}
+
+func (qe *queryExecutorImpl) ExecuteQueryAndScanReturnedRows(ctx context.Context, qb QueryBuilder, parser RowParser) error {
+	return qe.RunQueryAndScanAllResults(WithReadYourWrites(ctx), qb, parser)
+}
+
+func (qe *queryExecutorImpl) ExecuteUpsert(ctx context.Context, qb QueryBuilder) (UpsertStatus, error) {
+	query, err := qb.Build()
+	if err != nil {
+		return UpsertSkipped, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+	}
+	// Without the status column an empty result could not be told
+	// apart from a skipped row.
+	if upsert, ok := qb.(UpsertQueryBuilder); !ok || !upsert.ReturnsUpsertStatus() {
+		return UpsertSkipped, errors.WrapCode(errors.NewCode(errors.ErrInvalidQuery), errors.ErrDbRequestCreationFailed)
+	}
+
+	rows := qe.db.Query(WithReadYourWrites(ctx), query)
+	defer rows.Close()
+	if err := rows.Err(); err != nil {
+		return UpsertSkipped, err
+	}
+
+	if rows.Empty() {
+		return UpsertSkipped, nil
+	}
+
+	parser := &upsertStatusParser{}
+	if err := rows.GetSingleValue(parser); err != nil {
+		return UpsertSkipped, wrapScanError(rows, err)
+	}
+
+	return parser.status, nil
+}
+
+func (qe *queryExecutorImpl) ExecuteCopyFrom(ctx context.Context, cb CopyFromBuilder) (int, error) {
+	copy, err := cb.BuildCopyFrom()
+	if err != nil {
+		return 0, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+	}
+
+	res := qe.db.CopyFrom(ctx, copy)
+	if err := res.Err(); err != nil {
+		return 0, err
+	}
+
+	return res.AffectedRows(), nil
+}
+
+// https://www.postgresql.org/docs/current/functions-info.html#FUNCTIONS-INFO-SESSION
+func (qe *queryExecutorImpl) Notify(ctx context.Context, channel string, payload string) error {
+	if len(channel) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlNotificationChannel)
+	}
+
+	qb := NewRawQueryBuilder()
+	qb.SetSql(notifySql)
+	qb.AddArg(channel)
+	qb.AddArg(payload)
+
+	_, err := qe.ExecuteQueryAffectingAnyRows(ctx, qb)
+	return err
+}
+
+// https://pkg.go.dev/database/sql#Tx
+func (qe *queryExecutorImpl) WithTransaction(ctx context.Context, fn TransactionFunc) error {
+	tx, err := qe.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			rollbackTransaction(ctx, tx)
+			panic(r)
+		}
+	}()
+
+	txQe := &queryExecutorImpl{
+		db: tx,
+	}
+
+	if err := fn(txQe); err != nil {
+		rollbackTransaction(ctx, tx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func rollbackTransaction(ctx context.Context, tx Transaction) {
+	if err := tx.Rollback(ctx); err != nil {
+		logger.ScopedErrorf(ctx, "failed to rollback transaction (err: %v)", err)
+	}
+}
+
 func (qe *queryExecutorImpl) runQueryAndReturnRows(ctx context.Context, qb QueryBuilder) (Rows, error) {
 	query, err := qb.Build()
 	if err != nil {
@@ -76,12 +263,27 @@ func (qe *queryExecutorImpl) runQueryAndReturnRows(ctx context.Context, qb Query
 
 	rows := qe.db.Query(ctx, query)
 	if err := rows.Err(); err != nil {
+		rows.Close()
 		return nil, err
 	}
 
 	return rows, nil
 }
 
+func (qe *queryExecutorImpl) executeQueryAndCheckAffectedRows(ctx context.Context, qb QueryBuilder, check func(affected int) error) (int, error) {
+	res, err := qe.executeQueryAndReturn(ctx, qb)
+	if err != nil {
+		return 0, err
+	}
+
+	affected := res.AffectedRows()
+	if err := check(affected); err != nil {
+		return affected, err
+	}
+
+	return affected, nil
+}
+
 func (qe *queryExecutorImpl) executeQueryAndReturn(ctx context.Context, qb QueryBuilder) (Result, error) {
 	query, err := qb.Build()
 	if err != nil {
@@ -95,3 +297,13 @@ func (qe *queryExecutorImpl) executeQueryAndReturn(ctx context.Context, qb Query
 
 	return res, nil
 }
+
+// The server can report an error after some rows were received: it is
+// returned as is so that its code is not hidden behind a parsing error.
+func wrapScanError(rows Rows, err error) error {
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return rowsErr
+	}
+
+	return errors.WrapCode(err, errors.ErrDbCorruptedData)
+}
@@ -2,9 +2,11 @@ package db
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/jackc/pgx"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -204,6 +206,86 @@ func TestQueryExecutor_RunQueryAndScanAllResults_Error(t *testing.T) {
 	assert.Equal(errDefault, err)
 }
 
+func TestQueryExecutor_RunQueryAndScanAllResults_ErrorAfterRows(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	pgErr := pgx.PgError{Code: serializationFailure}
+	mdb := &mockDb{
+		rows: newRows(&mockSqlRows{numberOfRows: 2, err: wrapSqlStateError(pgErr)}, nil),
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	err := qe.RunQueryAndScanAllResults(context.TODO(), mqb, &mockParser{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbSerializationFailure))
+	assert.Equal(pgErr, errorCause(err))
+}
+
+func TestQueryExecutor_RunQueryAndIterate_QueryError(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	mockRows := &mockRows{err: errDefault}
+	mdb := &mockDb{
+		rows: mockRows,
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	rows, err := qe.RunQueryAndIterate(context.TODO(), mqb)
+	assert.Nil(rows)
+	assert.Equal(errDefault, err)
+	assert.Equal(1, mockRows.closeCalled)
+}
+
+func TestQueryExecutor_RunQueryAndIterate(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	m := &mockSqlRows{numberOfRows: 2}
+	mdb := &mockDb{
+		rows: newRows(m, nil),
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	rows, err := qe.RunQueryAndIterate(context.TODO(), mqb)
+	assert.Nil(err)
+
+	mp := &mockParser{}
+	for rows.Next() {
+		assert.Nil(rows.Scan(mp))
+	}
+	assert.Nil(rows.Err())
+	assert.Equal(2, mp.parseCalled)
+	assert.Equal(int32(1), m.closeCalls.Load())
+}
+
+func TestQueryExecutor_RunQueryAndIterate_ContextCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	m := &mockSqlRows{numberOfRows: 3}
+	mdb := &mockDb{
+		rows: newRows(m, nil),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	qe := NewQueryExecutor(mdb)
+
+	rows, err := qe.RunQueryAndIterate(ctx, mqb)
+	assert.Nil(err)
+
+	assert.True(rows.Next())
+	cancel()
+	assert.False(rows.Next())
+	assert.False(rows.Next())
+	assert.True(errors.IsErrorWithCode(rows.Err(), errors.ErrDbRequestFailed))
+	assert.Equal(context.Canceled, errorCause(rows.Err()))
+	assert.Equal(int32(1), m.closeCalls.Load())
+}
+
 func TestQueryExecutor_RunQueryAndScanAllResults_ScanError(t *testing.T) {
 	assert := assert.New(t)
 
@@ -293,6 +375,342 @@ func TestQueryExecutor_ExecuteQueryAffectingSingleRow_MultipleRowsAffected(t *te
 	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlQueryAffectedMultipleRows))
 }
 
+func TestQueryExecutor_ExecuteQueryAffectingAtMostOneRow(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	for _, affected := range []int{0, 1} {
+		mdb := &mockDb{
+			result: &mockResult{
+				affectedRows: affected,
+			},
+		}
+		qe := NewQueryExecutor(mdb)
+
+		n, err := qe.ExecuteQueryAffectingAtMostOneRow(context.TODO(), mqb)
+		assert.Nil(err)
+		assert.Equal(affected, n)
+	}
+
+	mdb := &mockDb{
+		result: &mockResult{
+			affectedRows: 2,
+		},
+	}
+	qe := NewQueryExecutor(mdb)
+
+	n, err := qe.ExecuteQueryAffectingAtMostOneRow(context.TODO(), mqb)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlQueryAffectedMultipleRows))
+	assert.Equal(2, n)
+}
+
+func TestQueryExecutor_ExecuteQueryAffectingAtLeastOneRow(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	mdb := &mockDb{
+		result: &mockResult{
+			affectedRows: 4,
+		},
+	}
+	qe := NewQueryExecutor(mdb)
+
+	n, err := qe.ExecuteQueryAffectingAtLeastOneRow(context.TODO(), mqb)
+	assert.Nil(err)
+	assert.Equal(4, n)
+
+	mdb = &mockDb{
+		result: &mockResult{
+			affectedRows: 0,
+		},
+	}
+	qe = NewQueryExecutor(mdb)
+
+	_, err = qe.ExecuteQueryAffectingAtLeastOneRow(context.TODO(), mqb)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlQueryDidNotAffectAnyRow))
+}
+
+func TestQueryExecutor_ExecuteQueryAffectingRows(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	mdb := &mockDb{
+		result: &mockResult{
+			affectedRows: 3,
+		},
+	}
+	qe := NewQueryExecutor(mdb)
+
+	n, err := qe.ExecuteQueryAffectingRows(context.TODO(), mqb, 3)
+	assert.Nil(err)
+	assert.Equal(3, n)
+
+	n, err = qe.ExecuteQueryAffectingRows(context.TODO(), mqb, 2)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlQueryAffectedUnexpectedRows))
+	assert.Equal(3, n)
+}
+
+func TestQueryExecutor_ExecuteQueryAffectingAnyRows(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	mdb := &mockDb{
+		result: &mockResult{
+			affectedRows: 17,
+		},
+	}
+	qe := NewQueryExecutor(mdb)
+
+	n, err := qe.ExecuteQueryAffectingAnyRows(context.TODO(), mqb)
+	assert.Nil(err)
+	assert.Equal(17, n)
+	assert.Equal(1, mdb.executeCalls)
+}
+
+func TestQueryExecutor_ExecuteQueryAffectingAnyRows_ExecuteError(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	mdb := &mockDb{
+		result: &mockResult{
+			err: errDefault,
+		},
+	}
+	qe := NewQueryExecutor(mdb)
+
+	n, err := qe.ExecuteQueryAffectingAnyRows(context.TODO(), mqb)
+	assert.Equal(errDefault, err)
+	assert.Equal(0, n)
+}
+
+func TestQueryExecutor_ExecuteQueryAndScanReturnedRow(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	mr := &mockRows{}
+	mdb := &mockDb{
+		rows: mr,
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	err := qe.ExecuteQueryAndScanReturnedRow(context.TODO(), mqb, &mockParser{})
+	assert.Nil(err)
+	assert.Equal(1, mdb.queryCalls)
+	assert.Equal(0, mdb.executeCalls)
+	assert.Equal(1, mr.singleValueCalled)
+	assert.Equal(1, mr.closeCalled)
+}
+
+func TestQueryExecutor_ExecuteQueryAndScanReturnedRow_Error(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	mdb := &mockDb{
+		rows: &mockRows{
+			err: errDefault,
+		},
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	err := qe.ExecuteQueryAndScanReturnedRow(context.TODO(), mqb, &mockParser{})
+	assert.Equal(errDefault, err)
+}
+
+func TestQueryExecutor_ExecuteQueryAndScanReturnedRow_NoRowsAffected(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	mdb := &mockDb{
+		rows: &mockRows{
+			getSingleValueErr: errors.NewCode(errors.ErrNoRowsReturnedForSqlQuery),
+		},
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	err := qe.ExecuteQueryAndScanReturnedRow(context.TODO(), mqb, &mockParser{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlQueryDidNotAffectSingleRow))
+}
+
+func TestQueryExecutor_ExecuteQueryAndScanReturnedRow_MultipleRowsAffected(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	mdb := &mockDb{
+		rows: &mockRows{
+			getSingleValueErr: errors.NewCode(errors.ErrMultiValuedDbElement),
+		},
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	err := qe.ExecuteQueryAndScanReturnedRow(context.TODO(), mqb, &mockParser{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlQueryAffectedMultipleRows))
+}
+
+func TestQueryExecutor_ExecuteQueryAndScanReturnedRow_ScanError(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	mr := &mockRows{
+		getSingleValueErr: errDefault,
+	}
+	mdb := &mockDb{
+		rows: mr,
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	err := qe.ExecuteQueryAndScanReturnedRow(context.TODO(), mqb, &mockParser{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbCorruptedData))
+	cause := errors.Unwrap(err)
+	assert.Equal(errDefault, cause)
+	assert.Equal(1, mr.closeCalled)
+}
+
+func TestQueryExecutor_ExecuteQueryAndScanReturnedRows(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{}
+	mr := &mockRows{}
+	mdb := &mockDb{
+		rows: mr,
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	err := qe.ExecuteQueryAndScanReturnedRows(context.TODO(), mqb, &mockParser{})
+	assert.Nil(err)
+	assert.Equal(1, mdb.queryCalls)
+	assert.Equal(1, mr.allCalled)
+	assert.Equal(1, mr.closeCalled)
+}
+
+func TestQueryExecutor_WithTransaction_BeginError(t *testing.T) {
+	assert := assert.New(t)
+
+	mdb := &mockDb{
+		beginErr: errDefault,
+	}
+	qe := NewQueryExecutor(mdb)
+
+	called := false
+	err := qe.WithTransaction(context.TODO(), func(tx QueryExecutor) error {
+		called = true
+		return nil
+	})
+	assert.Equal(errDefault, err)
+	assert.False(called)
+}
+
+func TestQueryExecutor_WithTransaction(t *testing.T) {
+	assert := assert.New(t)
+
+	mtx := &mockTransaction{
+		result: &mockResult{affectedRows: 1},
+	}
+	mdb := &mockDb{
+		tx: mtx,
+	}
+	mqb := &mockQueryBuilderWithQuery{}
+	qe := NewQueryExecutor(mdb)
+
+	err := qe.WithTransaction(context.TODO(), func(tx QueryExecutor) error {
+		return tx.ExecuteQueryAffectingSingleRow(context.TODO(), mqb)
+	})
+	assert.Nil(err)
+	assert.Equal(1, mdb.beginCalls)
+	assert.Equal(0, mdb.executeCalls)
+	assert.Equal(1, len(mtx.executions))
+	assert.Equal(1, mtx.commitCalled)
+	assert.Equal(0, mtx.rollbackCalled)
+}
+
+func TestQueryExecutor_WithTransaction_CommitError(t *testing.T) {
+	assert := assert.New(t)
+
+	mtx := &mockTransaction{
+		commitErr: errDefault,
+	}
+	mdb := &mockDb{
+		tx: mtx,
+	}
+	qe := NewQueryExecutor(mdb)
+
+	err := qe.WithTransaction(context.TODO(), func(tx QueryExecutor) error {
+		return nil
+	})
+	assert.Equal(errDefault, err)
+	assert.Equal(1, mtx.commitCalled)
+}
+
+func TestQueryExecutor_WithTransaction_RollbackOnError(t *testing.T) {
+	assert := assert.New(t)
+
+	mtx := &mockTransaction{
+		rollbackErr: errDefault,
+	}
+	mdb := &mockDb{
+		tx: mtx,
+	}
+	qe := NewQueryExecutor(mdb)
+
+	errFunc := fmt.Errorf("funcError")
+	err := qe.WithTransaction(context.TODO(), func(tx QueryExecutor) error {
+		return errFunc
+	})
+	assert.Equal(errFunc, err)
+	assert.Equal(0, mtx.commitCalled)
+	assert.Equal(1, mtx.rollbackCalled)
+}
+
+func TestQueryExecutor_WithTransaction_RollbackOnPanic(t *testing.T) {
+	assert := assert.New(t)
+
+	mtx := &mockTransaction{}
+	mdb := &mockDb{
+		tx: mtx,
+	}
+	qe := NewQueryExecutor(mdb)
+
+	assert.PanicsWithValue("somePanic", func() {
+		qe.WithTransaction(context.TODO(), func(tx QueryExecutor) error {
+			panic("somePanic")
+		})
+	})
+	assert.Equal(0, mtx.commitCalled)
+	assert.Equal(1, mtx.rollbackCalled)
+}
+
+func TestQueryExecutor_WithTransaction_Nested(t *testing.T) {
+	assert := assert.New(t)
+
+	nested := &mockTransaction{}
+	mtx := &mockTransaction{
+		nested: nested,
+	}
+	mdb := &mockDb{
+		tx: mtx,
+	}
+	qe := NewQueryExecutor(mdb)
+
+	errFunc := fmt.Errorf("funcError")
+	err := qe.WithTransaction(context.TODO(), func(tx QueryExecutor) error {
+		nestedErr := tx.WithTransaction(context.TODO(), func(tx QueryExecutor) error {
+			return errFunc
+		})
+		assert.Equal(errFunc, nestedErr)
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal(1, nested.rollbackCalled)
+	assert.Equal(0, nested.commitCalled)
+	assert.Equal(1, mtx.commitCalled)
+	assert.Equal(0, mtx.rollbackCalled)
+}
+
 type mockQueryBuilder struct {
 	buildErr error
 }
@@ -301,7 +719,25 @@ func (m mockQueryBuilder) Build() (Query, error) {
 	return nil, m.buildErr
 }
 
+type mockQueryBuilderWithQuery struct{}
+
+func (m *mockQueryBuilderWithQuery) Build() (Query, error) {
+	return queryImpl{sqlCode: "someSqlCode"}, nil
+}
+
+type mockUpsertQueryBuilder struct{}
+
+func (m mockUpsertQueryBuilder) Build() (Query, error) {
+	sqlCode := "INSERT INTO table (column) VALUES ($1) ON CONFLICT (column) DO UPDATE SET column = EXCLUDED.column RETURNING " + UpsertStatusColumn
+	return queryImpl{sqlCode: sqlCode}, nil
+}
+
+func (m mockUpsertQueryBuilder) ReturnsUpsertStatus() bool {
+	return true
+}
+
 type mockDb struct {
+	connectCalls  int
 	connectErr    error
 	disconnectErr error
 
@@ -312,9 +748,21 @@ type mockDb struct {
 	executeCalls int
 	executions   []Query
 	result       Result
+
+	copyCalls int
+	copies    []CopyFrom
+
+	beginCalls int
+	tx         *mockTransaction
+	beginErr   error
+
+	subscribeCalls int
+	subscription   Subscription
+	subscribeErr   error
 }
 
 func (m *mockDb) Connect(ctx context.Context) error {
+	m.connectCalls++
 	return m.connectErr
 }
 
@@ -334,6 +782,299 @@ func (m *mockDb) Execute(ctx context.Context, query Query) Result {
 	return m.result
 }
 
+func (m *mockDb) CopyFrom(ctx context.Context, copy CopyFrom) Result {
+	m.copies = append(m.copies, copy)
+	m.copyCalls++
+	return m.result
+}
+
+func (m *mockDb) Begin(ctx context.Context) (Transaction, error) {
+	m.beginCalls++
+	if m.beginErr != nil {
+		return nil, m.beginErr
+	}
+	return m.tx, nil
+}
+
+func (m *mockDb) Subscribe(ctx context.Context, channel string) (Subscription, error) {
+	m.subscribeCalls++
+	return m.subscription, m.subscribeErr
+}
+
+type mockTransaction struct {
+	queries    []Query
+	rows       Rows
+	executions []Query
+	result     Result
+
+	nested   *mockTransaction
+	beginErr error
+
+	commitCalled   int
+	commitErr      error
+	rollbackCalled int
+	rollbackErr    error
+}
+
+func (m *mockTransaction) Query(ctx context.Context, query Query) Rows {
+	m.queries = append(m.queries, query)
+	return m.rows
+}
+
+func (m *mockTransaction) Execute(ctx context.Context, query Query) Result {
+	m.executions = append(m.executions, query)
+	return m.result
+}
+
+func (m *mockTransaction) CopyFrom(ctx context.Context, copy CopyFrom) Result {
+	return m.result
+}
+
+func (m *mockTransaction) Begin(ctx context.Context) (Transaction, error) {
+	if m.beginErr != nil {
+		return nil, m.beginErr
+	}
+	return m.nested, nil
+}
+
+func (m *mockTransaction) Commit(ctx context.Context) error {
+	m.commitCalled++
+	return m.commitErr
+}
+
+func (m *mockTransaction) Rollback(ctx context.Context) error {
+	m.rollbackCalled++
+	return m.rollbackErr
+}
+
+func TestQueryExecutor_ExecuteUpsert_Inserted(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockUpsertQueryBuilder{}
+	mr := &mockRows{
+		getSingleValueScannable: &mockUpsertStatusScannable{inserted: true},
+	}
+	mdb := &mockDb{
+		rows: mr,
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	status, err := qe.ExecuteUpsert(context.TODO(), mqb)
+	assert.Nil(err)
+	assert.Equal(UpsertInserted, status)
+	assert.Equal(1, mr.singleValueCalled)
+	assert.Equal(1, mr.closeCalled)
+}
+
+func TestQueryExecutor_ExecuteUpsert_Updated(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockUpsertQueryBuilder{}
+	mr := &mockRows{
+		getSingleValueScannable: &mockUpsertStatusScannable{inserted: false},
+	}
+	mdb := &mockDb{
+		rows: mr,
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	status, err := qe.ExecuteUpsert(context.TODO(), mqb)
+	assert.Nil(err)
+	assert.Equal(UpsertUpdated, status)
+	assert.Equal(1, mr.singleValueCalled)
+	assert.Equal(1, mr.closeCalled)
+}
+
+func TestQueryExecutor_ExecuteUpsert_BuildError(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockQueryBuilder{
+		buildErr: errDefault,
+	}
+	mdb := &mockDb{}
+
+	qe := NewQueryExecutor(mdb)
+
+	_, err := qe.ExecuteUpsert(context.TODO(), mqb)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbRequestCreationFailed))
+	assert.Equal(0, mdb.queryCalls)
+}
+
+func TestQueryExecutor_ExecuteUpsert_NoStatusColumn(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := &mockQueryBuilderWithQuery{}
+	mdb := &mockDb{}
+
+	qe := NewQueryExecutor(mdb)
+
+	_, err := qe.ExecuteUpsert(context.TODO(), mqb)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbRequestCreationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrInvalidQuery))
+	assert.Equal(0, mdb.queryCalls)
+}
+
+func TestQueryExecutor_ExecuteUpsert_OtherColumn(t *testing.T) {
+	assert := assert.New(t)
+
+	qb := NewInsertQueryBuilder()
+	qb.SetTable("table")
+	qb.AddElement("column", "prop")
+	qb.AddReturningUpsertStatus()
+	qb.AddReturning("id")
+	mdb := &mockDb{}
+
+	qe := NewQueryExecutor(mdb)
+
+	_, err := qe.ExecuteUpsert(context.TODO(), qb)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbRequestCreationFailed))
+	assert.Equal(0, mdb.queryCalls)
+}
+
+func TestQueryExecutor_ExecuteUpsert_Skipped(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockUpsertQueryBuilder{}
+	mr := &mockRows{
+		empty: true,
+	}
+	mdb := &mockDb{
+		rows: mr,
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	status, err := qe.ExecuteUpsert(context.TODO(), mqb)
+	assert.Nil(err)
+	assert.Equal(UpsertSkipped, status)
+	assert.Equal(0, mr.singleValueCalled)
+}
+
+func TestQueryExecutor_ExecuteUpsert_Error(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockUpsertQueryBuilder{}
+	mdb := &mockDb{
+		rows: &mockRows{
+			err: errDefault,
+		},
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	_, err := qe.ExecuteUpsert(context.TODO(), mqb)
+	assert.Equal(errDefault, err)
+}
+
+func TestQueryExecutor_ExecuteUpsert_ScanError(t *testing.T) {
+	assert := assert.New(t)
+
+	mqb := mockUpsertQueryBuilder{}
+	mdb := &mockDb{
+		rows: &mockRows{
+			getSingleValueErr: errDefault,
+		},
+	}
+
+	qe := NewQueryExecutor(mdb)
+
+	_, err := qe.ExecuteUpsert(context.TODO(), mqb)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbCorruptedData))
+}
+
+func TestQueryExecutor_ExecuteCopyFrom_BuildError(t *testing.T) {
+	assert := assert.New(t)
+
+	mdb := &mockDb{}
+	qe := NewQueryExecutor(mdb)
+
+	b := NewBulkInsertQueryBuilder()
+	_, err := qe.ExecuteCopyFrom(context.TODO(), b)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbRequestCreationFailed))
+	assert.Equal(0, mdb.copyCalls)
+}
+
+func TestQueryExecutor_ExecuteCopyFrom(t *testing.T) {
+	assert := assert.New(t)
+
+	mdb := &mockDb{
+		result: newResult("COPY 2", nil),
+	}
+	qe := NewQueryExecutor(mdb)
+
+	b := NewBulkInsertQueryBuilder()
+	b.SetTable("table")
+	b.SetColumns("a")
+	b.AddRow(1)
+	b.AddRow(2)
+
+	n, err := qe.ExecuteCopyFrom(context.TODO(), b)
+	assert.Nil(err)
+	assert.Equal(2, n)
+	assert.Equal(1, mdb.copyCalls)
+	assert.Equal([][]interface{}{{1}, {2}}, mdb.copies[0].Rows())
+}
+
+func TestQueryExecutor_ExecuteCopyFrom_Error(t *testing.T) {
+	assert := assert.New(t)
+
+	mdb := &mockDb{
+		result: newResult("", errDefault),
+	}
+	qe := NewQueryExecutor(mdb)
+
+	b := NewBulkInsertQueryBuilder()
+	b.SetTable("table")
+	b.SetColumns("a")
+	b.AddRow(1)
+
+	_, err := qe.ExecuteCopyFrom(context.TODO(), b)
+	assert.Equal(errDefault, err)
+}
+
+func TestQueryExecutor_Notify(t *testing.T) {
+	assert := assert.New(t)
+
+	mdb := &mockDb{
+		result: newResult("SELECT 1", nil),
+	}
+	qe := NewQueryExecutor(mdb)
+
+	err := qe.Notify(context.TODO(), "channel", "payload")
+	assert.Nil(err)
+	assert.Equal(1, mdb.executeCalls)
+	assert.Equal("SELECT pg_notify($1, $2)", mdb.executions[0].ToSql())
+	assert.Equal([]interface{}{"channel", "payload"}, mdb.executions[0].Args())
+}
+
+func TestQueryExecutor_Notify_Error(t *testing.T) {
+	assert := assert.New(t)
+
+	mdb := &mockDb{
+		result: newResult("", errDefault),
+	}
+	qe := NewQueryExecutor(mdb)
+
+	err := qe.Notify(context.TODO(), "", "payload")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlNotificationChannel))
+	assert.Equal(0, mdb.executeCalls)
+
+	err = qe.Notify(context.TODO(), "channel", "payload")
+	assert.Equal(errDefault, err)
+}
+
+type mockUpsertStatusScannable struct {
+	inserted bool
+}
+
+func (m *mockUpsertStatusScannable) Scan(dest ...interface{}) error {
+	*(dest[0].(*bool)) = m.inserted
+	return nil
+}
+
 type mockRows struct {
 	err         error
 	closeCalled int
@@ -378,6 +1119,14 @@ func (m *mockRows) GetAll(parser RowParser) error {
 	return m.getAllErr
 }
 
+func (m *mockRows) Next() bool {
+	return false
+}
+
+func (m *mockRows) Scan(parser RowParser) error {
+	return m.getAllErr
+}
+
 type mockResult struct {
 	err                error
 	affectedRowsCalled int
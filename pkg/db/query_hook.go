@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type QueryOperation int
+
+const (
+	QueryRows QueryOperation = iota
+	QueryExecution
+)
+
+// Describes a query sent to the database. The duration, affected rows
+// and error are only set once the query is done: for queries returning
+// rows this is when the rows are closed and the affected rows are the
+// rows which were iterated.
+type QueryEvent struct {
+	Operation    QueryOperation
+	Query        Query
+	Label        string
+	Duration     time.Duration
+	AffectedRows int
+	Err          error
+}
+
+// Hooks are called in order before the query and in reverse order
+// after it, like http middlewares. The context returned by BeforeQuery
+// is passed to the next hooks, to the query and to AfterQuery.
+type QueryHook interface {
+	BeforeQuery(ctx context.Context, event QueryEvent) context.Context
+	AfterQuery(ctx context.Context, event QueryEvent)
+}
+
+// Used by the hooks for the queries sent without a label.
+const UnlabeledQuery = "unlabeled"
+
+type queryLabelKeyType string
+
+const queryLabelKey queryLabelKeyType = "query-label"
+
+func WithQueryLabel(ctx context.Context, label string) context.Context {
+	return context.WithValue(ctx, queryLabelKey, label)
+}
+
+func QueryLabelFromContext(ctx context.Context) string {
+	label, _ := ctx.Value(queryLabelKey).(string)
+	return label
+}
+
+func runBeforeQueryHooks(ctx context.Context, hooks []QueryHook, event QueryEvent) context.Context {
+	for _, hook := range hooks {
+		ctx = hook.BeforeQuery(ctx, event)
+	}
+
+	return ctx
+}
+
+func runAfterQueryHooks(ctx context.Context, hooks []QueryHook, event QueryEvent) {
+	for id := len(hooks) - 1; id >= 0; id-- {
+		hooks[id].AfterQuery(ctx, event)
+	}
+}
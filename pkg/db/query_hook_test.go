@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockHookKey string
+
+type mockQueryHook struct {
+	name  string
+	lock  sync.Mutex
+	calls *[]string
+
+	before []QueryEvent
+	after  []QueryEvent
+	values []interface{}
+}
+
+func (m *mockQueryHook) BeforeQuery(ctx context.Context, event QueryEvent) context.Context {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.before = append(m.before, event)
+	if m.calls != nil {
+		*m.calls = append(*m.calls, "before-"+m.name)
+	}
+	return context.WithValue(ctx, mockHookKey(m.name), m.name)
+}
+
+func (m *mockQueryHook) AfterQuery(ctx context.Context, event QueryEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.after = append(m.after, event)
+	if m.calls != nil {
+		*m.calls = append(*m.calls, "after-"+m.name)
+	}
+	m.values = append(m.values, ctx.Value(mockHookKey(m.name)))
+}
+
+func newHookedDatabase(t *testing.T, pool *mockPgxDbFacade, hooks ...QueryHook) Database {
+	conf := testConfig
+	conf.Hooks = hooks
+	conf.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return pool, nil
+	}
+
+	db := NewPostgresDatabase(conf)
+	assert.Nil(t, db.Connect(context.Background()))
+	return db
+}
+
+func TestQueryLabel(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("", QueryLabelFromContext(context.Background()))
+
+	ctx := WithQueryLabel(context.Background(), "label")
+	assert.Equal("label", QueryLabelFromContext(ctx))
+}
+
+func TestQueryHooks_Order(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	h1 := &mockQueryHook{name: "h1", calls: &calls}
+	h2 := &mockQueryHook{name: "h2", calls: &calls}
+	hooks := []QueryHook{h1, h2}
+
+	ctx := runBeforeQueryHooks(context.Background(), hooks, QueryEvent{})
+	runAfterQueryHooks(ctx, hooks, QueryEvent{})
+
+	assert.Equal([]string{"before-h1", "before-h2", "after-h2", "after-h1"}, calls)
+	assert.Equal([]interface{}{"h1"}, h1.values)
+	assert.Equal([]interface{}{"h2"}, h2.values)
+}
+
+func TestQueryHooks_Execute(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockQueryHook{name: "hook"}
+	db := newHookedDatabase(t, &mockPgxDbFacade{tag: "INSERT 0 2"}, m)
+
+	ctx := WithQueryLabel(context.Background(), "insert")
+	query := queryImpl{sqlCode: "INSERT INTO scores (player, points) VALUES ($1, 1), ($1, 2)", args: []interface{}{"alice"}}
+	res := db.Execute(ctx, query)
+	assert.Nil(res.Err())
+
+	assert.Equal(1, len(m.before))
+	assert.Equal(QueryExecution, m.before[0].Operation)
+	assert.Equal(query, m.before[0].Query)
+	assert.Equal("insert", m.before[0].Label)
+
+	assert.Equal(1, len(m.after))
+	assert.Equal(2, m.after[0].AffectedRows)
+	assert.Nil(m.after[0].Err)
+	assert.Equal([]interface{}{"hook"}, m.values)
+}
+
+func TestQueryHooks_Query(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockQueryHook{name: "hook"}
+	pool := &mockPgxDbFacade{tag: "INSERT 0 2", rows: &mockSqlRows{numberOfRows: 2}}
+	db := newHookedDatabase(t, pool, m)
+	db.Execute(context.Background(), queryImpl{sqlCode: "INSERT INTO scores (player, points) VALUES ('alice', 1), ('bob', 2)"})
+
+	rows := db.Query(context.Background(), queryImpl{sqlCode: "SELECT player FROM scores"})
+	assert.Nil(rows.Err())
+	assert.Equal(2, len(m.before))
+	assert.Equal(QueryRows, m.before[1].Operation)
+	assert.Equal(1, len(m.after))
+
+	assert.Nil(rows.GetAll(&mockParser{}))
+	rows.Close()
+	assert.Equal(2, len(m.after))
+	assert.Equal(2, m.after[1].AffectedRows)
+	assert.Nil(m.after[1].Err)
+}
+
+func TestQueryHooks_Error(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockQueryHook{name: "hook"}
+	db := newHookedDatabase(t, &mockPgxDbFacade{queryError: errDefault, execError: errDefault}, m)
+
+	rows := db.Query(context.Background(), queryImpl{sqlCode: "SELECT unknown FROM players"})
+	assert.True(errors.IsErrorWithCode(rows.Err(), errors.ErrDbRequestFailed))
+	assert.Equal(1, len(m.after))
+	assert.Equal(rows.Err(), m.after[0].Err)
+
+	res := db.Execute(context.Background(), queryImpl{sqlCode: "DELETE FROM unknown"})
+	assert.True(errors.IsErrorWithCode(res.Err(), errors.ErrDbRequestFailed))
+	assert.Equal(2, len(m.after))
+	assert.Equal(res.Err(), m.after[1].Err)
+}
+
+func TestQueryHooks_InvalidQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockQueryHook{name: "hook"}
+	db := newHookedDatabase(t, &mockPgxDbFacade{}, m)
+
+	db.Query(context.Background(), queryImpl{})
+	db.Execute(context.Background(), queryImpl{})
+	assert.Empty(m.before)
+	assert.Empty(m.after)
+}
+
+func TestQueryHooks_Transaction(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockQueryHook{name: "hook"}
+	pool := &mockPgxDbFacade{
+		tx: &mockPgxTxFacade{tag: "INSERT 0 1", rows: &mockSqlRows{}},
+	}
+	db := newHookedDatabase(t, pool, m)
+
+	tx, err := db.Begin(context.Background())
+	assert.Nil(err)
+
+	res := tx.Execute(context.Background(), queryImpl{sqlCode: "INSERT INTO scores (player) VALUES ('alice')"})
+	assert.Nil(res.Err())
+	rows := tx.Query(context.Background(), queryImpl{sqlCode: "SELECT player FROM scores"})
+	rows.Close()
+	assert.Nil(tx.Commit(context.Background()))
+
+	assert.Equal(2, len(m.before))
+	assert.Equal(2, len(m.after))
+}
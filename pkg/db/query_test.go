@@ -35,3 +35,23 @@ func TestQuery_Verbose(t *testing.T) {
 	q.verbose = true
 	assert.True(q.Verbose())
 }
+
+func TestQuery_Args(t *testing.T) {
+	assert := assert.New(t)
+
+	q := queryImpl{}
+	assert.Nil(q.Args())
+
+	q.args = []interface{}{"value", 32}
+	assert.Equal([]interface{}{"value", 32}, q.Args())
+}
+
+func TestQueryToDebugStr(t *testing.T) {
+	assert := assert.New(t)
+
+	q := queryImpl{
+		sqlCode: "SELECT prop FROM table WHERE key in ($1, $2)",
+		args:    []interface{}{"value", 32},
+	}
+	assert.Equal("SELECT prop FROM table WHERE key in ('value', '32')", queryToDebugStr(q))
+}
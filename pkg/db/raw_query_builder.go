@@ -0,0 +1,68 @@
+package db
+
+import (
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+// The raw query builder sends the sql code as is: the arguments are
+// referenced in it with the $1, $2... placeholders. A query without
+// arguments may contain several statements separated by semicolons.
+type RawQueryBuilder interface {
+	QueryBuilder
+
+	SetSql(sqlCode string) error
+	AddArg(arg interface{}) error
+	SetVerbose(verbose bool)
+}
+
+type rawQueryBuilder struct {
+	sqlCode string
+	args    []interface{}
+	verbose bool
+}
+
+func NewRawQueryBuilder() RawQueryBuilder {
+	return &rawQueryBuilder{}
+}
+
+func (b *rawQueryBuilder) SetSql(sqlCode string) error {
+	if len(sqlCode) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlScript)
+	}
+
+	b.sqlCode = sqlCode
+	return nil
+}
+
+func (b *rawQueryBuilder) AddArg(arg interface{}) error {
+	if arg == nil {
+		return errors.NewCode(errors.ErrInvalidSqlScriptArg)
+	}
+
+	b.args = append(b.args, arg)
+
+	return nil
+}
+
+func (b *rawQueryBuilder) SetVerbose(verbose bool) {
+	b.verbose = verbose
+}
+
+func (b *rawQueryBuilder) Build() (Query, error) {
+	if len(b.sqlCode) == 0 {
+		return queryImpl{}, errors.WrapCode(errors.NewCode(errors.ErrInvalidSqlScript), errors.ErrSqlTranslationFailed)
+	}
+
+	var args sqlArgs
+	if _, err := args.addAll(b.args); err != nil {
+		return queryImpl{}, errors.WrapCode(err, errors.ErrSqlTranslationFailed)
+	}
+
+	query := queryImpl{
+		sqlCode: b.sqlCode,
+		args:    args.values,
+		verbose: b.verbose,
+	}
+
+	return query, nil
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawQueryBuilder_SetSql(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewRawQueryBuilder()
+
+	err := b.SetSql("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlScript))
+
+	err = b.SetSql("SELECT 1")
+	assert.Nil(err)
+}
+
+func TestRawQueryBuilder_AddArg(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewRawQueryBuilder()
+
+	err := b.AddArg(nil)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlScriptArg))
+
+	err = b.AddArg("arg")
+	assert.Nil(err)
+}
+
+func TestRawQueryBuilder_SetVerbose(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewRawQueryBuilder()
+	b.SetSql("SELECT 1")
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.False(query.Verbose())
+
+	b.SetVerbose(true)
+	query, err = b.Build()
+	assert.Nil(err)
+	assert.True(query.Verbose())
+}
+
+func TestRawQueryBuilder_Build_NoSql(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewRawQueryBuilder()
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrInvalidSqlScript))
+}
+
+func TestRawQueryBuilder_Build(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewRawQueryBuilder()
+	b.SetSql("CREATE TABLE t (id integer); DROP TABLE t")
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.True(query.Valid())
+	assert.Equal("CREATE TABLE t (id integer); DROP TABLE t", query.ToSql())
+	assert.Nil(query.Args())
+}
+
+func TestRawQueryBuilder_Build_Args(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewRawQueryBuilder()
+	b.SetSql("DELETE FROM t WHERE id = $1 AND name = $2")
+	b.AddArg(1)
+	b.AddArg("name")
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("DELETE FROM t WHERE id = $1 AND name = $2", query.ToSql())
+	assert.Equal([]interface{}{1, "name"}, query.Args())
+}
+
+func TestRawQueryBuilder_Build_ArgWithError(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewRawQueryBuilder()
+	b.SetSql("SELECT $1")
+	b.AddArg(mockUnmarshalable{})
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(strings.Contains(cause.Error(), errDefault.Error()))
+}
@@ -0,0 +1,248 @@
+package db
+
+import (
+	"context"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+// Provides the usual operations on the rows of a table, mapped to T
+// with a StructMapper and identified by a single column.
+// https://threedots.tech/post/repository-pattern-in-go/
+type Repository[T any, ID comparable] interface {
+	// Returns the value as stored, including the generated columns.
+	Create(ctx context.Context, value T) (T, error)
+	Get(ctx context.Context, id ID) (T, error)
+	// Updates the columns, or all the writable ones if none is given.
+	Update(ctx context.Context, id ID, value T, columns ...string) (T, error)
+	Delete(ctx context.Context, id ID) error
+
+	// The filter is optional: all the rows are returned without it.
+	List(ctx context.Context, filter Filter) ([]T, error)
+	Count(ctx context.Context, filter Filter) (int, error)
+	Exists(ctx context.Context, id ID) (bool, error)
+}
+
+type RepositoryTable struct {
+	Name     string
+	IdColumn string
+	// Order of the lists, by ascending id if empty.
+	OrderBy []OrderBy
+	Verbose bool
+}
+
+type repositoryImpl[T any, ID comparable] struct {
+	qe     QueryExecutor
+	table  RepositoryTable
+	mapper StructMapper[T]
+}
+
+type countParser struct {
+	count int64
+}
+
+func NewRepository[T any, ID comparable](qe QueryExecutor, table RepositoryTable) (Repository[T, ID], error) {
+	if len(table.Name) == 0 {
+		return nil, errors.NewCode(errors.ErrInvalidSqlTable)
+	}
+
+	mapper, err := NewStructMapper[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	if !containsColumn(mapper.Columns(), table.IdColumn) {
+		return nil, errors.WrapCode(errors.Newf("id column %q is not mapped", table.IdColumn), errors.ErrInvalidSqlColumn)
+	}
+	if len(table.OrderBy) == 0 {
+		table.OrderBy = []OrderBy{{Column: table.IdColumn}}
+	}
+
+	return &repositoryImpl[T, ID]{
+		qe:     qe,
+		table:  table,
+		mapper: mapper,
+	}, nil
+}
+
+func (r *repositoryImpl[T, ID]) Create(ctx context.Context, value T) (T, error) {
+	var zero T
+
+	qb := NewInsertQueryBuilder()
+	qb.SetTable(r.table.Name)
+	if err := r.mapper.AddElements(qb, value); err != nil {
+		return zero, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+	}
+	if err := r.mapper.AddReturning(qb); err != nil {
+		return zero, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+	}
+	qb.SetVerbose(r.table.Verbose)
+
+	parser := r.mapper.NewParser()
+	if err := r.qe.ExecuteQueryAndScanReturnedRow(ctx, qb, parser); err != nil {
+		return zero, errors.WrapCode(err, errors.ErrDbEntityCreationFailure)
+	}
+
+	return parser.Value(), nil
+}
+
+func (r *repositoryImpl[T, ID]) Get(ctx context.Context, id ID) (T, error) {
+	var zero T
+
+	f, err := r.idFilter(id)
+	if err != nil {
+		return zero, err
+	}
+	qb, err := r.selectQuery(f)
+	if err != nil {
+		return zero, err
+	}
+
+	parser := r.mapper.NewParser()
+	if err := r.qe.RunQueryAndScanSingleResult(ctx, qb, parser); err != nil {
+		return zero, errors.WrapCode(err, errors.ErrDbEntityGetFailure)
+	}
+
+	return parser.Value(), nil
+}
+
+func (r *repositoryImpl[T, ID]) Update(ctx context.Context, id ID, value T, columns ...string) (T, error) {
+	var zero T
+
+	f, err := r.idFilter(id)
+	if err != nil {
+		return zero, err
+	}
+
+	qb := NewUpdateQueryBuilder()
+	qb.SetTable(r.table.Name)
+	if err := r.mapper.AddUpdates(qb, value, columns...); err != nil {
+		return zero, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+	}
+	qb.SetFilter(f)
+	if err := r.mapper.AddReturning(qb); err != nil {
+		return zero, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+	}
+	qb.SetVerbose(r.table.Verbose)
+
+	parser := r.mapper.NewParser()
+	if err := r.qe.ExecuteQueryAndScanReturnedRow(ctx, qb, parser); err != nil {
+		return zero, errors.WrapCode(err, errors.ErrDbEntityUpdateFailure)
+	}
+
+	return parser.Value(), nil
+}
+
+func (r *repositoryImpl[T, ID]) Delete(ctx context.Context, id ID) error {
+	f, err := r.idFilter(id)
+	if err != nil {
+		return err
+	}
+
+	qb := NewDeleteQueryBuilder()
+	qb.SetTable(r.table.Name)
+	qb.SetFilter(f)
+	qb.SetVerbose(r.table.Verbose)
+
+	if err := r.qe.ExecuteQueryAffectingSingleRow(ctx, qb); err != nil {
+		return errors.WrapCode(err, errors.ErrDbEntityDeletionFailure)
+	}
+
+	return nil
+}
+
+func (r *repositoryImpl[T, ID]) List(ctx context.Context, filter Filter) ([]T, error) {
+	qb, err := r.selectQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, orderBy := range r.table.OrderBy {
+		if err := qb.AddOrderBy(orderBy); err != nil {
+			return nil, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+		}
+	}
+
+	parser := r.mapper.NewParser()
+	if err := r.qe.RunQueryAndScanAllResults(ctx, qb, parser); err != nil {
+		return nil, errors.WrapCode(err, errors.ErrDbEntityGetFailure)
+	}
+
+	values := parser.Values()
+	if values == nil {
+		values = []T{}
+	}
+
+	return values, nil
+}
+
+func (r *repositoryImpl[T, ID]) Count(ctx context.Context, filter Filter) (int, error) {
+	qb := NewSelectQueryBuilder()
+	qb.SetTable(r.table.Name)
+	qb.AddAggregate(Aggregate{Function: Count, Column: allColumns})
+	if filter != nil {
+		if err := qb.SetFilter(filter); err != nil {
+			return 0, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+		}
+	}
+	qb.SetVerbose(r.table.Verbose)
+
+	parser := &countParser{}
+	if err := r.qe.RunQueryAndScanSingleResult(ctx, qb, parser); err != nil {
+		return 0, errors.WrapCode(err, errors.ErrDbEntityGetFailure)
+	}
+
+	return int(parser.count), nil
+}
+
+func (r *repositoryImpl[T, ID]) Exists(ctx context.Context, id ID) (bool, error) {
+	f, err := r.idFilter(id)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := r.Count(ctx, f)
+	return count > 0, err
+}
+
+func (r *repositoryImpl[T, ID]) idFilter(id ID) (Filter, error) {
+	fb := NewInFilterBuilder()
+	fb.SetKey(r.table.IdColumn)
+	fb.AddValue(id)
+
+	f, err := fb.Build()
+	if err != nil {
+		return nil, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+	}
+
+	return f, nil
+}
+
+func (r *repositoryImpl[T, ID]) selectQuery(filter Filter) (SelectQueryBuilder, error) {
+	qb := NewSelectQueryBuilder()
+	qb.SetTable(r.table.Name)
+	if err := r.mapper.AddProps(qb); err != nil {
+		return nil, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+	}
+	if filter != nil {
+		if err := qb.SetFilter(filter); err != nil {
+			return nil, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+		}
+	}
+	qb.SetVerbose(r.table.Verbose)
+
+	return qb, nil
+}
+
+func (p *countParser) ScanRow(row Scannable) error {
+	return row.Scan(&p.count)
+}
+
+func containsColumn(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
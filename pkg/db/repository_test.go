@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPlayersTable = RepositoryTable{
+	Name:     "players",
+	IdColumn: "id",
+	OrderBy:  []OrderBy{{Column: "name"}},
+}
+
+func TestNewRepository_Invalid(t *testing.T) {
+	assert := assert.New(t)
+
+	qe := NewQueryExecutor(&mockDb{})
+
+	_, err := NewRepository[structMapperTestPlayer, uuid.UUID](qe, RepositoryTable{IdColumn: "id"})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlTable))
+	_, err = NewRepository[structMapperTestPlayer, uuid.UUID](qe, RepositoryTable{Name: "players", IdColumn: "unknown"})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlColumn))
+	_, err = NewRepository[int, uuid.UUID](qe, testPlayersTable)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlStructMapping))
+}
+
+func TestNewRepository_DefaultOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	table := RepositoryTable{Name: "players", IdColumn: "id"}
+	repo, err := NewRepository[structMapperTestPlayer, uuid.UUID](NewQueryExecutor(&mockDb{}), table)
+	assert.Nil(err)
+
+	impl := repo.(*repositoryImpl[structMapperTestPlayer, uuid.UUID])
+	assert.Equal([]OrderBy{{Column: "id"}}, impl.table.OrderBy)
+}
+
+func TestRepository_QueryErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	mdb := &mockDb{rows: &mockRows{err: errDefault}}
+	repo, err := NewRepository[structMapperTestPlayer, uuid.UUID](NewQueryExecutor(mdb), testPlayersTable)
+	assert.Nil(err)
+
+	_, err = repo.List(context.Background(), nil)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbEntityGetFailure))
+	_, err = repo.Count(context.Background(), nil)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbEntityGetFailure))
+	_, err = repo.Exists(context.Background(), uuid.New())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbEntityGetFailure))
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const defaultRetryBackoff = 50 * time.Millisecond
+
+type idempotentQueryKeyType string
+
+const idempotentQueryKey idempotentQueryKeyType = "idempotent-query"
+
+// Marks the queries of the context as safe to run several times: they
+// are then retried when they fail with a transient error. Queries run
+// in a transaction are never retried as the whole transaction fails.
+func WithIdempotentQuery(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentQueryKey, true)
+}
+
+func IdempotentQueryFromContext(ctx context.Context) bool {
+	idempotent, _ := ctx.Value(idempotentQueryKey).(bool)
+	return idempotent
+}
+
+type retryPolicy struct {
+	attempts uint
+	backoff  time.Duration
+}
+
+func newRetryPolicy(conf Config) retryPolicy {
+	p := retryPolicy{
+		attempts: conf.DbRetryAttempts,
+		backoff:  conf.DbRetryBackoff,
+	}
+
+	if p.backoff == 0 {
+		p.backoff = defaultRetryBackoff
+	}
+
+	return p
+}
+
+// Runs the function until it succeeds, fails with an error which is
+// not transient or the attempts are exhausted. The delay between two
+// attempts doubles each time.
+func (p retryPolicy) run(ctx context.Context, work func() error) {
+	backoff := p.backoff
+
+	for attempt := uint(0); ; attempt++ {
+		err := work()
+		if attempt >= p.attempts || !IdempotentQueryFromContext(ctx) || !isTransientError(ctx, err) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
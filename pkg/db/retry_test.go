@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+var errSerializationFailure = errors.WrapCode(pgx.PgError{Code: serializationFailure}, errors.ErrDbRequestFailed)
+
+func TestRetryPolicy_New(t *testing.T) {
+	assert := assert.New(t)
+
+	p := newRetryPolicy(Config{DbRetryAttempts: 2})
+	assert.Equal(uint(2), p.attempts)
+	assert.Equal(defaultRetryBackoff, p.backoff)
+}
+
+func TestRetryPolicy_Run(t *testing.T) {
+	assert := assert.New(t)
+
+	p := retryPolicy{attempts: 2, backoff: time.Millisecond}
+	ctx := WithIdempotentQuery(context.Background())
+
+	calls := 0
+	p.run(ctx, func() error {
+		calls++
+		return errSerializationFailure
+	})
+	assert.Equal(3, calls)
+
+	calls = 0
+	p.run(ctx, func() error {
+		calls++
+		if calls == 1 {
+			return errors.WrapCode(io.EOF, errors.ErrDbRequestFailed)
+		}
+		return nil
+	})
+	assert.Equal(2, calls)
+}
+
+func TestRetryPolicy_Run_NotIdempotent(t *testing.T) {
+	assert := assert.New(t)
+
+	p := retryPolicy{attempts: 2, backoff: time.Millisecond}
+
+	calls := 0
+	p.run(context.Background(), func() error {
+		calls++
+		return errSerializationFailure
+	})
+	assert.Equal(1, calls)
+}
+
+func TestRetryPolicy_Run_NotTransient(t *testing.T) {
+	assert := assert.New(t)
+
+	p := retryPolicy{attempts: 2, backoff: time.Millisecond}
+
+	calls := 0
+	p.run(WithIdempotentQuery(context.Background()), func() error {
+		calls++
+		return errDefault
+	})
+	assert.Equal(1, calls)
+}
+
+func TestRetryPolicy_Run_CancelledContext(t *testing.T) {
+	assert := assert.New(t)
+
+	p := retryPolicy{attempts: 2, backoff: time.Hour}
+	ctx, cancel := context.WithCancel(WithIdempotentQuery(context.Background()))
+
+	calls := 0
+	p.run(ctx, func() error {
+		calls++
+		cancel()
+		return errSerializationFailure
+	})
+	assert.Equal(1, calls)
+}
+
+func TestIdempotentQueryFromContext(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(IdempotentQueryFromContext(context.Background()))
+	assert.True(IdempotentQueryFromContext(WithIdempotentQuery(context.Background())))
+}
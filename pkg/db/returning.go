@@ -0,0 +1,39 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+// https://www.postgresql.org/docs/current/dml-returning.html
+type returningClause struct {
+	keys    map[string]bool
+	columns []string
+}
+
+func (r *returningClause) add(column string) error {
+	if len(column) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlColumn)
+	}
+
+	if r.keys == nil {
+		r.keys = make(map[string]bool)
+	}
+	if _, ok := r.keys[column]; ok {
+		return errors.NewCode(errors.ErrDuplicatedSqlColumn)
+	}
+
+	r.keys[column] = true
+	r.columns = append(r.columns, column)
+	return nil
+}
+
+func (r *returningClause) toSql() string {
+	if len(r.columns) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" RETURNING %s", strings.Join(r.columns, ", "))
+}
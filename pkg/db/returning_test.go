@@ -0,0 +1,34 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReturningClause_Add(t *testing.T) {
+	assert := assert.New(t)
+
+	var r returningClause
+
+	err := r.add("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlColumn))
+
+	err = r.add("id")
+	assert.Nil(err)
+
+	err = r.add("id")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDuplicatedSqlColumn))
+}
+
+func TestReturningClause_ToSql(t *testing.T) {
+	assert := assert.New(t)
+
+	var r returningClause
+	assert.Equal("", r.toSql())
+
+	r.add("id")
+	r.add("created_at")
+	assert.Equal(" RETURNING id, created_at", r.toSql())
+}
@@ -0,0 +1,273 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
+)
+
+const defaultReplicaEjectionTime = 30 * time.Second
+
+type readYourWritesKeyType string
+
+const readYourWritesKey readYourWritesKeyType = "read-your-writes"
+
+// Sends the queries of the context to the primary: this is useful to
+// read data which was just written and might not be replicated yet.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readYourWritesKey, true)
+}
+
+func ReadYourWritesFromContext(ctx context.Context) bool {
+	readYourWrites, _ := ctx.Value(readYourWritesKey).(bool)
+	return readYourWrites
+}
+
+// The lock is held while a query starts on the replica so that it is
+// not disconnected in the meantime.
+type replicaDb struct {
+	db           Database
+	connected    atomic.Bool
+	ejectedUntil atomic.Int64
+	lock         sync.RWMutex
+}
+
+// The routing database sends the Query calls to the replicas in a
+// round robin fashion and everything else to the primary: as the
+// transactions are started on the primary the queries they run also
+// reach it. The queries writing rows, such as an upsert, are sent with
+// WithReadYourWrites by the query executor. A replica failing to answer is ejected for a while and
+// the query is run again on the primary. The replicas which can't be
+// connected are skipped and reconnected in the background.
+type routingDb struct {
+	primary       Database
+	replicas      []*replicaDb
+	next          atomic.Uint64
+	ejectionTime  time.Duration
+	currentTimeFn func() time.Time
+
+	lock          sync.Mutex
+	stop          chan struct{}
+	reconnections sync.WaitGroup
+}
+
+func NewRoutingDatabase(conf Config) Database {
+	replicas := make([]Database, 0, len(conf.DbReplicas))
+	for _, replica := range conf.DbReplicas {
+		replicas = append(replicas, NewPostgresDatabase(conf.replica(replica)))
+	}
+
+	primary := conf
+	primary.DbReplicas = nil
+
+	return newRoutingDatabase(NewPostgresDatabase(primary), replicas, conf.DbReplicaEjectionTime)
+}
+
+func newRoutingDatabase(primary Database, replicas []Database, ejectionTime time.Duration) *routingDb {
+	if ejectionTime == 0 {
+		ejectionTime = defaultReplicaEjectionTime
+	}
+
+	db := routingDb{
+		primary:       primary,
+		ejectionTime:  ejectionTime,
+		currentTimeFn: time.Now,
+	}
+
+	for _, replica := range replicas {
+		db.replicas = append(db.replicas, &replicaDb{db: replica})
+	}
+
+	return &db
+}
+
+// Only the primary is required to be reachable: the replicas which
+// can't be connected are retried in the background.
+func (db *routingDb) Connect(ctx context.Context) error {
+	if err := db.primary.Connect(ctx); err != nil {
+		return err
+	}
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.stop == nil {
+		db.stop = make(chan struct{})
+	}
+
+	for id, replica := range db.replicas {
+		if replica.connected.Load() {
+			continue
+		}
+
+		if err := replica.db.Connect(ctx); err != nil {
+			warnLog(ctx, "failed to connect to replica %d (err: %v)", id, err)
+			db.reconnections.Add(1)
+			go db.reconnectReplica(db.stop, id, replica)
+			continue
+		}
+
+		replica.connected.Store(true)
+	}
+
+	return nil
+}
+
+// The reconnections are stopped first as they might be connecting a
+// replica.
+func (db *routingDb) Disconnect(ctx context.Context) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.stop != nil {
+		close(db.stop)
+		db.reconnections.Wait()
+		db.stop = nil
+	}
+
+	for _, replica := range db.replicas {
+		replica.lock.Lock()
+		replica.connected.Store(false)
+		if err := replica.db.Disconnect(ctx); err != nil {
+			warnLog(ctx, "failed to disconnect replica (err: %v)", err)
+		}
+		replica.lock.Unlock()
+	}
+
+	return db.primary.Disconnect(ctx)
+}
+
+func (db *routingDb) Query(ctx context.Context, query Query) Rows {
+	if ReadYourWritesFromContext(ctx) {
+		return db.primary.Query(ctx, query)
+	}
+
+	replica := db.pickReplica()
+	if replica == nil {
+		return db.primary.Query(ctx, query)
+	}
+
+	rows, ok := replica.query(ctx, query)
+	if !ok {
+		return db.primary.Query(ctx, query)
+	}
+	if isReplicaFailure(ctx, rows.Err()) {
+		warnLog(ctx, "ejecting replica after failure (err: %v)", rows.Err())
+		rows.Close()
+		db.eject(replica)
+		return db.primary.Query(ctx, query)
+	}
+
+	return rows
+}
+
+func (db *routingDb) Execute(ctx context.Context, query Query) Result {
+	return db.primary.Execute(ctx, query)
+}
+
+func (db *routingDb) CopyFrom(ctx context.Context, copy CopyFrom) Result {
+	return db.primary.CopyFrom(ctx, copy)
+}
+
+func (db *routingDb) Begin(ctx context.Context) (Transaction, error) {
+	return db.primary.Begin(ctx)
+}
+
+// Replicas can't listen to notifications as they are read only.
+// https://www.postgresql.org/docs/current/hot-standby.html#HOT-STANDBY-USERS
+func (db *routingDb) Subscribe(ctx context.Context, channel string) (Subscription, error) {
+	return db.primary.Subscribe(ctx, channel)
+}
+
+// Sums the statistics of the primary and of the replicas.
+func (db *routingDb) StatementCacheStats() StatementCacheStats {
+	var stats StatementCacheStats
+
+	dbs := []Database{db.primary}
+	for _, replica := range db.replicas {
+		dbs = append(dbs, replica.db)
+	}
+	for _, database := range dbs {
+		if reporter, ok := database.(StatementCacheReporter); ok {
+			s := reporter.StatementCacheStats()
+			stats.Hits += s.Hits
+			stats.Misses += s.Misses
+			stats.Invalidations += s.Invalidations
+			stats.Size += s.Size
+		}
+	}
+
+	return stats
+}
+
+func (db *routingDb) pickReplica() *replicaDb {
+	count := uint64(len(db.replicas))
+	now := db.currentTimeFn().UnixNano()
+
+	for attempt := uint64(0); attempt < count; attempt++ {
+		replica := db.replicas[db.next.Add(1)%count]
+		if !replica.connected.Load() || replica.ejectedUntil.Load() > now {
+			continue
+		}
+
+		return replica
+	}
+
+	return nil
+}
+
+// The replica might have been disconnected since it was picked, in
+// which case the query is not run.
+func (replica *replicaDb) query(ctx context.Context, query Query) (Rows, bool) {
+	replica.lock.RLock()
+	defer replica.lock.RUnlock()
+
+	if !replica.connected.Load() {
+		return nil, false
+	}
+
+	return replica.db.Query(ctx, query), true
+}
+
+// Runs in the background so that the queries do not wait for the
+// connection: the replica is skipped until then.
+func (db *routingDb) reconnectReplica(stop chan struct{}, id int, replica *replicaDb) {
+	defer db.reconnections.Done()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(db.ejectionTime):
+		}
+
+		err := replica.db.Connect(context.Background())
+		if err == nil {
+			logger.Infof("reconnected to replica %d", id)
+			replica.connected.Store(true)
+			return
+		}
+
+		logger.Warnf("failed to reconnect to replica %d, retrying in %v (err: %v)", id, db.ejectionTime, err)
+	}
+}
+
+func (db *routingDb) eject(replica *replicaDb) {
+	until := db.currentTimeFn().Add(db.ejectionTime)
+	replica.ejectedUntil.Store(until.UnixNano())
+}
+
+// Errors returned by the server, such as a syntax error, do not make
+// the replica unhealthy. Neither do the cancellations coming from the
+// caller or the timeouts as the query might just be slow.
+func isReplicaFailure(ctx context.Context, err error) bool {
+	if ctx.Err() == nil && errors.IsErrorWithCode(err, errors.ErrDbConnectionInvalid) {
+		return true
+	}
+
+	return isConnectionError(ctx, err)
+}
@@ -0,0 +1,341 @@
+package db
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+var defaultQuery = queryImpl{sqlCode: "SELECT name FROM t"}
+
+func newTestRoutingDatabase(primary *mockDb, replicas ...*mockDb) *routingDb {
+	var dbs []Database
+	for _, replica := range replicas {
+		dbs = append(dbs, replica)
+	}
+
+	return newRoutingDatabase(primary, dbs, time.Minute)
+}
+
+func TestRoutingDatabase_New(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := testConfig
+	conf.DbReplicas = []ReplicaConfig{
+		{DbHost: "replica1", DbPort: 1},
+		{DbHost: "replica2", DbPort: 2},
+	}
+
+	db, ok := NewRoutingDatabase(conf).(*routingDb)
+	assert.True(ok)
+	assert.Equal(2, len(db.replicas))
+	assert.Equal(defaultReplicaEjectionTime, db.ejectionTime)
+
+	primary := db.primary.(*postgresDb)
+	assert.Equal("host", primary.config.DbHost)
+	assert.Nil(primary.config.DbReplicas)
+	replica := db.replicas[1].db.(*postgresDb)
+	assert.Equal("replica2", replica.config.DbHost)
+	assert.Equal(uint16(2), replica.config.DbPort)
+	assert.Equal("database", replica.config.DbName)
+}
+
+func TestRoutingDatabase_Connect(t *testing.T) {
+	assert := assert.New(t)
+
+	primary := &mockDb{}
+	replica := &mockDb{connectErr: errDefault}
+	db := newTestRoutingDatabase(primary, replica)
+
+	assert.Nil(db.Connect(context.Background()))
+	defer db.Disconnect(context.Background())
+	assert.Equal(1, primary.connectCalls)
+	assert.Equal(1, replica.connectCalls)
+	assert.False(db.replicas[0].connected.Load())
+
+	primary.connectErr = errDefault
+	assert.Equal(errDefault, db.Connect(context.Background()))
+}
+
+func TestRoutingDatabase_Disconnect(t *testing.T) {
+	assert := assert.New(t)
+
+	primary := &mockDb{}
+	replica := &mockDb{disconnectErr: errDefault}
+	db := newTestRoutingDatabase(primary, replica)
+	assert.Nil(db.Connect(context.Background()))
+
+	assert.Nil(db.Disconnect(context.Background()))
+	assert.False(db.replicas[0].connected.Load())
+
+	primary.disconnectErr = errDefault
+	assert.Equal(errDefault, db.Disconnect(context.Background()))
+}
+
+func TestRoutingDatabase_Query_RoundRobin(t *testing.T) {
+	assert := assert.New(t)
+
+	primary := &mockDb{}
+	replica1 := &mockDb{rows: &mockRows{}}
+	replica2 := &mockDb{rows: &mockRows{}}
+	db := newTestRoutingDatabase(primary, replica1, replica2)
+	assert.Nil(db.Connect(context.Background()))
+
+	for id := 0; id < 4; id++ {
+		db.Query(context.Background(), defaultQuery)
+	}
+
+	assert.Equal(0, primary.queryCalls)
+	assert.Equal(2, replica1.queryCalls)
+	assert.Equal(2, replica2.queryCalls)
+}
+
+func TestRoutingDatabase_Query_NoReplicas(t *testing.T) {
+	assert := assert.New(t)
+
+	rows := &mockRows{}
+	primary := &mockDb{rows: rows}
+	db := newTestRoutingDatabase(primary)
+
+	actual := db.Query(context.Background(), defaultQuery)
+	assert.Equal(rows, actual)
+	assert.Equal(1, primary.queryCalls)
+}
+
+func TestRoutingDatabase_Query_ReadYourWrites(t *testing.T) {
+	assert := assert.New(t)
+
+	primary := &mockDb{rows: &mockRows{}}
+	replica := &mockDb{rows: &mockRows{}}
+	db := newTestRoutingDatabase(primary, replica)
+	assert.Nil(db.Connect(context.Background()))
+
+	db.Query(WithReadYourWrites(context.Background()), defaultQuery)
+	assert.Equal(1, primary.queryCalls)
+	assert.Equal(0, replica.queryCalls)
+}
+
+func TestRoutingDatabase_Query_EjectsFailingReplica(t *testing.T) {
+	assert := assert.New(t)
+
+	primaryRows := &mockRows{}
+	primary := &mockDb{rows: primaryRows}
+	replica := &mockDb{
+		rows: &mockRows{err: errors.WrapCode(io.EOF, errors.ErrDbRequestFailed)},
+	}
+	db := newTestRoutingDatabase(primary, replica)
+	now := time.Now()
+	db.currentTimeFn = func() time.Time { return now }
+	assert.Nil(db.Connect(context.Background()))
+
+	actual := db.Query(context.Background(), defaultQuery)
+	assert.Equal(primaryRows, actual)
+	assert.Equal(1, replica.queryCalls)
+
+	db.Query(context.Background(), defaultQuery)
+	assert.Equal(1, replica.queryCalls)
+	assert.Equal(2, primary.queryCalls)
+
+	now = now.Add(time.Minute + time.Second)
+	replica.rows = &mockRows{}
+	db.Query(context.Background(), defaultQuery)
+	assert.Equal(2, replica.queryCalls)
+	assert.Equal(2, primary.queryCalls)
+}
+
+func TestRoutingDatabase_Query_ReconnectsReplicaInBackground(t *testing.T) {
+	assert := assert.New(t)
+
+	primary := &mockDb{rows: &mockRows{}}
+	replica := &mockFlakyDb{mockDb: mockDb{rows: &mockRows{}}, failures: 2}
+	db := newRoutingDatabase(primary, []Database{replica}, 10*time.Millisecond)
+	assert.Nil(db.Connect(context.Background()))
+	defer db.Disconnect(context.Background())
+
+	db.Query(context.Background(), defaultQuery)
+	assert.Equal(1, primary.queryCalls)
+	assert.Equal(0, replica.queryCalls)
+
+	assert.Eventually(func() bool {
+		return db.replicas[0].connected.Load()
+	}, time.Second, time.Millisecond)
+	assert.Equal(3, replica.connects())
+
+	db.Query(context.Background(), defaultQuery)
+	assert.Equal(1, primary.queryCalls)
+	assert.Equal(1, replica.queryCalls)
+}
+
+func TestRoutingDatabase_Disconnect_StopsReconnections(t *testing.T) {
+	assert := assert.New(t)
+
+	primary := &mockDb{}
+	replica := &mockFlakyDb{failures: -1}
+	db := newRoutingDatabase(primary, []Database{replica}, time.Millisecond)
+	assert.Nil(db.Connect(context.Background()))
+
+	assert.Eventually(func() bool {
+		return replica.connects() > 2
+	}, time.Second, time.Millisecond)
+	assert.Nil(db.Disconnect(context.Background()))
+
+	connects := replica.connects()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(connects, replica.connects())
+	assert.False(db.replicas[0].connected.Load())
+}
+
+func TestRoutingDatabase_Query_SkipsDisconnectedReplica(t *testing.T) {
+	assert := assert.New(t)
+
+	primary := &mockDb{rows: &mockRows{}}
+	replica := &mockDb{rows: &mockRows{}}
+	db := newTestRoutingDatabase(primary, replica)
+	assert.Nil(db.Connect(context.Background()))
+	assert.Nil(db.Disconnect(context.Background()))
+
+	db.Query(context.Background(), defaultQuery)
+	assert.Equal(1, primary.queryCalls)
+	assert.Equal(0, replica.queryCalls)
+
+	_, ok := db.replicas[0].query(context.Background(), defaultQuery)
+	assert.False(ok)
+	assert.Equal(0, replica.queryCalls)
+}
+
+func TestRoutingDatabase_Query_KeepsReplicaOnServerError(t *testing.T) {
+	assert := assert.New(t)
+
+	rows := &mockRows{err: errors.WrapCode(pgx.PgError{Code: "42601"}, errors.ErrDbRequestFailed)}
+	primary := &mockDb{}
+	replica := &mockDb{rows: rows}
+	db := newTestRoutingDatabase(primary, replica)
+	assert.Nil(db.Connect(context.Background()))
+
+	actual := db.Query(context.Background(), defaultQuery)
+	assert.Equal(rows, actual)
+
+	db.Query(context.Background(), defaultQuery)
+	assert.Equal(2, replica.queryCalls)
+	assert.Equal(0, primary.queryCalls)
+}
+
+func TestRoutingDatabase_Query_KeepsReplicaOnCancellation(t *testing.T) {
+	assert := assert.New(t)
+
+	rows := &mockRows{err: errors.WrapCode(context.Canceled, errors.ErrDbRequestFailed)}
+	primary := &mockDb{}
+	replica := &mockDb{rows: rows}
+	db := newTestRoutingDatabase(primary, replica)
+	assert.Nil(db.Connect(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	actual := db.Query(ctx, defaultQuery)
+	assert.Equal(rows, actual)
+	assert.Equal(0, primary.queryCalls)
+}
+
+func TestRoutingDatabase_WritesGoToPrimary(t *testing.T) {
+	assert := assert.New(t)
+
+	primary := &mockDb{result: &mockResult{}, tx: &mockTransaction{}}
+	replica := &mockDb{}
+	db := newTestRoutingDatabase(primary, replica)
+	assert.Nil(db.Connect(context.Background()))
+
+	db.Execute(context.Background(), defaultQuery)
+	db.CopyFrom(context.Background(), copyFromImpl{})
+	_, err := db.Begin(context.Background())
+	assert.Nil(err)
+	_, err = db.Subscribe(context.Background(), "channel")
+	assert.Nil(err)
+
+	assert.Equal(1, primary.executeCalls)
+	assert.Equal(1, primary.copyCalls)
+	assert.Equal(1, primary.beginCalls)
+	assert.Equal(1, primary.subscribeCalls)
+	assert.Equal(0, replica.executeCalls)
+	assert.Equal(0, replica.copyCalls)
+	assert.Equal(0, replica.beginCalls)
+	assert.Equal(0, replica.subscribeCalls)
+}
+
+func TestRoutingDatabase_ReturningWritesGoToPrimary(t *testing.T) {
+	assert := assert.New(t)
+
+	primary := &mockDb{
+		rows: &mockRows{
+			getSingleValueScannable: &mockUpsertStatusScannable{inserted: true},
+		},
+	}
+	replica := &mockDb{}
+	db := newTestRoutingDatabase(primary, replica)
+	assert.Nil(db.Connect(context.Background()))
+	qe := NewQueryExecutor(db)
+
+	upsert := NewInsertQueryBuilder()
+	upsert.SetTable("players")
+	upsert.AddElement("name", "player")
+	upsert.SetOnConflictColumns("name")
+	upsert.AddOnConflictUpdateFromExcluded("name")
+	upsert.AddReturningUpsertStatus()
+	status, err := qe.ExecuteUpsert(context.Background(), upsert)
+	assert.Nil(err)
+	assert.Equal(UpsertInserted, status)
+
+	insert := NewInsertQueryBuilder()
+	insert.SetTable("players")
+	insert.AddElement("name", "player")
+	insert.AddReturning("id")
+	err = qe.ExecuteQueryAndScanReturnedRow(context.Background(), insert, &upsertStatusParser{})
+	assert.Nil(err)
+	err = qe.ExecuteQueryAndScanReturnedRows(context.Background(), insert, &upsertStatusParser{})
+	assert.Nil(err)
+
+	assert.Equal(3, primary.queryCalls)
+	assert.Equal(0, replica.queryCalls)
+}
+
+// Fails the given number of connections, or all of them if negative.
+// The connections can be attempted in the background.
+type mockFlakyDb struct {
+	mockDb
+
+	lock         sync.Mutex
+	failures     int
+	connectCalls int
+}
+
+func (m *mockFlakyDb) Connect(ctx context.Context) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.connectCalls++
+	if m.failures < 0 || m.connectCalls <= m.failures {
+		return errDefault
+	}
+
+	return nil
+}
+
+func (m *mockFlakyDb) connects() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.connectCalls
+}
+
+func TestReadYourWritesFromContext(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(ReadYourWritesFromContext(context.Background()))
+	assert.True(ReadYourWritesFromContext(WithReadYourWrites(context.Background())))
+}
@@ -12,6 +12,13 @@ type Rows interface {
 
 	GetSingleValue(parser RowParser) error
 	GetAll(parser RowParser) error
+
+	// Streams the rows one at a time instead of parsing them all: each
+	// successful call to Next is followed by a call to Scan. The rows
+	// are closed when Next returns false, Err then tells whether all of
+	// them were read.
+	Next() bool
+	Scan(parser RowParser) error
 }
 
 type Scannable interface {
@@ -22,31 +29,49 @@ type RowParser interface {
 	ScanRow(row Scannable) error
 }
 
-type sqlRows interface {
+// Implemented by the rows knowing the names of their columns: parsers
+// can use it to check that the query returns what they expect.
+type columnsDescriber interface {
+	Columns() []string
+}
+
+// The rows returned by a Driver. They can also describe their columns
+// like the pgx ones do.
+type DriverRows interface {
 	Next() bool
 	Scan(dest ...interface{}) error
 	Close()
+	// Reports the error which stopped the iteration if any.
+	Err() error
 }
 
 type rowsImpl struct {
-	rows sqlRows
-	next bool
-	err  error
+	rows    DriverRows
+	next    bool
+	started bool
+	err     error
 }
 
-func newRows(rows sqlRows, err error) Rows {
+func newRows(rows DriverRows, err error) Rows {
 	r := rowsImpl{
 		rows: rows,
 		err:  err,
 	}
 
 	if !common.IsInterfaceNil(r.rows) && r.err == nil {
-		r.next = r.rows.Next()
+		r.advance()
 	}
 
 	return &r
 }
 
+func (r *rowsImpl) advance() {
+	r.next = r.rows.Next()
+	if !r.next {
+		r.err = r.rows.Err()
+	}
+}
+
 func (r *rowsImpl) Err() error {
 	return r.err
 }
@@ -75,12 +100,12 @@ func (r *rowsImpl) GetSingleValue(parser RowParser) error {
 		return errors.WrapCode(err, errors.ErrSqlRowParsingFailed)
 	}
 
-	r.next = r.rows.Next()
+	r.advance()
 	if r.next {
 		return errors.NewCode(errors.ErrMultiValuedDbElement)
 	}
 
-	return nil
+	return r.err
 }
 
 func (r *rowsImpl) GetAll(parser RowParser) error {
@@ -95,7 +120,38 @@ func (r *rowsImpl) GetAll(parser RowParser) error {
 			return errors.WrapCode(err, errors.ErrSqlRowParsingFailed)
 		}
 
-		r.next = r.rows.Next()
+		r.advance()
+	}
+
+	return r.err
+}
+
+func (r *rowsImpl) Next() bool {
+	if r.err != nil || common.IsInterfaceNil(r.rows) {
+		r.Close()
+		return false
+	}
+
+	// The first row is already read when the rows are created.
+	if r.started {
+		r.advance()
+	}
+	r.started = true
+
+	if !r.next {
+		r.Close()
+	}
+
+	return r.next
+}
+
+func (r *rowsImpl) Scan(parser RowParser) error {
+	if !r.started || !r.next {
+		return errors.NewCode(errors.ErrNoRowsReturnedForSqlQuery)
+	}
+
+	if err := parser.ScanRow(r.rows); err != nil {
+		return errors.WrapCode(err, errors.ErrSqlRowParsingFailed)
 	}
 
 	return nil
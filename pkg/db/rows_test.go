@@ -187,10 +187,113 @@ func TestRows_GetAll_ScannerError(t *testing.T) {
 	assert.Equal(1, mp.parseCalled)
 }
 
+func TestRows_ErrorAfterIteration(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockSqlRows{err: errDefault}
+	r := newRows(m, nil)
+	assert.Equal(errDefault, r.Err())
+
+	mp := &mockParser{}
+	m = &mockSqlRows{numberOfRows: 2, err: errDefault}
+	r = newRows(m, nil)
+	assert.Nil(r.Err())
+	err := r.GetAll(mp)
+	assert.Equal(errDefault, err)
+	assert.Equal(2, mp.parseCalled)
+
+	mp = &mockParser{}
+	m = &mockSqlRows{numberOfRows: 1, err: errDefault}
+	r = newRows(m, nil)
+	err = r.GetSingleValue(mp)
+	assert.Equal(errDefault, err)
+	assert.Equal(1, mp.parseCalled)
+}
+
+func TestRows_Next(t *testing.T) {
+	assert := assert.New(t)
+
+	mp := &mockParser{}
+	m := &mockSqlRows{numberOfRows: 3}
+	r := newRows(m, nil)
+
+	rowsCount := 0
+	for r.Next() {
+		assert.Nil(r.Scan(mp))
+		rowsCount++
+	}
+
+	assert.Equal(3, rowsCount)
+	assert.Equal(3, mp.parseCalled)
+	assert.Nil(r.Err())
+	assert.Equal(int32(1), m.closeCalls.Load())
+	assert.False(r.Next())
+}
+
+func TestRows_Next_InvalidPreconditions(t *testing.T) {
+	assert := assert.New(t)
+
+	r := newRows(nil, errDefault)
+	assert.False(r.Next())
+	assert.Equal(errDefault, r.Err())
+
+	r = newRows(nil, nil)
+	assert.False(r.Next())
+	assert.Nil(r.Err())
+}
+
+func TestRows_Next_ErrorAfterRows(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockSqlRows{numberOfRows: 2, err: errDefault}
+	r := newRows(m, nil)
+
+	rowsCount := 0
+	for r.Next() {
+		rowsCount++
+	}
+
+	assert.Equal(2, rowsCount)
+	assert.Equal(errDefault, r.Err())
+	assert.Equal(int32(1), m.closeCalls.Load())
+}
+
+func TestRows_Scan_WithoutRow(t *testing.T) {
+	assert := assert.New(t)
+
+	mp := &mockParser{}
+	m := &mockSqlRows{numberOfRows: 1}
+	r := newRows(m, nil)
+
+	err := r.Scan(mp)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrNoRowsReturnedForSqlQuery))
+
+	assert.True(r.Next())
+	assert.False(r.Next())
+	err = r.Scan(mp)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrNoRowsReturnedForSqlQuery))
+	assert.Equal(0, mp.parseCalled)
+}
+
+func TestRows_Scan_ParserError(t *testing.T) {
+	assert := assert.New(t)
+
+	mp := &mockParser{parseErr: errDefault}
+	m := &mockSqlRows{numberOfRows: 1}
+	r := newRows(m, nil)
+
+	assert.True(r.Next())
+	err := r.Scan(mp)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlRowParsingFailed))
+	cause := errors.Unwrap(err)
+	assert.Equal(errDefault, cause)
+}
+
 type mockSqlRows struct {
 	count        int
 	numberOfRows int
 	scanError    error
+	err          error
 	closeCalls   atomic.Int32
 }
 
@@ -208,6 +311,10 @@ func (m *mockSqlRows) Close() {
 	m.closeCalls.Add(1)
 }
 
+func (m *mockSqlRows) Err() error {
+	return m.err
+}
+
 type mockParser struct {
 	parseCalled int
 	parseErr    error
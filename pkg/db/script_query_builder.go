@@ -2,7 +2,6 @@ package db
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/KnoblauchPilze/go-game/pkg/errors"
 )
@@ -59,12 +58,14 @@ func (b *scriptQueryBuilder) Build() (Query, error) {
 		return queryImpl{}, errors.WrapCode(errors.NewCode(errors.ErrInvalidSqlScript), errors.ErrSqlTranslationFailed)
 	}
 
-	argsAsStr, err := b.argsToStr()
+	var args sqlArgs
+	argsAsStr, err := args.addAll(b.args)
 	if err != nil {
 		return queryImpl{}, errors.WrapCode(err, errors.ErrSqlTranslationFailed)
 	}
 
 	query := queryImpl{
+		args:    args.values,
 		verbose: b.verbose,
 	}
 
@@ -76,17 +77,3 @@ func (b *scriptQueryBuilder) Build() (Query, error) {
 
 	return query, nil
 }
-
-func (b *scriptQueryBuilder) argsToStr() (string, error) {
-	args := make([]string, 0, len(b.args))
-	for _, arg := range b.args {
-		argStr, err := argToStr(arg)
-		if err != nil {
-			return "", err
-		}
-
-		args = append(args, fmt.Sprintf("'%s'", argStr))
-	}
-
-	return strings.Join(args, ", "), nil
-}
@@ -17,12 +17,13 @@ func TestScriptQueryBuilder_SetHasReturnValue(t *testing.T) {
 	b.AddArg("arg")
 	query, err := b.Build()
 	assert.Nil(err)
-	assert.Equal("SELECT script('arg')", query.ToSql())
+	assert.Equal("SELECT script($1)", query.ToSql())
+	assert.Equal([]interface{}{"arg"}, query.Args())
 
 	b.SetHasReturnValue(true)
 	query, err = b.Build()
 	assert.Nil(err)
-	assert.Equal("SELECT * FROM script('arg')", query.ToSql())
+	assert.Equal("SELECT * FROM script($1)", query.ToSql())
 }
 
 func TestScriptQueryBuilder_SetScript(t *testing.T) {
@@ -97,7 +98,8 @@ func TestScriptQueryBuilder_Build_SingleArg(t *testing.T) {
 	query, err := b.Build()
 	assert.Nil(err)
 	assert.True(query.Valid())
-	assert.Equal("SELECT script('arg')", query.ToSql())
+	assert.Equal("SELECT script($1)", query.ToSql())
+	assert.Equal([]interface{}{"arg"}, query.Args())
 }
 
 func TestScriptQueryBuilder_Build_MultiArgs(t *testing.T) {
@@ -111,7 +113,8 @@ func TestScriptQueryBuilder_Build_MultiArgs(t *testing.T) {
 	query, err := b.Build()
 	assert.Nil(err)
 	assert.True(query.Valid())
-	assert.Equal("SELECT script('arg1', 'arg2')", query.ToSql())
+	assert.Equal("SELECT script($1, $2)", query.ToSql())
+	assert.Equal([]interface{}{"arg1", "arg2"}, query.Args())
 }
 
 func TestScriptQueryBuilder_Build_ArgWithError(t *testing.T) {
@@ -11,22 +11,45 @@ type SelectQueryBuilder interface {
 	QueryBuilder
 
 	SetTable(table string) error
+	SetAlias(alias string) error
+	AddJoin(join Join) error
 	AddProp(prop string) error
+	AddAggregate(aggregate Aggregate) error
 	SetFilter(filter Filter) error
+	AddGroupBy(column string) error
+	SetHaving(filter Filter) error
+	AddOrderBy(orderBy OrderBy) error
+	SetLimit(limit int) error
+	SetOffset(offset int) error
+	SetCursor(cursor Cursor) error
 	SetVerbose(verbose bool)
 }
 
 type selectQueryBuilder struct {
-	propsKeys map[string]bool
-	props     []string
-	table     string
-	filter    Filter
-	verbose   bool
+	propsKeys   map[string]bool
+	props       []string
+	table       string
+	alias       string
+	tablesKeys  map[string]bool
+	joins       []Join
+	filter      Filter
+	groupByKeys map[string]bool
+	groupBy     []string
+	having      Filter
+	orderByKeys map[string]bool
+	orderBy     []OrderBy
+	limit       int
+	offset      int
+	cursor      Cursor
+	verbose     bool
 }
 
 func NewSelectQueryBuilder() SelectQueryBuilder {
 	return &selectQueryBuilder{
-		propsKeys: make(map[string]bool),
+		propsKeys:   make(map[string]bool),
+		tablesKeys:  make(map[string]bool),
+		groupByKeys: make(map[string]bool),
+		orderByKeys: make(map[string]bool),
 	}
 }
 
@@ -39,6 +62,29 @@ func (b *selectQueryBuilder) SetTable(table string) error {
 	return nil
 }
 
+func (b *selectQueryBuilder) SetAlias(alias string) error {
+	if len(alias) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlTableAlias)
+	}
+
+	b.alias = alias
+	return nil
+}
+
+func (b *selectQueryBuilder) AddJoin(join Join) error {
+	if err := join.valid(); err != nil {
+		return err
+	}
+
+	if _, ok := b.tablesKeys[join.name()]; ok {
+		return errors.NewCode(errors.ErrDuplicatedSqlTable)
+	}
+
+	b.tablesKeys[join.name()] = true
+	b.joins = append(b.joins, join)
+	return nil
+}
+
 func (b *selectQueryBuilder) AddProp(prop string) error {
 	if len(prop) == 0 {
 		return errors.NewCode(errors.ErrInvalidSqlProp)
@@ -53,6 +99,21 @@ func (b *selectQueryBuilder) AddProp(prop string) error {
 	return nil
 }
 
+func (b *selectQueryBuilder) AddAggregate(aggregate Aggregate) error {
+	if err := aggregate.valid(); err != nil {
+		return err
+	}
+
+	prop := aggregate.toSql()
+	if _, ok := b.propsKeys[prop]; ok {
+		return errors.NewCode(errors.ErrDuplicatedSqlProp)
+	}
+
+	b.propsKeys[prop] = true
+	b.props = append(b.props, prop)
+	return nil
+}
+
 func (b *selectQueryBuilder) SetFilter(filter Filter) error {
 	if !filter.Valid() {
 		return errors.NewCode(errors.ErrInvalidSqlFilter)
@@ -62,6 +123,70 @@ func (b *selectQueryBuilder) SetFilter(filter Filter) error {
 	return nil
 }
 
+func (b *selectQueryBuilder) AddGroupBy(column string) error {
+	if len(column) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlColumn)
+	}
+
+	if _, ok := b.groupByKeys[column]; ok {
+		return errors.NewCode(errors.ErrDuplicatedSqlColumn)
+	}
+
+	b.groupByKeys[column] = true
+	b.groupBy = append(b.groupBy, column)
+	return nil
+}
+
+func (b *selectQueryBuilder) SetHaving(filter Filter) error {
+	if !filter.Valid() {
+		return errors.NewCode(errors.ErrInvalidSqlFilter)
+	}
+
+	b.having = filter
+	return nil
+}
+
+func (b *selectQueryBuilder) AddOrderBy(orderBy OrderBy) error {
+	if err := orderBy.valid(); err != nil {
+		return err
+	}
+
+	if _, ok := b.orderByKeys[orderBy.Column]; ok {
+		return errors.NewCode(errors.ErrDuplicatedSqlColumn)
+	}
+
+	b.orderByKeys[orderBy.Column] = true
+	b.orderBy = append(b.orderBy, orderBy)
+	return nil
+}
+
+func (b *selectQueryBuilder) SetLimit(limit int) error {
+	if limit <= 0 {
+		return errors.NewCode(errors.ErrInvalidSqlLimit)
+	}
+
+	b.limit = limit
+	return nil
+}
+
+func (b *selectQueryBuilder) SetOffset(offset int) error {
+	if offset < 0 {
+		return errors.NewCode(errors.ErrInvalidSqlOffset)
+	}
+
+	b.offset = offset
+	return nil
+}
+
+func (b *selectQueryBuilder) SetCursor(cursor Cursor) error {
+	if len(cursor) == 0 {
+		return errors.NewCode(errors.ErrInvalidSqlCursor)
+	}
+
+	b.cursor = cursor
+	return nil
+}
+
 func (b *selectQueryBuilder) SetVerbose(verbose bool) {
 	b.verbose = verbose
 }
@@ -74,20 +199,93 @@ func (b *selectQueryBuilder) Build() (Query, error) {
 		return queryImpl{}, errors.WrapCode(errors.NewCode(errors.ErrNoPropInSqlSelectQuery), errors.ErrSqlTranslationFailed)
 	}
 
+	if _, ok := b.tablesKeys[b.tableName()]; ok {
+		return queryImpl{}, errors.WrapCode(errors.NewCode(errors.ErrDuplicatedSqlTable), errors.ErrSqlTranslationFailed)
+	}
+
+	var args sqlArgs
 	propsAsStr := b.propsToStr()
-	sqlQuery := fmt.Sprintf("SELECT %s FROM %s", propsAsStr, b.table)
-	if b.filter != nil {
-		sqlQuery += fmt.Sprintf(" WHERE %s", b.filter.ToSql())
+	sqlQuery := fmt.Sprintf("SELECT %s FROM %s", propsAsStr, tableToSql(b.table, b.alias))
+	for _, join := range b.joins {
+		sqlQuery += fmt.Sprintf(" %s", join.toSql(&args))
+	}
+
+	whereClause, err := b.whereToStr(&args)
+	if err != nil {
+		return queryImpl{}, errors.WrapCode(err, errors.ErrSqlTranslationFailed)
+	}
+	if len(whereClause) > 0 {
+		sqlQuery += fmt.Sprintf(" WHERE %s", whereClause)
+	}
+	// https://www.postgresql.org/docs/current/queries-table-expressions.html#QUERIES-GROUP
+	if len(b.groupBy) > 0 {
+		sqlQuery += fmt.Sprintf(" GROUP BY %s", strings.Join(b.groupBy, ", "))
+	}
+	if b.having != nil {
+		sqlQuery += fmt.Sprintf(" HAVING %s", args.addFilter(b.having))
+	}
+	if len(b.orderBy) > 0 {
+		sqlQuery += fmt.Sprintf(" ORDER BY %s", b.orderByToStr())
+	}
+	// https://www.postgresql.org/docs/current/queries-limit.html
+	if b.limit > 0 {
+		sqlQuery += fmt.Sprintf(" LIMIT %d", b.limit)
+	}
+	if b.offset > 0 {
+		sqlQuery += fmt.Sprintf(" OFFSET %d", b.offset)
 	}
 
 	query := queryImpl{
 		sqlCode: sqlQuery,
+		args:    args.values,
 		verbose: b.verbose,
 	}
 
 	return query, nil
 }
 
+func (b *selectQueryBuilder) tableName() string {
+	if len(b.alias) > 0 {
+		return b.alias
+	}
+	return b.table
+}
+
 func (b *selectQueryBuilder) propsToStr() string {
 	return strings.Join(b.props, ", ")
 }
+
+func (b *selectQueryBuilder) whereToStr(args *sqlArgs) (string, error) {
+	if len(b.cursor) == 0 {
+		if b.filter == nil {
+			return "", nil
+		}
+		return args.addFilter(b.filter), nil
+	}
+
+	values, err := b.cursor.decode()
+	if err != nil {
+		return "", err
+	}
+	if len(values) != len(b.orderBy) {
+		return "", errors.NewCode(errors.ErrInvalidSqlCursor)
+	}
+
+	seek := seekFilter(b.orderBy, values)
+	if b.filter == nil {
+		return args.addFilter(seek), nil
+	}
+
+	filter := fmt.Sprintf("(%s)", args.addFilter(b.filter))
+	filter += fmt.Sprintf(" AND (%s)", args.addFilter(seek))
+	return filter, nil
+}
+
+func (b *selectQueryBuilder) orderByToStr() string {
+	orderBy := make([]string, 0, len(b.orderBy))
+	for _, order := range b.orderBy {
+		orderBy = append(orderBy, order.toSql())
+	}
+
+	return strings.Join(orderBy, ", ")
+}
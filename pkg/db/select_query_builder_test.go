@@ -150,3 +150,329 @@ func TestSelectQueryBuilder_Build_WithFilter(t *testing.T) {
 	assert.True(query.Valid())
 	assert.Equal("SELECT prop1 FROM table WHERE someFilter", query.ToSql())
 }
+
+func TestSelectQueryBuilder_Build_WithFilterArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+	b.SetTable("table")
+	b.AddProp("prop1")
+	f := filterImpl{
+		sqlCode: "key in ($1)",
+		args:    []interface{}{"value"},
+	}
+	b.SetFilter(f)
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.True(query.Valid())
+	assert.Equal("SELECT prop1 FROM table WHERE key in ($1)", query.ToSql())
+	assert.Equal([]interface{}{"value"}, query.Args())
+}
+
+func TestSelectQueryBuilder_AddOrderBy(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+
+	err := b.AddOrderBy(OrderBy{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlColumn))
+
+	err = b.AddOrderBy(OrderBy{Column: "column", Order: SortOrder(4)})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlOrdering))
+
+	err = b.AddOrderBy(OrderBy{Column: "column"})
+	assert.Nil(err)
+
+	err = b.AddOrderBy(OrderBy{Column: "column", Order: Descending})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDuplicatedSqlColumn))
+}
+
+func TestSelectQueryBuilder_SetLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+
+	err := b.SetLimit(0)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlLimit))
+
+	err = b.SetLimit(10)
+	assert.Nil(err)
+}
+
+func TestSelectQueryBuilder_SetOffset(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+
+	err := b.SetOffset(-1)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlOffset))
+
+	err = b.SetOffset(10)
+	assert.Nil(err)
+}
+
+func TestSelectQueryBuilder_SetCursor(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+
+	err := b.SetCursor("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlCursor))
+
+	err = b.SetCursor(Cursor("someCursor"))
+	assert.Nil(err)
+}
+
+func TestSelectQueryBuilder_Build_WithOrderBy(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+	b.SetTable("table")
+	b.AddProp("prop")
+	b.AddOrderBy(OrderBy{Column: "created_at", Order: Descending, Nulls: NullsLast})
+	b.AddOrderBy(OrderBy{Column: "id"})
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("SELECT prop FROM table ORDER BY created_at DESC NULLS LAST, id", query.ToSql())
+}
+
+func TestSelectQueryBuilder_Build_WithLimitAndOffset(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+	b.SetTable("table")
+	b.AddProp("prop")
+	b.SetFilter(filterImpl{sqlCode: "key = $1", args: []interface{}{"value"}})
+	b.AddOrderBy(OrderBy{Column: "id"})
+	b.SetLimit(10)
+	b.SetOffset(20)
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("SELECT prop FROM table WHERE key = $1 ORDER BY id LIMIT 10 OFFSET 20", query.ToSql())
+	assert.Equal([]interface{}{"value"}, query.Args())
+}
+
+func TestSelectQueryBuilder_Build_WithCursor(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewCursor("2023-01-01", 12)
+	assert.Nil(err)
+
+	b := NewSelectQueryBuilder()
+	b.SetTable("table")
+	b.AddProp("prop")
+	b.AddOrderBy(OrderBy{Column: "created_at", Order: Descending})
+	b.AddOrderBy(OrderBy{Column: "id"})
+	b.SetLimit(10)
+	b.SetCursor(c)
+
+	query, err := b.Build()
+	assert.Nil(err)
+	expected := "SELECT prop FROM table WHERE (created_at < $1) OR (created_at = $1 AND id > $2) ORDER BY created_at DESC, id LIMIT 10"
+	assert.Equal(expected, query.ToSql())
+	assert.Equal([]interface{}{"2023-01-01", "12"}, query.Args())
+}
+
+func TestSelectQueryBuilder_Build_WithCursorAndFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewCursor(12)
+	assert.Nil(err)
+
+	b := NewSelectQueryBuilder()
+	b.SetTable("table")
+	b.AddProp("prop")
+	b.SetFilter(filterImpl{sqlCode: "key = $1", args: []interface{}{"value"}})
+	b.AddOrderBy(OrderBy{Column: "id"})
+	b.SetCursor(c)
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("SELECT prop FROM table WHERE (key = $1) AND ((id > $2)) ORDER BY id", query.ToSql())
+	assert.Equal([]interface{}{"value", "12"}, query.Args())
+}
+
+func TestSelectQueryBuilder_Build_CursorMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewCursor(12, "other")
+	assert.Nil(err)
+
+	b := NewSelectQueryBuilder()
+	b.SetTable("table")
+	b.AddProp("prop")
+	b.AddOrderBy(OrderBy{Column: "id"})
+	b.SetCursor(c)
+
+	_, err = b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrInvalidSqlCursor))
+}
+
+func TestSelectQueryBuilder_SetAlias(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+
+	err := b.SetAlias("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlTableAlias))
+
+	err = b.SetAlias("u")
+	assert.Nil(err)
+}
+
+func TestSelectQueryBuilder_AddJoin(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+
+	err := b.AddJoin(Join{Table: "profiles"})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlFilter))
+
+	on := filterImpl{sqlCode: "u.id = p.user_id"}
+	err = b.AddJoin(Join{Table: "profiles", Alias: "p", On: on})
+	assert.Nil(err)
+
+	err = b.AddJoin(Join{Table: "participations", Alias: "p", On: on})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDuplicatedSqlTable))
+}
+
+func TestSelectQueryBuilder_Build_WithAlias(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+	b.SetTable("users")
+	b.SetAlias("u")
+	b.AddProp(QualifiedColumn("u", "id"))
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("SELECT u.id FROM users AS u", query.ToSql())
+}
+
+func TestSelectQueryBuilder_Build_WithJoins(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+	b.SetTable("users")
+	b.SetAlias("u")
+	b.AddProp(QualifiedColumn("u", "id"))
+	b.AddProp(QualifiedColumn("p", "id"))
+	b.AddProp(QualifiedColumn("m", "id"))
+	b.AddJoin(Join{
+		Table: "profiles",
+		Alias: "p",
+		On:    filterImpl{sqlCode: "u.id = p.user_id"},
+	})
+	b.AddJoin(Join{
+		Type:  LeftJoin,
+		Table: "match_participations",
+		Alias: "m",
+		On:    filterImpl{sqlCode: "m.kind = $1", args: []interface{}{"ranked"}},
+	})
+	b.SetFilter(filterImpl{sqlCode: "u.name = $1", args: []interface{}{"someName"}})
+
+	query, err := b.Build()
+	assert.Nil(err)
+	expected := "SELECT u.id, p.id, m.id FROM users AS u INNER JOIN profiles AS p ON u.id = p.user_id LEFT JOIN match_participations AS m ON m.kind = $1 WHERE u.name = $2"
+	assert.Equal(expected, query.ToSql())
+	assert.Equal([]interface{}{"ranked", "someName"}, query.Args())
+}
+
+func TestSelectQueryBuilder_Build_JoinWithSameNameAsTable(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+	b.SetTable("users")
+	b.AddProp("users.id")
+	b.AddJoin(Join{
+		Table: "users",
+		On:    filterImpl{sqlCode: "users.id = users.id"},
+	})
+
+	_, err := b.Build()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrSqlTranslationFailed))
+	cause := errors.Unwrap(err)
+	assert.True(errors.IsErrorWithCode(cause, errors.ErrDuplicatedSqlTable))
+}
+
+func TestSelectQueryBuilder_AddAggregate(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+
+	err := b.AddAggregate(Aggregate{Function: Sum})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlColumn))
+
+	err = b.AddAggregate(Aggregate{Function: Sum, Column: "score", Alias: "total"})
+	assert.Nil(err)
+
+	err = b.AddAggregate(Aggregate{Function: Sum, Column: "score", Alias: "total"})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDuplicatedSqlProp))
+}
+
+func TestSelectQueryBuilder_AddGroupBy(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+
+	err := b.AddGroupBy("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlColumn))
+
+	err = b.AddGroupBy("column")
+	assert.Nil(err)
+
+	err = b.AddGroupBy("column")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDuplicatedSqlColumn))
+}
+
+func TestSelectQueryBuilder_SetHaving(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+
+	err := b.SetHaving(filterImpl{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlFilter))
+
+	err = b.SetHaving(filterImpl{sqlCode: "someSqlCode"})
+	assert.Nil(err)
+}
+
+func TestSelectQueryBuilder_Build_WithAggregates(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewSelectQueryBuilder()
+	b.SetTable("matches")
+	b.AddAggregate(Aggregate{Function: Count, Column: "*", Alias: "total"})
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("SELECT COUNT(*) AS total FROM matches", query.ToSql())
+}
+
+func TestSelectQueryBuilder_Build_WithGroupByAndHaving(t *testing.T) {
+	assert := assert.New(t)
+
+	count := Aggregate{Function: Count, Column: "*", Alias: "played"}
+
+	b := NewSelectQueryBuilder()
+	b.SetTable("matches")
+	b.AddProp("user_id")
+	b.AddAggregate(count)
+	b.AddAggregate(Aggregate{Function: Max, Column: "score", Alias: "best"})
+	b.SetFilter(filterImpl{sqlCode: "kind = $1", args: []interface{}{"ranked"}})
+	b.AddGroupBy("user_id")
+	b.SetHaving(filterImpl{sqlCode: count.Expression() + " > $1", args: []interface{}{10}})
+	b.AddOrderBy(OrderBy{Column: "best", Order: Descending})
+	b.SetLimit(5)
+
+	query, err := b.Build()
+	assert.Nil(err)
+	expected := "SELECT user_id, COUNT(*) AS played, MAX(score) AS best FROM matches WHERE kind = $1 GROUP BY user_id HAVING COUNT(*) > $2 ORDER BY best DESC LIMIT 5"
+	assert.Equal(expected, query.ToSql())
+	assert.Equal([]interface{}{"ranked", 10}, query.Args())
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -35,17 +36,59 @@ func argToStr(arg interface{}) (string, error) {
 	return out, err
 }
 
+// https://github.com/jackc/pgx/blob/v3.6.2/values.go
+func argToSqlArg(arg interface{}) (interface{}, error) {
+	switch v := arg.(type) {
+	case nil:
+		return nil, nil
+	case Convertible:
+		return argToStr(v)
+	case bool, int, int8, int16, int32, int64, uint8, uint16, uint32, float32, float64, []byte, time.Time:
+		return v, nil
+	default:
+		return argToStr(v)
+	}
+}
+
+// https://github.com/jackc/pgx/blob/v3.6.2/copy_from.go
+// The COPY protocol uses the binary format so values can't be
+// converted to strings: they are handed to pgx which encodes
+// them based on the type of the target column.
+func argToCopyArg(arg interface{}) interface{} {
+	if convertible, ok := arg.(Convertible); ok {
+		return convertible.Convert()
+	}
+
+	return arg
+}
+
+func sqlArgToDebugStr(arg interface{}) string {
+	if arg == nil {
+		return "NULL"
+	}
+	if t, ok := arg.(time.Time); ok {
+		return fmt.Sprintf("'%s'", t.Format(time.RFC3339))
+	}
+
+	str, err := argToStr(arg)
+	if err != nil {
+		return fmt.Sprintf("'%v'", arg)
+	}
+
+	return fmt.Sprintf("'%s'", str)
+}
+
 type sqlProp struct {
 	column string
 	value  interface{}
 }
 
-func sqlPropAsUpdateToStr(update sqlProp) (string, error) {
-	arg, err := argToStr(update.value)
+func sqlPropAsUpdateToStr(update sqlProp, args *sqlArgs) (string, error) {
+	placeholder, err := args.add(update.value)
 	if err != nil {
 		return "", err
 	}
 
-	out := fmt.Sprintf("%s = '%s'", update.column, arg)
+	out := fmt.Sprintf("%s = %s", update.column, placeholder)
 	return out, nil
 }
@@ -2,6 +2,7 @@ package db
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -98,9 +99,11 @@ func TestSqlPropAsUpdateToStr(t *testing.T) {
 		value:  32,
 	}
 
-	out, err := sqlPropAsUpdateToStr(update)
+	var args sqlArgs
+	out, err := sqlPropAsUpdateToStr(update, &args)
 	assert.Nil(err)
-	assert.Equal("column = '32'", out)
+	assert.Equal("column = $1", out)
+	assert.Equal([]interface{}{32}, args.values)
 }
 
 func TestSqlPropAsUpdateToStr_Unmarshalable(t *testing.T) {
@@ -111,6 +114,89 @@ func TestSqlPropAsUpdateToStr_Unmarshalable(t *testing.T) {
 		value:  mockUnmarshalable{},
 	}
 
-	_, err := sqlPropAsUpdateToStr(update)
+	var args sqlArgs
+	_, err := sqlPropAsUpdateToStr(update, &args)
 	assert.Contains(err.Error(), errDefault.Error())
 }
+
+func TestArgToSqlArg_Nil(t *testing.T) {
+	assert := assert.New(t)
+
+	out, err := argToSqlArg(nil)
+	assert.Nil(err)
+	assert.Nil(out)
+}
+
+func TestArgToSqlArg_Native(t *testing.T) {
+	assert := assert.New(t)
+
+	someTime := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
+
+	out, err := argToSqlArg(32)
+	assert.Nil(err)
+	assert.Equal(32, out)
+
+	out, err = argToSqlArg(true)
+	assert.Nil(err)
+	assert.Equal(true, out)
+
+	out, err = argToSqlArg(someTime)
+	assert.Nil(err)
+	assert.Equal(someTime, out)
+}
+
+func TestArgToSqlArg_Uuid(t *testing.T) {
+	assert := assert.New(t)
+
+	arg := uuid.New()
+
+	out, err := argToSqlArg(arg)
+	assert.Nil(err)
+	assert.Equal(arg.String(), out)
+}
+
+func TestArgToSqlArg_Convertible(t *testing.T) {
+	assert := assert.New(t)
+
+	arg := mockConvertible{value: 32}
+
+	out, err := argToSqlArg(arg)
+	assert.Nil(err)
+	assert.Equal("32", out)
+}
+
+func TestArgToSqlArg_ComplexArg(t *testing.T) {
+	assert := assert.New(t)
+
+	arg := mockComplexArg{Value: 26, Name: "someName"}
+
+	out, err := argToSqlArg(arg)
+	assert.Nil(err)
+	assert.Equal("{\"Value\":26,\"Name\":\"someName\"}", out)
+}
+
+func TestArgToSqlArg_Unmarshalable(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := argToSqlArg(mockUnmarshalable{})
+	assert.Contains(err.Error(), errDefault.Error())
+}
+
+func TestSqlArgToDebugStr(t *testing.T) {
+	assert := assert.New(t)
+
+	someTime := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
+
+	assert.Equal("NULL", sqlArgToDebugStr(nil))
+	assert.Equal("'hello'", sqlArgToDebugStr("hello"))
+	assert.Equal("'32'", sqlArgToDebugStr(32))
+	assert.Equal("'2009-11-17T20:34:58Z'", sqlArgToDebugStr(someTime))
+}
+
+func TestArgToCopyArg(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(32, argToCopyArg(mockConvertible{value: 32}))
+	assert.Equal("haha", argToCopyArg("haha"))
+	assert.Nil(argToCopyArg(nil))
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
+)
+
+var warnLog = logger.ScopedWarnf
+
+type slowQueryHook struct {
+	threshold time.Duration
+}
+
+func NewSlowQueryHook(threshold time.Duration) QueryHook {
+	return slowQueryHook{
+		threshold: threshold,
+	}
+}
+
+func (h slowQueryHook) BeforeQuery(ctx context.Context, event QueryEvent) context.Context {
+	return ctx
+}
+
+func (h slowQueryHook) AfterQuery(ctx context.Context, event QueryEvent) {
+	if event.Duration < h.threshold {
+		return
+	}
+
+	label := event.Label
+	if len(label) == 0 {
+		label = UnlabeledQuery
+	}
+
+	warnLog(ctx, "slow query %s took %v (threshold: %v): %s", label, event.Duration, h.threshold, queryToDebugStr(event.Query))
+}
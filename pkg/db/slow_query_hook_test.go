@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlowQueryHook(t *testing.T) {
+	assert := assert.New(t)
+	defer func() {
+		warnLog = logger.ScopedWarnf
+	}()
+
+	var warnings []string
+	warnLog = func(ctx context.Context, format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	h := NewSlowQueryHook(time.Second)
+	event := QueryEvent{
+		Query:    queryImpl{sqlCode: "SELECT name FROM t"},
+		Duration: 999 * time.Millisecond,
+	}
+
+	ctx := h.BeforeQuery(context.Background(), event)
+	h.AfterQuery(ctx, event)
+	assert.Empty(warnings)
+
+	event.Duration = 2 * time.Second
+	h.AfterQuery(ctx, event)
+	event.Label = "label"
+	h.AfterQuery(ctx, event)
+	assert.Equal([]string{
+		"slow query unlabeled took 2s (threshold: 1s): SELECT name FROM t",
+		"slow query label took 2s (threshold: 1s): SELECT name FROM t",
+	}, warnings)
+}
@@ -0,0 +1,178 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type sqlArgs struct {
+	values []interface{}
+}
+
+func (a *sqlArgs) add(value interface{}) (string, error) {
+	arg, err := argToSqlArg(value)
+	if err != nil {
+		return "", err
+	}
+
+	a.values = append(a.values, arg)
+	return fmt.Sprintf("$%d", len(a.values)), nil
+}
+
+func (a *sqlArgs) addAll(values []interface{}) (string, error) {
+	placeholders := make([]string, 0, len(values))
+	for _, value := range values {
+		placeholder, err := a.add(value)
+		if err != nil {
+			return "", err
+		}
+
+		placeholders = append(placeholders, placeholder)
+	}
+
+	return strings.Join(placeholders, ", "), nil
+}
+
+func (a *sqlArgs) addFilter(filter Filter) string {
+	sqlCode := shiftPlaceholders(filter.ToSql(), len(a.values))
+	a.values = append(a.values, filter.Args()...)
+	return sqlCode
+}
+
+func shiftPlaceholders(sqlCode string, offset int) string {
+	if offset == 0 {
+		return sqlCode
+	}
+
+	return replacePlaceholders(sqlCode, func(id int, placeholder string) string {
+		return fmt.Sprintf("$%d", id+offset)
+	})
+}
+
+func inlinePlaceholders(sqlCode string, args []interface{}) string {
+	return replacePlaceholders(sqlCode, func(id int, placeholder string) string {
+		if id < 1 || id > len(args) {
+			return placeholder
+		}
+
+		return sqlArgToDebugStr(args[id-1])
+	})
+}
+
+// replacePlaceholders calls the replacement function for each positional
+// placeholder of the code. The string literals, quoted identifiers,
+// dollar-quoted bodies and comments are copied verbatim: a '$1' in there
+// is not a placeholder.
+// https://www.postgresql.org/docs/current/sql-syntax-lexical.html
+func replacePlaceholders(sqlCode string, replace func(id int, placeholder string) string) string {
+	var out strings.Builder
+	out.Grow(len(sqlCode))
+
+	for i := 0; i < len(sqlCode); {
+		end := i + 1
+
+		if quotedEnd, ok := skipQuotedSql(sqlCode, i); ok {
+			end = quotedEnd
+		} else if sqlCode[i] == '$' && (i == 0 || !isIdentifierChar(sqlCode[i-1])) {
+			if digits := countDigits(sqlCode[i+1:]); digits > 0 {
+				end = i + 1 + digits
+				id, _ := strconv.Atoi(sqlCode[i+1 : end])
+				out.WriteString(replace(id, sqlCode[i:end]))
+				i = end
+				continue
+			}
+		}
+
+		out.WriteString(sqlCode[i:end])
+		i = end
+	}
+
+	return out.String()
+}
+
+// skipQuotedSql returns the position right after the string literal,
+// quoted identifier, dollar-quoted body or comment starting at i if any.
+func skipQuotedSql(sqlCode string, i int) (int, bool) {
+	switch {
+	case sqlCode[i] == '\'':
+		return skipStringLiteral(sqlCode, i), true
+	case sqlCode[i] == '"':
+		return skipDelimited(sqlCode, i+1, `"`), true
+	case strings.HasPrefix(sqlCode[i:], "--"):
+		return skipDelimited(sqlCode, i+2, "\n"), true
+	case strings.HasPrefix(sqlCode[i:], "/*"):
+		return skipDelimited(sqlCode, i+2, "*/"), true
+	case sqlCode[i] == '$' && (i == 0 || !isIdentifierChar(sqlCode[i-1])):
+		if tag, ok := dollarQuoteTag(sqlCode[i:]); ok {
+			return skipDelimited(sqlCode, i+len(tag), tag), true
+		}
+	}
+
+	return i, false
+}
+
+// skipStringLiteral returns the position right after the literal opening
+// at start. A doubled quote is read as the end of a literal directly
+// followed by another one, and backslashes escape characters in escape
+// strings (E'...').
+func skipStringLiteral(sqlCode string, start int) int {
+	escapes := start > 0 && (sqlCode[start-1] == 'E' || sqlCode[start-1] == 'e')
+	if escapes && start > 1 && isIdentifierChar(sqlCode[start-2]) {
+		escapes = false
+	}
+
+	for i := start + 1; i < len(sqlCode); i++ {
+		switch {
+		case escapes && sqlCode[i] == '\\':
+			i++
+		case sqlCode[i] == '\'':
+			return i + 1
+		}
+	}
+
+	return len(sqlCode)
+}
+
+func skipDelimited(sqlCode string, from int, delimiter string) int {
+	if from > len(sqlCode) {
+		return len(sqlCode)
+	}
+
+	end := strings.Index(sqlCode[from:], delimiter)
+	if end < 0 {
+		return len(sqlCode)
+	}
+
+	return from + end + len(delimiter)
+}
+
+func dollarQuoteTag(sqlCode string) (string, bool) {
+	for i := 1; i < len(sqlCode); i++ {
+		c := sqlCode[i]
+		switch {
+		case c == '$':
+			return sqlCode[:i+1], true
+		case c >= '0' && c <= '9' && i == 1:
+			return "", false
+		case !isIdentifierChar(c):
+			return "", false
+		}
+	}
+
+	return "", false
+}
+
+func countDigits(sqlCode string) int {
+	count := 0
+	for count < len(sqlCode) && sqlCode[count] >= '0' && sqlCode[count] <= '9' {
+		count++
+	}
+
+	return count
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
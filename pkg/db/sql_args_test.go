@@ -0,0 +1,96 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqlArgs_Add(t *testing.T) {
+	assert := assert.New(t)
+
+	var args sqlArgs
+
+	out, err := args.add("value")
+	assert.Nil(err)
+	assert.Equal("$1", out)
+
+	out, err = args.add(32)
+	assert.Nil(err)
+	assert.Equal("$2", out)
+
+	assert.Equal([]interface{}{"value", 32}, args.values)
+}
+
+func TestSqlArgs_Add_Error(t *testing.T) {
+	assert := assert.New(t)
+
+	var args sqlArgs
+
+	_, err := args.add(mockUnmarshalable{})
+	assert.Contains(err.Error(), errDefault.Error())
+	assert.Equal(0, len(args.values))
+}
+
+func TestSqlArgs_AddAll(t *testing.T) {
+	assert := assert.New(t)
+
+	var args sqlArgs
+	args.add("first")
+
+	out, err := args.addAll([]interface{}{"second", "third"})
+	assert.Nil(err)
+	assert.Equal("$2, $3", out)
+	assert.Equal([]interface{}{"first", "second", "third"}, args.values)
+}
+
+func TestSqlArgs_AddFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	var args sqlArgs
+	args.add("first")
+
+	f := filterImpl{
+		sqlCode: "key in ($1, $2)",
+		args:    []interface{}{"second", "third"},
+	}
+
+	out := args.addFilter(f)
+	assert.Equal("key in ($2, $3)", out)
+	assert.Equal([]interface{}{"first", "second", "third"}, args.values)
+}
+
+func TestShiftPlaceholders(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("key = $1", shiftPlaceholders("key = $1", 0))
+	assert.Equal("key = $4 AND other = $13", shiftPlaceholders("key = $1 AND other = $10", 3))
+}
+
+func TestShiftPlaceholders_IgnoresQuotedCode(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("key = '$1' AND other = $3", shiftPlaceholders("key = '$1' AND other = $1", 2))
+	assert.Equal("key = 'it''s $1' AND other = $3", shiftPlaceholders("key = 'it''s $1' AND other = $1", 2))
+	assert.Equal("key = E'\\' $1' AND other = $3", shiftPlaceholders("key = E'\\' $1' AND other = $1", 2))
+	assert.Equal(`"col$1" = $3`, shiftPlaceholders(`"col$1" = $1`, 2))
+	assert.Equal("key = $$ $1 $$ AND other = $3", shiftPlaceholders("key = $$ $1 $$ AND other = $1", 2))
+	assert.Equal("key = $tag$ $1 $$ $2 $tag$ AND other = $3", shiftPlaceholders("key = $tag$ $1 $$ $2 $tag$ AND other = $1", 2))
+	assert.Equal("key = $3 -- $1\nAND other = $4", shiftPlaceholders("key = $1 -- $1\nAND other = $2", 2))
+	assert.Equal("key = /* $1 */ $3", shiftPlaceholders("key = /* $1 */ $1", 2))
+	assert.Equal("col$1 = $3", shiftPlaceholders("col$1 = $1", 2))
+	assert.Equal("key = 'unterminated $1", shiftPlaceholders("key = 'unterminated $1", 2))
+}
+
+func TestInlinePlaceholders(t *testing.T) {
+	assert := assert.New(t)
+
+	out := inlinePlaceholders("key = $1 AND other in ($2, $3)", []interface{}{"it's", 32, nil})
+	assert.Equal("key = 'it's' AND other in ('32', NULL)", out)
+
+	out = inlinePlaceholders("key = $2", []interface{}{"value"})
+	assert.Equal("key = $2", out)
+
+	out = inlinePlaceholders("key = '$1' AND other = $1", []interface{}{"value"})
+	assert.Equal("key = '$1' AND other = 'value'", out)
+}
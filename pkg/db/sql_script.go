@@ -0,0 +1,39 @@
+package db
+
+import "strings"
+
+// SplitSqlStatements returns the statements of the script without
+// their comments. The semicolons of the literals, quoted identifiers
+// and dollar-quoted bodies do not end a statement.
+func SplitSqlStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); len(statement) > 0 {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(script); {
+		end, ok := skipQuotedSql(script, i)
+		switch {
+		case ok && (strings.HasPrefix(script[i:], "--") || strings.HasPrefix(script[i:], "/*")):
+			current.WriteByte(' ')
+		case ok:
+			current.WriteString(script[i:end])
+		case script[i] == ';':
+			flush()
+			end = i + 1
+		default:
+			current.WriteByte(script[i])
+			end = i + 1
+		}
+
+		i = end
+	}
+	flush()
+
+	return statements
+}
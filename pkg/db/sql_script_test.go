@@ -0,0 +1,27 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitSqlStatements(t *testing.T) {
+	assert := assert.New(t)
+
+	script := `-- Some comment; with a semicolon
+SET search_path = public;
+CREATE FUNCTION f() RETURNS TRIGGER AS $$
+  BEGIN
+    NEW.name = 'a;b';
+  END;
+$$ language 'plpgsql';
+/* ; */ SELECT ';'`
+
+	expected := []string{
+		"SET search_path = public",
+		"CREATE FUNCTION f() RETURNS TRIGGER AS $$\n  BEGIN\n    NEW.name = 'a;b';\n  END;\n$$ language 'plpgsql'",
+		"SELECT ';'",
+	}
+	assert.Equal(expected, SplitSqlStatements(script))
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/jackc/pgx"
+)
+
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	integrityConstraintViolationClass = "23"
+	notNullViolation                  = "23502"
+	foreignKeyViolation               = "23503"
+	uniqueViolation                   = "23505"
+	checkViolation                    = "23514"
+)
+
+var sqlStateErrorCodes = map[string]errors.ErrorCode{
+	uniqueViolation:      errors.ErrDbUniqueViolation,
+	foreignKeyViolation:  errors.ErrDbForeignKeyViolation,
+	notNullViolation:     errors.ErrDbNotNullViolation,
+	checkViolation:       errors.ErrDbCheckViolation,
+	serializationFailure: errors.ErrDbSerializationFailure,
+	deadlockDetected:     errors.ErrDbDeadlockDetected,
+}
+
+// Describes the constraint which prevented a query from succeeding.
+// Postgres does not report the column of all the violations, e.g. for
+// the unique ones only the constraint is known.
+type ConstraintViolation struct {
+	Table      string
+	Column     string
+	Constraint string
+}
+
+func ViolatedConstraint(err error) (ConstraintViolation, bool) {
+	pgErr, ok := errorCause(err).(pgx.PgError)
+	if !ok || !strings.HasPrefix(pgErr.Code, integrityConstraintViolationClass) {
+		return ConstraintViolation{}, false
+	}
+
+	violation := ConstraintViolation{
+		Table:      pgErr.TableName,
+		Column:     pgErr.ColumnName,
+		Constraint: pgErr.ConstraintName,
+	}
+	return violation, true
+}
+
+// The errors reported by the server are translated to a dedicated code
+// when the caller might want to react to them. The error returned by
+// pgx is kept as the cause in any case.
+func wrapSqlStateError(err error) error {
+	pgErr, ok := errorCause(err).(pgx.PgError)
+	if !ok {
+		return errors.WrapCode(err, errors.ErrDbRequestFailed)
+	}
+	code, ok := sqlStateErrorCodes[pgErr.Code]
+	if !ok {
+		return errors.WrapCode(err, errors.ErrDbRequestFailed)
+	}
+
+	if violation, ok := ViolatedConstraint(err); ok && violation != (ConstraintViolation{}) {
+		err = errors.Wrapf(err, "violated %s", violation)
+	}
+
+	return errors.WrapCode(err, code)
+}
+
+func (v ConstraintViolation) String() string {
+	var out []string
+	if len(v.Constraint) > 0 {
+		out = append(out, fmt.Sprintf("constraint %q", v.Constraint))
+	}
+	if len(v.Table) > 0 {
+		out = append(out, fmt.Sprintf("table %q", v.Table))
+	}
+	if len(v.Column) > 0 {
+		out = append(out, fmt.Sprintf("column %q", v.Column))
+	}
+
+	return strings.Join(out, ", ")
+}
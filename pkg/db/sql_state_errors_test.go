@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapSqlStateError(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(errors.IsErrorWithCode(wrapSqlStateError(errDefault), errors.ErrDbRequestFailed))
+	assert.True(errors.IsErrorWithCode(wrapSqlStateError(pgx.PgError{Code: "42601"}), errors.ErrDbRequestFailed))
+
+	codes := map[string]errors.ErrorCode{
+		uniqueViolation:      errors.ErrDbUniqueViolation,
+		foreignKeyViolation:  errors.ErrDbForeignKeyViolation,
+		notNullViolation:     errors.ErrDbNotNullViolation,
+		checkViolation:       errors.ErrDbCheckViolation,
+		serializationFailure: errors.ErrDbSerializationFailure,
+		deadlockDetected:     errors.ErrDbDeadlockDetected,
+	}
+	for sqlState, code := range codes {
+		err := wrapSqlStateError(pgx.PgError{Code: sqlState})
+		assert.True(errors.IsErrorWithCode(err, code), sqlState)
+		assert.Equal(pgx.PgError{Code: sqlState}, errorCause(err))
+	}
+}
+
+func TestWrapSqlStateError_ConstraintDetails(t *testing.T) {
+	assert := assert.New(t)
+
+	pgErr := pgx.PgError{
+		Code:           notNullViolation,
+		TableName:      "users",
+		ColumnName:     "mail",
+		ConstraintName: "users_mail_check",
+	}
+	err := wrapSqlStateError(pgErr)
+
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbNotNullViolation))
+	assert.Contains(err.Error(), `violated constraint "users_mail_check", table "users", column "mail"`)
+	assert.Equal(pgErr, errorCause(err))
+	assert.True(isTransientError(context.Background(), wrapSqlStateError(pgx.PgError{Code: deadlockDetected})))
+}
+
+func TestViolatedConstraint(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ok := ViolatedConstraint(nil)
+	assert.False(ok)
+	_, ok = ViolatedConstraint(errDefault)
+	assert.False(ok)
+	_, ok = ViolatedConstraint(wrapSqlStateError(pgx.PgError{Code: serializationFailure}))
+	assert.False(ok)
+
+	err := wrapSqlStateError(pgx.PgError{Code: foreignKeyViolation, TableName: "games", ConstraintName: "games_player_fkey"})
+	violation, ok := ViolatedConstraint(errors.WrapCode(err, errors.ErrDbEntityCreationFailure))
+	assert.True(ok)
+	assert.Equal(ConstraintViolation{Table: "games", Constraint: "games_player_fkey"}, violation)
+}
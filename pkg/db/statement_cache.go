@@ -0,0 +1,281 @@
+package db
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/KnoblauchPilze/go-game/pkg/common"
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
+	"github.com/jackc/pgx"
+)
+
+const statementNamePrefix = "stmt_"
+
+// Raised when a table used by a prepared statement changed since it
+// was prepared, typically after a migration.
+// https://github.com/postgres/postgres/blob/REL_15_0/src/backend/utils/cache/plancache.c#L727
+const featureNotSupported = "0A000"
+const stalePlanMessage = "cached plan must not change result type"
+
+type StatementCacheStats struct {
+	Hits          uint64
+	Misses        uint64
+	Invalidations uint64
+	// Number of statements prepared on the current connections.
+	Size int
+}
+
+// Implemented by the databases caching prepared statements.
+type StatementCacheReporter interface {
+	StatementCacheStats() StatementCacheStats
+}
+
+// Shared by the successive pools of a database.
+type statementCounters struct {
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	invalidations atomic.Uint64
+}
+
+// Prepares the statements on the connections they run on and runs them
+// by name afterwards: preparing them on the pool would reset the
+// connections in use. Once a connection holds maxSize statements the
+// least recently used one is deallocated.
+type statementCache struct {
+	maxSize  int
+	counters *statementCounters
+	lock     sync.Mutex
+	conns    map[pgxConn]*connStatements
+}
+
+// The statements prepared on a connection, the most recently used
+// first. A connection is used by a single query at a time so only the
+// map of the cache needs the lock.
+type connStatements struct {
+	names map[string]*list.Element
+	lru   *list.List
+}
+
+type preparedStatement struct {
+	key  string
+	name string
+}
+
+// Keeps the first row read to detect stale plans: pgx only reports the
+// errors of the server once the rows are read.
+type peekedRows struct {
+	pgxRows
+	peeked bool
+	next   bool
+}
+
+func newStatementCache(maxSize uint, counters *statementCounters) *statementCache {
+	return &statementCache{
+		maxSize:  int(maxSize),
+		counters: counters,
+		conns:    make(map[pgxConn]*connStatements),
+	}
+}
+
+func (c *statementCache) query(ctx context.Context, conn pgxConn, sql string, args []interface{}) (pgxRows, error) {
+	statement, ok := c.statement(ctx, conn, sql, args)
+	if !ok {
+		return conn.QueryEx(ctx, sql, nil, args...)
+	}
+
+	rows, err := c.queryPrepared(ctx, conn, statement.name, args)
+	if isStalePlanError(err) {
+		c.invalidate(conn, statement)
+		return conn.QueryEx(ctx, sql, nil, args...)
+	}
+
+	return rows, err
+}
+
+func (c *statementCache) exec(ctx context.Context, conn pgxConn, sql string, args []interface{}) (pgx.CommandTag, error) {
+	statement, ok := c.statement(ctx, conn, sql, args)
+	if !ok {
+		return conn.ExecEx(ctx, sql, nil, args...)
+	}
+
+	tag, err := conn.ExecEx(ctx, statement.name, nil, args...)
+	if isStalePlanError(err) {
+		c.invalidate(conn, statement)
+		return conn.ExecEx(ctx, sql, nil, args...)
+	}
+
+	return tag, err
+}
+
+// The statements of a connection which is closed are gone with it.
+func (c *statementCache) forget(conn pgxConn) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.conns, conn)
+}
+
+func (c *statementCache) size() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	size := 0
+	for _, statements := range c.conns {
+		size += statements.lru.Len()
+	}
+
+	return size
+}
+
+// Statements without arguments are not prepared: they might be scripts
+// made of several statements which postgres can't prepare.
+func (c *statementCache) statement(ctx context.Context, conn pgxConn, sql string, args []interface{}) (preparedStatement, bool) {
+	if len(args) == 0 {
+		return preparedStatement{}, false
+	}
+
+	key := normalizeSql(sql)
+
+	c.lock.Lock()
+	statements, ok := c.conns[conn]
+	if !ok {
+		statements = &connStatements{
+			names: make(map[string]*list.Element),
+			lru:   list.New(),
+		}
+		c.conns[conn] = statements
+	}
+	if elem, ok := statements.names[key]; ok {
+		statements.lru.MoveToFront(elem)
+		c.lock.Unlock()
+		c.counters.hits.Add(1)
+		return elem.Value.(preparedStatement), true
+	}
+
+	var evicted *preparedStatement
+	if statements.lru.Len() >= c.maxSize {
+		elem := statements.lru.Back()
+		statement := statements.lru.Remove(elem).(preparedStatement)
+		delete(statements.names, statement.key)
+		evicted = &statement
+	}
+	c.lock.Unlock()
+
+	c.counters.misses.Add(1)
+	if evicted != nil {
+		c.deallocate(conn, *evicted)
+	}
+
+	// A statement failing to be prepared runs without it: this reports
+	// the error of the server as is.
+	statement := preparedStatement{key: key, name: statementName(key)}
+	if _, err := conn.PrepareEx(ctx, statement.name, key, nil); err != nil {
+		return preparedStatement{}, false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	statements.names[key] = statements.lru.PushFront(statement)
+
+	return statement, true
+}
+
+func (c *statementCache) queryPrepared(ctx context.Context, conn pgxConn, name string, args []interface{}) (pgxRows, error) {
+	rows, err := conn.QueryEx(ctx, name, nil, args...)
+	if err != nil || common.IsInterfaceNil(rows) {
+		return rows, err
+	}
+
+	peeked := &peekedRows{
+		pgxRows: rows,
+		peeked:  true,
+		next:    rows.Next(),
+	}
+	if err := rows.Err(); !peeked.next && isStalePlanError(err) {
+		rows.Close()
+		return nil, err
+	}
+
+	return peeked, nil
+}
+
+// The statement is prepared again with the new result type on next
+// use. The other connections find out on their own.
+func (c *statementCache) invalidate(conn pgxConn, statement preparedStatement) {
+	c.lock.Lock()
+	if statements, ok := c.conns[conn]; ok {
+		if elem, ok := statements.names[statement.key]; ok {
+			statements.lru.Remove(elem)
+			delete(statements.names, statement.key)
+		}
+	}
+	c.lock.Unlock()
+
+	c.counters.invalidations.Add(1)
+	c.deallocate(conn, statement)
+}
+
+func (c *statementCache) deallocate(conn pgxConn, statement preparedStatement) {
+	if err := conn.Deallocate(statement.name); err != nil {
+		logger.Warnf("failed to deallocate statement %s (err: %v)", statement.name, err)
+	}
+}
+
+func (r *peekedRows) Next() bool {
+	if r.peeked {
+		r.peeked = false
+		return r.next
+	}
+
+	return r.pgxRows.Next()
+}
+
+// Collapses the whitespaces outside of the literals, quoted identifiers,
+// dollar-quoted bodies and comments: the statement is prepared with the
+// normalised sql.
+func normalizeSql(sql string) string {
+	var out strings.Builder
+	space := false
+
+	sql = strings.TrimSpace(sql)
+	for i := 0; i < len(sql); {
+		end, quoted := skipQuotedSql(sql, i)
+		if !quoted {
+			if isSqlSpace(sql[i]) {
+				space = true
+				i++
+				continue
+			}
+			end = i + 1
+		}
+
+		if space {
+			out.WriteByte(' ')
+			space = false
+		}
+		out.WriteString(sql[i:end])
+		i = end
+	}
+
+	return strings.TrimSpace(strings.TrimSuffix(out.String(), ";"))
+}
+
+func isSqlSpace(c byte) bool {
+	return strings.IndexByte(" \t\n\r\f\v", c) >= 0
+}
+
+// Derived from the sql so that a statement has the same name on all
+// the connections.
+func statementName(sql string) string {
+	sum := sha256.Sum256([]byte(sql))
+	return statementNamePrefix + hex.EncodeToString(sum[:8])
+}
+
+func isStalePlanError(err error) bool {
+	pgErr, ok := errorCause(err).(pgx.PgError)
+	return ok && pgErr.Code == featureNotSupported && pgErr.Message == stalePlanMessage
+}
@@ -0,0 +1,299 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+var errStalePlan = pgx.PgError{
+	Code:    featureNotSupported,
+	Message: stalePlanMessage,
+}
+
+func newTestStatementCache(maxSize uint) *statementCache {
+	return newStatementCache(maxSize, &statementCounters{})
+}
+
+func TestNormalizeSql(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("SELECT id FROM t WHERE a = $1", normalizeSql("  SELECT id\n\tFROM   t WHERE a = $1 ;  "))
+	assert.Equal("SELECT 'a  b', \"c  d\" FROM t", normalizeSql("SELECT   'a  b',  \"c  d\"\nFROM t"))
+	assert.Equal("SELECT 'it''s  ok' FROM t", normalizeSql("SELECT 'it''s  ok'  FROM t"))
+	assert.Equal("SELECT id -- the  id\n FROM t", normalizeSql("SELECT id -- the  id\n  FROM t"))
+	assert.Equal("SELECT /* a  b */ id FROM t", normalizeSql("SELECT  /* a  b */\nid FROM t"))
+	assert.Equal("SELECT $$a  'b$$ FROM t", normalizeSql("SELECT  $$a  'b$$ FROM t"))
+}
+
+func TestStatementName(t *testing.T) {
+	assert := assert.New(t)
+
+	name := statementName("SELECT id FROM t")
+	assert.Equal(name, statementName("SELECT id FROM t"))
+	assert.NotEqual(name, statementName("SELECT name FROM t"))
+	assert.Equal(len(statementNamePrefix)+16, len(name))
+}
+
+func TestStatementCache_Query(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := &mockPgxConn{rows: &mockPgxRows{mockSqlRows: mockSqlRows{numberOfRows: 1}}}
+	c := newTestStatementCache(10)
+
+	_, err := c.query(context.Background(), conn, "SELECT id  FROM t WHERE a = $1", []interface{}{1})
+	assert.Nil(err)
+	_, err = c.query(context.Background(), conn, "SELECT id FROM t\nWHERE a = $1", []interface{}{2})
+	assert.Nil(err)
+
+	name := statementName("SELECT id FROM t WHERE a = $1")
+	assert.Equal(map[string]string{name: "SELECT id FROM t WHERE a = $1"}, conn.prepared)
+	assert.Equal([]string{name, name}, conn.sqlReceived)
+	assert.Equal(uint64(1), c.counters.hits.Load())
+	assert.Equal(uint64(1), c.counters.misses.Load())
+	assert.Equal(1, c.size())
+}
+
+func TestStatementCache_Query_KeepsFirstRow(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := &mockPgxConn{rows: &mockPgxRows{mockSqlRows: mockSqlRows{numberOfRows: 2}}}
+	c := newTestStatementCache(10)
+
+	rows, err := c.query(context.Background(), conn, "SELECT id FROM t WHERE a = $1", []interface{}{1})
+	assert.Nil(err)
+	assert.True(rows.Next())
+	assert.True(rows.Next())
+	assert.False(rows.Next())
+}
+
+func TestStatementCache_Exec(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := &mockPgxConn{tag: "UPDATE 1"}
+	c := newTestStatementCache(10)
+
+	tag, err := c.exec(context.Background(), conn, "UPDATE t SET a = $1", []interface{}{1})
+	assert.Nil(err)
+	assert.Equal(pgx.CommandTag("UPDATE 1"), tag)
+
+	name := statementName("UPDATE t SET a = $1")
+	assert.Equal([]string{name}, conn.sqlReceived)
+}
+
+func TestStatementCache_WithoutArguments(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := &mockPgxConn{}
+	c := newTestStatementCache(10)
+
+	_, err := c.exec(context.Background(), conn, "DELETE FROM t; DELETE FROM u", nil)
+	assert.Nil(err)
+
+	assert.Nil(conn.prepared)
+	assert.Equal([]string{"DELETE FROM t; DELETE FROM u"}, conn.sqlReceived)
+	assert.Equal(uint64(0), c.counters.misses.Load())
+}
+
+func TestStatementCache_PerConnection(t *testing.T) {
+	assert := assert.New(t)
+
+	first := &mockPgxConn{}
+	second := &mockPgxConn{}
+	c := newTestStatementCache(10)
+
+	_, err := c.exec(context.Background(), first, "UPDATE t SET a = $1", []interface{}{1})
+	assert.Nil(err)
+	_, err = c.exec(context.Background(), second, "UPDATE t SET a = $1", []interface{}{1})
+	assert.Nil(err)
+
+	name := statementName("UPDATE t SET a = $1")
+	assert.Equal(map[string]string{name: "UPDATE t SET a = $1"}, first.prepared)
+	assert.Equal(map[string]string{name: "UPDATE t SET a = $1"}, second.prepared)
+	assert.Equal(uint64(2), c.counters.misses.Load())
+	assert.Equal(2, c.size())
+
+	c.forget(first)
+	assert.Equal(1, c.size())
+}
+
+func TestStatementCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := &mockPgxConn{}
+	c := newTestStatementCache(2)
+
+	for _, sql := range []string{"UPDATE t SET a = $1", "UPDATE t SET b = $1", "UPDATE t SET a = $1", "UPDATE t SET c = $1"} {
+		_, err := c.exec(context.Background(), conn, sql, []interface{}{1})
+		assert.Nil(err)
+	}
+
+	expected := map[string]string{
+		statementName("UPDATE t SET a = $1"): "UPDATE t SET a = $1",
+		statementName("UPDATE t SET c = $1"): "UPDATE t SET c = $1",
+	}
+	assert.Equal(expected, conn.prepared)
+	assert.Equal([]string{statementName("UPDATE t SET b = $1")}, conn.deallocated)
+	assert.Equal(uint64(1), c.counters.hits.Load())
+	assert.Equal(uint64(3), c.counters.misses.Load())
+	assert.Equal(2, c.size())
+}
+
+func TestStatementCache_PrepareFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := &mockPgxConn{prepareErr: errDefault}
+	c := newTestStatementCache(10)
+
+	_, err := c.exec(context.Background(), conn, "UPDATE t SET a = $1", []interface{}{1})
+	assert.Nil(err)
+
+	assert.Equal([]string{"UPDATE t SET a = $1"}, conn.sqlReceived)
+	assert.Equal(0, c.size())
+}
+
+func TestStatementCache_Exec_StalePlan(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := &mockPgxConn{}
+	c := newTestStatementCache(10)
+
+	_, err := c.exec(context.Background(), conn, "UPDATE t SET a = $1", []interface{}{1})
+	assert.Nil(err)
+
+	conn.execError = errStalePlan
+	_, err = c.exec(context.Background(), conn, "UPDATE t SET a = $1", []interface{}{2})
+	assert.Equal(errStalePlan, err)
+
+	name := statementName("UPDATE t SET a = $1")
+	assert.Equal([]string{name, name, "UPDATE t SET a = $1"}, conn.sqlReceived)
+	assert.Equal([]string{name}, conn.deallocated)
+	assert.Equal(uint64(1), c.counters.invalidations.Load())
+	assert.Equal(0, c.size())
+}
+
+func TestStatementCache_Query_StalePlan(t *testing.T) {
+	assert := assert.New(t)
+
+	stale := &mockStalePlanRows{err: errStalePlan}
+	conn := &mockPgxConn{rows: stale}
+	c := newTestStatementCache(10)
+
+	rows, err := c.query(context.Background(), conn, "SELECT id FROM t WHERE a = $1", []interface{}{1})
+	assert.Nil(err)
+	assert.Equal(stale, rows)
+
+	name := statementName("SELECT id FROM t WHERE a = $1")
+	assert.Equal([]string{name, "SELECT id FROM t WHERE a = $1"}, conn.sqlReceived)
+	assert.Equal(int32(1), stale.closeCalls.Load())
+	assert.Equal([]string{name}, conn.deallocated)
+	assert.Equal(uint64(1), c.counters.invalidations.Load())
+}
+
+func TestStatementCache_Query_OtherError(t *testing.T) {
+	assert := assert.New(t)
+
+	rows := &mockStalePlanRows{err: errDefault}
+	conn := &mockPgxConn{rows: rows}
+	c := newTestStatementCache(10)
+
+	actual, err := c.query(context.Background(), conn, "SELECT id FROM t WHERE a = $1", []interface{}{1})
+	assert.Nil(err)
+	assert.False(actual.Next())
+	assert.Nil(conn.deallocated)
+	assert.Equal(int32(0), rows.closeCalls.Load())
+}
+
+func TestPgxDbFacade_StatementCache_DeadConnection(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &mockPgxDbConn{
+		conn: &mockPgxConn{alive: true},
+	}
+	f := pgxDbFacadeImpl{
+		pool: m,
+	}
+	f.useStatementCache(newTestStatementCache(10))
+
+	_, err := f.Exec(context.Background(), "UPDATE t SET a = $1", 1)
+	assert.Nil(err)
+	assert.Equal(1, f.statementCacheSize())
+
+	m.conn.alive = false
+	_, err = f.Exec(context.Background(), "UPDATE t SET a = $1", 1)
+	assert.Nil(err)
+	assert.Equal(0, f.statementCacheSize())
+	assert.Equal(2, m.releaseCalled)
+}
+
+func TestPostgresDatabase_StatementCacheStats(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	config.DbStatementCacheSize = 10
+	conn := &mockPgxConn{tag: "UPDATE 1", alive: true}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return &pgxDbFacadeImpl{pool: &mockPgxDbConn{conn: conn}}, nil
+	}
+
+	db := NewPostgresDatabase(config)
+	assert.Nil(db.Connect(context.Background()))
+
+	q := queryImpl{sqlCode: "UPDATE t SET a = $1", args: []interface{}{1}}
+	assert.Nil(db.Execute(context.Background(), q).Err())
+	assert.Nil(db.Execute(context.Background(), q).Err())
+
+	expected := StatementCacheStats{Hits: 1, Misses: 1, Size: 1}
+	assert.Equal(expected, db.(StatementCacheReporter).StatementCacheStats())
+}
+
+func TestPostgresDatabase_StatementCache_Disabled(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig
+	conn := &mockPgxConn{tag: "UPDATE 1", alive: true}
+	config.creationFunc = func(config pgx.ConnPoolConfig) (DriverPool, error) {
+		return &pgxDbFacadeImpl{pool: &mockPgxDbConn{conn: conn}}, nil
+	}
+
+	db := NewPostgresDatabase(config)
+	assert.Nil(db.Connect(context.Background()))
+
+	q := queryImpl{sqlCode: "UPDATE t SET a = $1", args: []interface{}{1}}
+	assert.Nil(db.Execute(context.Background(), q).Err())
+
+	assert.Nil(conn.prepared)
+	assert.Equal(StatementCacheStats{}, db.(StatementCacheReporter).StatementCacheStats())
+}
+
+func TestRoutingDatabase_StatementCacheStats(t *testing.T) {
+	assert := assert.New(t)
+
+	primary := &mockStatementCacheDb{stats: StatementCacheStats{Hits: 1, Misses: 2, Size: 3}}
+	replica := &mockStatementCacheDb{stats: StatementCacheStats{Hits: 4, Invalidations: 5, Size: 6}}
+	db := newRoutingDatabase(primary, []Database{replica, &mockDb{}}, time.Minute)
+
+	expected := StatementCacheStats{Hits: 5, Misses: 2, Invalidations: 5, Size: 9}
+	assert.Equal(expected, db.StatementCacheStats())
+}
+
+type mockStalePlanRows struct {
+	mockPgxRows
+	err error
+}
+
+func (m *mockStalePlanRows) Err() error {
+	return m.err
+}
+
+type mockStatementCacheDb struct {
+	mockDb
+	stats StatementCacheStats
+}
+
+func (m *mockStatementCacheDb) StatementCacheStats() StatementCacheStats {
+	return m.stats
+}
@@ -0,0 +1,320 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+const structTag = "db"
+
+// Options of the tag, e.g. `db:"id,omitempty"`:
+//   - omitempty: the zero value is not inserted nor updated, so that
+//     the database can generate it.
+//   - readonly: the column is only read, e.g. a creation timestamp.
+const omitEmptyTagOption = "omitempty"
+const readOnlyTagOption = "readonly"
+
+// Maps the fields of a struct tagged with `db:"column"` to columns:
+// untagged fields and fields tagged with `db:"-"` are ignored while
+// untagged embedded structs are flattened.
+type StructMapper[T any] interface {
+	Columns() []string
+
+	AddProps(qb SelectQueryBuilder) error
+	AddReturning(qb ReturningQueryBuilder) error
+	AddElements(qb InsertQueryBuilder, value T) error
+	// Updates the columns, or all the writable ones if none is given.
+	AddUpdates(qb UpdateQueryBuilder, value T, columns ...string) error
+
+	NewParser() StructParser[T]
+}
+
+type ReturningQueryBuilder interface {
+	AddReturning(column string) error
+}
+
+type StructParser[T any] interface {
+	RowParser
+
+	Value() T
+	Values() []T
+}
+
+type structField struct {
+	column    string
+	index     []int
+	omitEmpty bool
+	readOnly  bool
+}
+
+type structMapper[T any] struct {
+	fields  []structField
+	columns map[string]int
+}
+
+type structParser[T any] struct {
+	mapper *structMapper[T]
+	// Index of the field for each column of the rows, resolved when
+	// scanning the first one.
+	order  []int
+	values []T
+}
+
+func NewStructMapper[T any]() (StructMapper[T], error) {
+	var zero T
+	t := reflect.TypeOf(zero)
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.WrapCode(errors.Newf("%v is not a struct", t), errors.ErrInvalidSqlStructMapping)
+	}
+
+	m := &structMapper[T]{
+		columns: make(map[string]int),
+	}
+	if err := m.addFields(t, nil); err != nil {
+		return nil, err
+	}
+	if len(m.fields) == 0 {
+		return nil, errors.WrapCode(errors.Newf("no column in %v", t), errors.ErrInvalidSqlStructMapping)
+	}
+
+	return m, nil
+}
+
+func MustNewStructMapper[T any]() StructMapper[T] {
+	m, err := NewStructMapper[T]()
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+func (m *structMapper[T]) addFields(t reflect.Type, index []int) error {
+	for id := 0; id < t.NumField(); id++ {
+		field := t.Field(id)
+		tag, tagged := field.Tag.Lookup(structTag)
+
+		fieldIndex := append(append([]int{}, index...), id)
+		if !tagged && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := m.addFields(field.Type, fieldIndex); err != nil {
+				return err
+			}
+			continue
+		}
+		if !tagged || tag == "-" {
+			continue
+		}
+
+		options := strings.Split(tag, ",")
+		f := structField{
+			column: options[0],
+			index:  fieldIndex,
+		}
+		if len(f.column) == 0 {
+			return errors.WrapCode(errors.Newf("no column for field %s", field.Name), errors.ErrInvalidSqlStructMapping)
+		}
+		if !field.IsExported() {
+			return errors.WrapCode(errors.Newf("field %s of column %s is not exported", field.Name, f.column), errors.ErrInvalidSqlStructMapping)
+		}
+		if _, ok := m.columns[f.column]; ok {
+			return errors.WrapCode(errors.Newf("column %s is mapped more than once", f.column), errors.ErrInvalidSqlStructMapping)
+		}
+
+		for _, option := range options[1:] {
+			switch option {
+			case omitEmptyTagOption:
+				f.omitEmpty = true
+			case readOnlyTagOption:
+				f.readOnly = true
+			default:
+				return errors.WrapCode(errors.Newf("unknown option %q for column %s", option, f.column), errors.ErrInvalidSqlStructMapping)
+			}
+		}
+
+		m.columns[f.column] = len(m.fields)
+		m.fields = append(m.fields, f)
+	}
+
+	return nil
+}
+
+func (m *structMapper[T]) Columns() []string {
+	columns := make([]string, 0, len(m.fields))
+	for _, f := range m.fields {
+		columns = append(columns, f.column)
+	}
+
+	return columns
+}
+
+func (m *structMapper[T]) AddProps(qb SelectQueryBuilder) error {
+	for _, f := range m.fields {
+		if err := qb.AddProp(f.column); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *structMapper[T]) AddReturning(qb ReturningQueryBuilder) error {
+	for _, f := range m.fields {
+		if err := qb.AddReturning(f.column); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *structMapper[T]) AddElements(qb InsertQueryBuilder, value T) error {
+	v := reflect.ValueOf(value)
+	for _, f := range m.fields {
+		if !f.writable(v) {
+			continue
+		}
+		if err := qb.AddElement(f.column, v.FieldByIndex(f.index).Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *structMapper[T]) AddUpdates(qb UpdateQueryBuilder, value T, columns ...string) error {
+	v := reflect.ValueOf(value)
+	if len(columns) == 0 {
+		for _, f := range m.fields {
+			if !f.writable(v) {
+				continue
+			}
+			if err := qb.AddUpdate(f.column, v.FieldByIndex(f.index).Interface()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, column := range columns {
+		id, ok := m.columns[column]
+		if !ok {
+			return errors.WrapCode(errors.Newf("column %s is not mapped", column), errors.ErrInvalidSqlColumn)
+		}
+
+		f := m.fields[id]
+		if f.readOnly {
+			return errors.WrapCode(errors.Newf("column %s is read only", column), errors.ErrInvalidSqlColumn)
+		}
+		if err := qb.AddUpdate(f.column, v.FieldByIndex(f.index).Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *structMapper[T]) NewParser() StructParser[T] {
+	return &structParser[T]{mapper: m}
+}
+
+func (f structField) writable(v reflect.Value) bool {
+	if f.readOnly {
+		return false
+	}
+
+	return !f.omitEmpty || !v.FieldByIndex(f.index).IsZero()
+}
+
+func (p *structParser[T]) ScanRow(row Scannable) error {
+	if p.order == nil {
+		order, err := p.mapper.resolve(row)
+		if err != nil {
+			return err
+		}
+		p.order = order
+	}
+
+	var value T
+	v := reflect.ValueOf(&value).Elem()
+
+	dest := make([]interface{}, 0, len(p.order))
+	for _, id := range p.order {
+		dest = append(dest, v.FieldByIndex(p.mapper.fields[id].index).Addr().Interface())
+	}
+
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+
+	p.values = append(p.values, value)
+	return nil
+}
+
+// The last value scanned, the zero value if none.
+func (p *structParser[T]) Value() T {
+	if len(p.values) == 0 {
+		var zero T
+		return zero
+	}
+
+	return p.values[len(p.values)-1]
+}
+
+func (p *structParser[T]) Values() []T {
+	return p.values
+}
+
+// Rows which don't describe their columns are assumed to return them
+// in the order of the fields.
+func (m *structMapper[T]) resolve(row Scannable) ([]int, error) {
+	var columns []string
+	if describer, ok := row.(columnsDescriber); ok {
+		columns = describer.Columns()
+	}
+
+	if columns == nil {
+		order := make([]int, 0, len(m.fields))
+		for id := range m.fields {
+			order = append(order, id)
+		}
+		return order, nil
+	}
+
+	var unexpected []string
+	found := make(map[int]bool)
+	order := make([]int, 0, len(columns))
+	for _, column := range columns {
+		id, ok := m.columns[column]
+		if !ok || found[id] {
+			unexpected = append(unexpected, column)
+			continue
+		}
+
+		found[id] = true
+		order = append(order, id)
+	}
+
+	var missing []string
+	for id, f := range m.fields {
+		if !found[id] {
+			missing = append(missing, f.column)
+		}
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing column(s) %v", missing))
+	}
+	if len(unexpected) > 0 {
+		problems = append(problems, fmt.Sprintf("unexpected column(s) %v", unexpected))
+	}
+	if len(problems) > 0 {
+		return nil, errors.WrapCode(errors.Newf("%s", strings.Join(problems, ", ")), errors.ErrMismatchedSqlColumns)
+	}
+
+	return order, nil
+}
@@ -0,0 +1,241 @@
+package db
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type structMapperTestBase struct {
+	Id uuid.UUID `db:"id,omitempty"`
+}
+
+type structMapperTestPlayer struct {
+	structMapperTestBase
+	Name      string     `db:"name"`
+	Level     int        `db:"level"`
+	CreatedAt *time.Time `db:"created_at,readonly"`
+	Score     float64    `db:"-"`
+	Ignored   string
+}
+
+var defaultTestPlayerId = uuid.MustParse("08ce96a3-3430-48a8-a3b2-b1c987a207ca")
+
+func TestNewStructMapper_Invalid(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewStructMapper[int]()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlStructMapping))
+	_, err = NewStructMapper[*structMapperTestPlayer]()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlStructMapping))
+	_, err = NewStructMapper[struct{ Name string }]()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlStructMapping))
+	_, err = NewStructMapper[struct {
+		Name string `db:",readonly"`
+	}]()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlStructMapping))
+	_, err = NewStructMapper[struct {
+		name string `db:"name"`
+	}]()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlStructMapping))
+	_, err = NewStructMapper[struct {
+		Name  string `db:"name"`
+		Alias string `db:"name"`
+	}]()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlStructMapping))
+	_, err = NewStructMapper[struct {
+		Name string `db:"name,unknown"`
+	}]()
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlStructMapping))
+}
+
+func TestMustNewStructMapper(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NotPanics(func() { MustNewStructMapper[structMapperTestPlayer]() })
+	assert.Panics(func() { MustNewStructMapper[int]() })
+}
+
+func TestStructMapper_Columns(t *testing.T) {
+	assert := assert.New(t)
+
+	m := MustNewStructMapper[structMapperTestPlayer]()
+	assert.Equal([]string{"id", "name", "level", "created_at"}, m.Columns())
+}
+
+func TestStructMapper_AddProps(t *testing.T) {
+	assert := assert.New(t)
+
+	m := MustNewStructMapper[structMapperTestPlayer]()
+	qb := NewSelectQueryBuilder()
+	qb.SetTable("players")
+	assert.Nil(m.AddProps(qb))
+
+	query, err := qb.Build()
+	assert.Nil(err)
+	assert.Equal("SELECT id, name, level, created_at FROM players", query.ToSql())
+
+	assert.NotNil(m.AddProps(qb))
+}
+
+func TestStructMapper_AddElements(t *testing.T) {
+	assert := assert.New(t)
+
+	m := MustNewStructMapper[structMapperTestPlayer]()
+	now := time.Now()
+	player := structMapperTestPlayer{Name: "name", Level: 2, CreatedAt: &now}
+
+	qb := NewInsertQueryBuilder()
+	qb.SetTable("players")
+	assert.Nil(m.AddElements(qb, player))
+	assert.Nil(m.AddReturning(qb))
+
+	query, err := qb.Build()
+	assert.Nil(err)
+	assert.Equal("INSERT INTO players (name, level) VALUES ($1, $2) RETURNING id, name, level, created_at", query.ToSql())
+	assert.Equal([]interface{}{"name", 2}, query.Args())
+
+	player.Id = defaultTestPlayerId
+	qb = NewInsertQueryBuilder()
+	qb.SetTable("players")
+	assert.Nil(m.AddElements(qb, player))
+
+	query, err = qb.Build()
+	assert.Nil(err)
+	assert.Equal("INSERT INTO players (id, name, level) VALUES ($1, $2, $3)", query.ToSql())
+	assert.Equal([]interface{}{defaultTestPlayerId.String(), "name", 2}, query.Args())
+}
+
+func TestStructMapper_AddUpdates(t *testing.T) {
+	assert := assert.New(t)
+
+	m := MustNewStructMapper[structMapperTestPlayer]()
+	player := structMapperTestPlayer{Name: "name", Level: 2}
+
+	qb := NewUpdateQueryBuilder()
+	qb.SetTable("players")
+	assert.Nil(m.AddUpdates(qb, player))
+
+	query, err := qb.Build()
+	assert.Nil(err)
+	assert.Equal("UPDATE players SET name = $1, level = $2", query.ToSql())
+
+	qb = NewUpdateQueryBuilder()
+	qb.SetTable("players")
+	assert.Nil(m.AddUpdates(qb, player, "level"))
+
+	query, err = qb.Build()
+	assert.Nil(err)
+	assert.Equal("UPDATE players SET level = $1", query.ToSql())
+	assert.Equal([]interface{}{2}, query.Args())
+}
+
+func TestStructMapper_AddUpdates_InvalidColumns(t *testing.T) {
+	assert := assert.New(t)
+
+	m := MustNewStructMapper[structMapperTestPlayer]()
+
+	err := m.AddUpdates(NewUpdateQueryBuilder(), structMapperTestPlayer{}, "unknown")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlColumn))
+	err = m.AddUpdates(NewUpdateQueryBuilder(), structMapperTestPlayer{}, "created_at")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlColumn))
+	err = m.AddUpdates(NewUpdateQueryBuilder(), structMapperTestPlayer{}, "name", "name")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDuplicatedSqlColumn))
+}
+
+func TestStructParser_ScanRow(t *testing.T) {
+	assert := assert.New(t)
+
+	p := MustNewStructMapper[structMapperTestPlayer]().NewParser()
+	assert.Equal(structMapperTestPlayer{}, p.Value())
+
+	row := &mockStructScannable{
+		values: []interface{}{defaultTestPlayerId.String(), "name", 2, nil},
+	}
+	assert.Nil(p.ScanRow(row))
+	row.values[1] = "other"
+	assert.Nil(p.ScanRow(row))
+
+	expected := []structMapperTestPlayer{
+		{structMapperTestBase: structMapperTestBase{Id: defaultTestPlayerId}, Name: "name", Level: 2},
+		{structMapperTestBase: structMapperTestBase{Id: defaultTestPlayerId}, Name: "other", Level: 2},
+	}
+	assert.Equal(expected, p.Values())
+	assert.Equal(expected[1], p.Value())
+}
+
+func TestStructParser_ScanRow_ColumnsOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	p := MustNewStructMapper[structMapperTestPlayer]().NewParser()
+	row := &mockStructScannable{
+		columns: []string{"level", "created_at", "name", "id"},
+		values:  []interface{}{2, nil, "name", defaultTestPlayerId.String()},
+	}
+
+	assert.Nil(p.ScanRow(row))
+	assert.Equal("name", p.Value().Name)
+	assert.Equal(2, p.Value().Level)
+	assert.Equal(defaultTestPlayerId, p.Value().Id)
+}
+
+func TestStructParser_ScanRow_MismatchedColumns(t *testing.T) {
+	assert := assert.New(t)
+
+	p := MustNewStructMapper[structMapperTestPlayer]().NewParser()
+	row := &mockStructScannable{
+		columns: []string{"id", "name", "name", "score"},
+	}
+
+	err := p.ScanRow(row)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrMismatchedSqlColumns))
+	assert.Contains(err.Error(), "missing column(s) [level created_at], unexpected column(s) [name score]")
+	assert.Equal(0, row.scanCalls)
+}
+
+func TestStructParser_ScanRow_Error(t *testing.T) {
+	assert := assert.New(t)
+
+	p := MustNewStructMapper[structMapperTestPlayer]().NewParser()
+	row := &mockStructScannable{err: errDefault}
+
+	assert.Equal(errDefault, p.ScanRow(row))
+	assert.Nil(p.Values())
+}
+
+type mockStructScannable struct {
+	columns   []string
+	values    []interface{}
+	err       error
+	scanCalls int
+}
+
+func (m *mockStructScannable) Columns() []string {
+	return m.columns
+}
+
+func (m *mockStructScannable) Scan(dest ...interface{}) error {
+	m.scanCalls++
+	if m.err != nil {
+		return m.err
+	}
+
+	// The scanners parse their value and the NULL values leave the
+	// pointers nil.
+	for id, value := range m.values {
+		if scanner, ok := dest[id].(sql.Scanner); ok {
+			if err := scanner.Scan(value); err != nil {
+				return err
+			}
+		} else if value != nil {
+			reflect.ValueOf(dest[id]).Elem().Set(reflect.ValueOf(value))
+		}
+	}
+
+	return nil
+}
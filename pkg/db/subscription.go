@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
+)
+
+const subscriptionBufferSize = 16
+
+// The function is used rather than the NOTIFY command which does not
+// accept parameters.
+const notifySql = "SELECT pg_notify($1, $2)"
+
+// https://www.postgresql.org/docs/current/sql-notify.html
+type Notification struct {
+	Channel string
+	Payload string
+}
+
+// The notifications are received until the subscription is closed or
+// its context is done: the channel is closed afterwards. The ones sent
+// while the connection is lost are not received.
+type Subscription interface {
+	Notifications() <-chan Notification
+	Close()
+}
+
+type listenerCreationFunc func(ctx context.Context) (DriverListener, error)
+
+type subscriptionImpl struct {
+	channel       string
+	connect       listenerCreationFunc
+	minBackoff    time.Duration
+	maxBackoff    time.Duration
+	notifications chan Notification
+	cancel        context.CancelFunc
+	done          chan struct{}
+}
+
+// The first connection is made before returning so that a subscription
+// which can't be made is reported to the caller.
+func newSubscription(ctx context.Context, channel string, conf Config, connect listenerCreationFunc) (Subscription, error) {
+	listener, err := listen(ctx, connect, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &subscriptionImpl{
+		channel:       channel,
+		connect:       connect,
+		notifications: make(chan Notification, subscriptionBufferSize),
+		done:          make(chan struct{}),
+	}
+	s.minBackoff, s.maxBackoff = reconnectBackoff(conf)
+
+	subCtx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	go s.run(subCtx, listener)
+
+	logger.ScopedInfof(ctx, "subscribed to channel %s", channel)
+
+	return s, nil
+}
+
+func (s *subscriptionImpl) Notifications() <-chan Notification {
+	return s.notifications
+}
+
+func (s *subscriptionImpl) Close() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *subscriptionImpl) run(ctx context.Context, listener DriverListener) {
+	defer close(s.done)
+	defer close(s.notifications)
+
+	for listener != nil {
+		err := s.forward(ctx, listener)
+		if ctx.Err() != nil {
+			return
+		}
+
+		logger.Warnf("lost subscription to channel %s (err: %v)", s.channel, err)
+		listener = s.reconnect(ctx)
+	}
+}
+
+func (s *subscriptionImpl) forward(ctx context.Context, listener DriverListener) error {
+	defer listener.Close()
+
+	for {
+		n, err := listener.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case s.notifications <- Notification{Channel: n.Channel, Payload: n.Payload}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Listening again is needed as the channels are attached to the
+// connection on the server side.
+func (s *subscriptionImpl) reconnect(ctx context.Context) DriverListener {
+	backoff := s.minBackoff
+
+	for {
+		listener, err := listen(ctx, s.connect, s.channel)
+		if err == nil {
+			logger.Infof("subscribed again to channel %s", s.channel)
+			return listener
+		}
+
+		logger.Warnf("failed to subscribe again to channel %s, retrying in %v (err: %v)", s.channel, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+func listen(ctx context.Context, connect listenerCreationFunc, channel string) (DriverListener, error) {
+	listener, err := connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := listener.Listen(channel); err != nil {
+		listener.Close()
+		return nil, errors.WrapCode(err, errors.ErrDbSubscriptionFailed)
+	}
+
+	return listener, nil
+}
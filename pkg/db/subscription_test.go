@@ -0,0 +1,207 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockListenerFactory struct {
+	lock      sync.Mutex
+	listeners []*mockPgxListener
+	failures  atomic.Int32
+	listenErr error
+}
+
+func (f *mockListenerFactory) create(config pgx.ConnConfig) (DriverListener, error) {
+	if f.failures.Load() > 0 {
+		f.failures.Add(-1)
+		return nil, errDefault
+	}
+
+	l := &mockPgxListener{
+		listenErr:     f.listenErr,
+		notifications: make(chan *pgx.Notification, 1),
+		errs:          make(chan error, 1),
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.listeners = append(f.listeners, l)
+	return l, nil
+}
+
+func (f *mockListenerFactory) listener(id int) *mockPgxListener {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if id >= len(f.listeners) {
+		return nil
+	}
+	return f.listeners[id]
+}
+
+func newSubscribedTestDatabase(t *testing.T, factory *mockListenerFactory) (Database, Subscription) {
+	config := testConfig
+	config.DbReconnectMinBackoff = time.Millisecond
+	config.DbReconnectMaxBackoff = 5 * time.Millisecond
+	config.listenerFunc = factory.create
+
+	db := NewPostgresDatabase(config)
+	sub, err := db.Subscribe(context.Background(), "channel")
+	assert.Nil(t, err)
+	t.Cleanup(sub.Close)
+
+	return db, sub
+}
+
+func receiveNotification(t *testing.T, sub Subscription) Notification {
+	select {
+	case n := <-sub.Notifications():
+		return n
+	case <-time.After(time.Second):
+		t.Fatal("no notification received")
+		return Notification{}
+	}
+}
+
+func TestPostgresDatabase_Subscribe_InvalidChannel(t *testing.T) {
+	assert := assert.New(t)
+
+	db := NewPostgresDatabase(testConfig)
+	_, err := db.Subscribe(context.Background(), "")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlNotificationChannel))
+}
+
+func TestPostgresDatabase_Subscribe_ConnectionFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	factory := &mockListenerFactory{}
+	factory.failures.Store(1)
+	config := testConfig
+	config.listenerFunc = factory.create
+
+	db := NewPostgresDatabase(config)
+	_, err := db.Subscribe(context.Background(), "channel")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbConnectionFailed))
+}
+
+func TestPostgresDatabase_Subscribe_ListenFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	factory := &mockListenerFactory{listenErr: errDefault}
+	config := testConfig
+	config.listenerFunc = factory.create
+
+	db := NewPostgresDatabase(config)
+	_, err := db.Subscribe(context.Background(), "channel")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDbSubscriptionFailed))
+	assert.Equal(int32(1), factory.listener(0).closeCalls.Load())
+}
+
+func TestPostgresDatabase_Subscribe(t *testing.T) {
+	assert := assert.New(t)
+
+	factory := &mockListenerFactory{}
+	_, sub := newSubscribedTestDatabase(t, factory)
+
+	l := factory.listener(0)
+	assert.Equal([]string{"channel"}, l.listened())
+
+	l.notifications <- &pgx.Notification{Channel: "channel", Payload: "payload"}
+	assert.Equal(Notification{Channel: "channel", Payload: "payload"}, receiveNotification(t, sub))
+}
+
+func TestPostgresDatabase_Subscribe_Close(t *testing.T) {
+	assert := assert.New(t)
+
+	factory := &mockListenerFactory{}
+	_, sub := newSubscribedTestDatabase(t, factory)
+
+	sub.Close()
+	_, ok := <-sub.Notifications()
+	assert.False(ok)
+	assert.Equal(int32(1), factory.listener(0).closeCalls.Load())
+}
+
+func TestPostgresDatabase_Subscribe_ContextDone(t *testing.T) {
+	assert := assert.New(t)
+
+	factory := &mockListenerFactory{}
+	config := testConfig
+	config.listenerFunc = factory.create
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub, err := NewPostgresDatabase(config).Subscribe(ctx, "channel")
+	assert.Nil(err)
+
+	cancel()
+	select {
+	case _, ok := <-sub.Notifications():
+		assert.False(ok)
+	case <-time.After(time.Second):
+		t.Fatal("subscription not closed")
+	}
+}
+
+func TestPostgresDatabase_Subscribe_ListensAgainAfterReconnection(t *testing.T) {
+	assert := assert.New(t)
+
+	factory := &mockListenerFactory{}
+	_, sub := newSubscribedTestDatabase(t, factory)
+
+	factory.failures.Store(2)
+	factory.listener(0).errs <- pgx.ErrDeadConn
+
+	assert.Eventually(func() bool {
+		return factory.listener(1) != nil
+	}, time.Second, time.Millisecond)
+	assert.Equal(int32(1), factory.listener(0).closeCalls.Load())
+
+	l := factory.listener(1)
+	assert.Equal([]string{"channel"}, l.listened())
+	l.notifications <- &pgx.Notification{Channel: "channel", Payload: "again"}
+	assert.Equal("again", receiveNotification(t, sub).Payload)
+}
+
+type mockPgxListener struct {
+	lock          sync.Mutex
+	channels      []string
+	listenErr     error
+	notifications chan *pgx.Notification
+	errs          chan error
+	closeCalls    atomic.Int32
+}
+
+func (m *mockPgxListener) Listen(channel string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.channels = append(m.channels, channel)
+	return m.listenErr
+}
+
+func (m *mockPgxListener) WaitForNotification(ctx context.Context) (*pgx.Notification, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case n := <-m.notifications:
+		return n, nil
+	case err := <-m.errs:
+		return nil, err
+	}
+}
+
+func (m *mockPgxListener) Close() error {
+	m.closeCalls.Add(1)
+	return nil
+}
+
+func (m *mockPgxListener) listened() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.channels
+}
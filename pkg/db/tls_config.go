@@ -0,0 +1,130 @@
+package db
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/jackc/pgx"
+)
+
+// https://www.postgresql.org/docs/current/libpq-ssl.html#LIBPQ-SSL-SSLMODE-STATEMENTS
+const (
+	sslModeDisable    = "disable"
+	sslModeAllow      = "allow"
+	sslModePrefer     = "prefer"
+	sslModeRequire    = "require"
+	sslModeVerifyCa   = "verify-ca"
+	sslModeVerifyFull = "verify-full"
+)
+
+func validSslMode(mode string) bool {
+	switch mode {
+	case "", sslModeDisable, sslModeAllow, sslModePrefer, sslModeRequire, sslModeVerifyCa, sslModeVerifyFull:
+		return true
+	default:
+		return false
+	}
+}
+
+// Mirrors what libpq does: the modes which don't verify the server
+// still encrypt the connection and present the client certificate.
+// Like libpq, requiring TLS with a root certificate verifies it.
+func configureTls(conf Config, out *pgx.ConnConfig) error {
+	mode := conf.DbSslMode
+	if mode == "" || mode == sslModeDisable {
+		return nil
+	}
+	if !validSslMode(mode) {
+		return errors.WrapCode(errors.Newf("invalid sslmode %q", mode), errors.ErrInvalidDbConfig)
+	}
+	if mode == sslModeRequire && len(conf.DbSslRootCert) > 0 {
+		mode = sslModeVerifyCa
+	}
+
+	tlsConf := &tls.Config{}
+
+	if len(conf.DbSslCert) > 0 || len(conf.DbSslKey) > 0 {
+		cert, err := tls.LoadX509KeyPair(conf.DbSslCert, conf.DbSslKey)
+		if err != nil {
+			return errors.WrapCode(err, errors.ErrInvalidDbConfig)
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+
+	var roots *x509.CertPool
+	if len(conf.DbSslRootCert) > 0 {
+		var err error
+		if roots, err = loadRootCertificates(conf.DbSslRootCert); err != nil {
+			return err
+		}
+	}
+
+	switch mode {
+	case sslModeAllow:
+		tlsConf.InsecureSkipVerify = true
+		out.UseFallbackTLS = true
+		out.FallbackTLSConfig = tlsConf
+		return nil
+	case sslModePrefer:
+		tlsConf.InsecureSkipVerify = true
+		out.UseFallbackTLS = true
+	case sslModeRequire:
+		tlsConf.InsecureSkipVerify = true
+	case sslModeVerifyCa:
+		// The standard verification always checks the host name.
+		tlsConf.InsecureSkipVerify = true
+		tlsConf.VerifyPeerCertificate = verifyCertificateChain(roots)
+	case sslModeVerifyFull:
+		tlsConf.RootCAs = roots
+		tlsConf.ServerName = conf.DbHost
+	}
+
+	out.TLSConfig = tlsConf
+	return nil
+}
+
+func loadRootCertificates(path string) (*x509.CertPool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WrapCode(err, errors.ErrInvalidDbConfig)
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(content) {
+		return nil, errors.WrapCode(errors.Newf("no certificate found in %s", path), errors.ErrInvalidDbConfig)
+	}
+
+	return roots, nil
+}
+
+func verifyCertificateChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.Newf("no certificate presented by the server")
+		}
+
+		opts := x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: x509.NewCertPool(),
+		}
+
+		var leaf *x509.Certificate
+		for id, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+
+			if id == 0 {
+				leaf = cert
+			} else {
+				opts.Intermediates.AddCert(cert)
+			}
+		}
+
+		_, err := leaf.Verify(opts)
+		return err
+	}
+}
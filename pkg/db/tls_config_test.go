@@ -0,0 +1,194 @@
+package db
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCertificate(t *testing.T, name string, parent *testCertificate) testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	issuer, signer := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		issuer, signer = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+
+	return testCertificate{cert: cert, key: key, der: der}
+}
+
+func (c testCertificate) write(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der})
+	assert.Nil(t, os.WriteFile(certPath, certPem, 0600))
+
+	keyDer, err := x509.MarshalECPrivateKey(c.key)
+	assert.Nil(t, err)
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	assert.Nil(t, os.WriteFile(keyPath, keyPem, 0600))
+
+	return certPath, keyPath
+}
+
+func TestConfigureTls_Disabled(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, mode := range []string{"", "disable"} {
+		conf := testConfig
+		conf.DbSslMode = mode
+
+		var actual pgx.ConnConfig
+		assert.Nil(configureTls(conf, &actual))
+		assert.Nil(actual.TLSConfig)
+		assert.False(actual.UseFallbackTLS)
+	}
+}
+
+func TestConfigureTls_InvalidMode(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := testConfig
+	conf.DbSslMode = "sometimes"
+
+	err := configureTls(conf, &pgx.ConnConfig{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidDbConfig))
+}
+
+func TestConfigureTls_Fallback(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := testConfig
+	conf.DbSslMode = "allow"
+	var actual pgx.ConnConfig
+	assert.Nil(configureTls(conf, &actual))
+	assert.Nil(actual.TLSConfig)
+	assert.True(actual.UseFallbackTLS)
+	assert.True(actual.FallbackTLSConfig.InsecureSkipVerify)
+
+	conf.DbSslMode = "prefer"
+	actual = pgx.ConnConfig{}
+	assert.Nil(configureTls(conf, &actual))
+	assert.True(actual.TLSConfig.InsecureSkipVerify)
+	assert.True(actual.UseFallbackTLS)
+	assert.Nil(actual.FallbackTLSConfig)
+}
+
+func TestConfigureTls_Require(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := testConfig
+	conf.DbSslMode = "require"
+
+	var actual pgx.ConnConfig
+	assert.Nil(configureTls(conf, &actual))
+	assert.True(actual.TLSConfig.InsecureSkipVerify)
+	assert.Nil(actual.TLSConfig.VerifyPeerCertificate)
+	assert.False(actual.UseFallbackTLS)
+
+	ca := newTestCertificate(t, "ca", nil)
+	conf.DbSslRootCert, _ = ca.write(t)
+	assert.Nil(configureTls(conf, &actual))
+	assert.NotNil(actual.TLSConfig.VerifyPeerCertificate)
+}
+
+func TestConfigureTls_VerifyFull(t *testing.T) {
+	assert := assert.New(t)
+
+	ca := newTestCertificate(t, "ca", nil)
+	client := newTestCertificate(t, "client", &ca)
+	conf := testConfig
+	conf.DbSslMode = "verify-full"
+	conf.DbSslRootCert, _ = ca.write(t)
+	conf.DbSslCert, conf.DbSslKey = client.write(t)
+
+	var actual pgx.ConnConfig
+	assert.Nil(configureTls(conf, &actual))
+	assert.False(actual.TLSConfig.InsecureSkipVerify)
+	assert.Equal("host", actual.TLSConfig.ServerName)
+	assert.NotNil(actual.TLSConfig.RootCAs)
+	assert.Equal(1, len(actual.TLSConfig.Certificates))
+}
+
+func TestConfigureTls_VerifyCa(t *testing.T) {
+	assert := assert.New(t)
+
+	ca := newTestCertificate(t, "ca", nil)
+	conf := testConfig
+	conf.DbSslMode = "verify-ca"
+	conf.DbSslRootCert, _ = ca.write(t)
+
+	var actual pgx.ConnConfig
+	assert.Nil(configureTls(conf, &actual))
+	verify := actual.TLSConfig.VerifyPeerCertificate
+
+	// The name of the server does not matter, only its issuer.
+	server := newTestCertificate(t, "another-host", &ca)
+	assert.Nil(verify([][]byte{server.der}, nil))
+
+	other := newTestCertificate(t, "other-ca", nil)
+	untrusted := newTestCertificate(t, "host", &other)
+	assert.NotNil(verify([][]byte{untrusted.der}, nil))
+	assert.NotNil(verify(nil, nil))
+}
+
+func TestConfigureTls_InvalidFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := testConfig
+	conf.DbSslMode = "verify-full"
+	conf.DbSslRootCert = filepath.Join(t.TempDir(), "missing.pem")
+	err := configureTls(conf, &pgx.ConnConfig{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidDbConfig))
+
+	notPem := filepath.Join(t.TempDir(), "ca.pem")
+	assert.Nil(os.WriteFile(notPem, []byte("not a certificate"), 0600))
+	conf.DbSslRootCert = notPem
+	err = configureTls(conf, &pgx.ConnConfig{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidDbConfig))
+
+	conf.DbSslRootCert = ""
+	conf.DbSslCert = notPem
+	conf.DbSslKey = notPem
+	err = configureTls(conf, &pgx.ConnConfig{})
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidDbConfig))
+}
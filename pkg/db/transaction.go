@@ -0,0 +1,14 @@
+package db
+
+import "context"
+
+// https://www.postgresql.org/docs/current/tutorial-transactions.html
+type Transaction interface {
+	Query(ctx context.Context, query Query) Rows
+	Execute(ctx context.Context, query Query) Result
+	CopyFrom(ctx context.Context, copy CopyFrom) Result
+
+	Begin(ctx context.Context) (Transaction, error)
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/jackc/pgx"
+)
+
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	connectionExceptionClass = "08"
+	serializationFailure     = "40001"
+	deadlockDetected         = "40P01"
+	adminShutdown            = "57P01"
+	crashShutdown            = "57P02"
+	cannotConnectNow         = "57P03"
+)
+
+// The cause of the errors produced by this package is the error
+// returned by pgx.
+func errorCause(err error) error {
+	for {
+		cause := errors.Unwrap(err)
+		if cause == nil {
+			return err
+		}
+		err = cause
+	}
+}
+
+// Connection errors mean that the database can't be reached: they
+// don't tell anything about the query itself. The errors caused by
+// the caller, such as a cancelled context, are not considered.
+func isConnectionError(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	cause := errorCause(err)
+	if pgErr, ok := cause.(pgx.PgError); ok {
+		switch pgErr.Code {
+		case adminShutdown, crashShutdown, cannotConnectNow:
+			return true
+		default:
+			return strings.HasPrefix(pgErr.Code, connectionExceptionClass)
+		}
+	}
+
+	switch cause {
+	case io.EOF, io.ErrUnexpectedEOF, pgx.ErrDeadConn, pgx.ErrClosedPool:
+		return true
+	}
+
+	_, isNetError := cause.(net.Error)
+	return isNetError
+}
+
+// Transient errors are expected to go away when the query is tried
+// again: this is the case of the connection errors but also of the
+// conflicts between concurrent transactions.
+func isTransientError(ctx context.Context, err error) bool {
+	if isConnectionError(ctx, err) {
+		return true
+	}
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	pgErr, ok := errorCause(err).(pgx.PgError)
+	if !ok {
+		return false
+	}
+
+	return pgErr.Code == serializationFailure || pgErr.Code == deadlockDetected
+}
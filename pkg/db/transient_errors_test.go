@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsConnectionError(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	assert.False(isConnectionError(ctx, nil))
+	assert.False(isConnectionError(ctx, errDefault))
+	assert.False(isConnectionError(ctx, errors.WrapCode(pgx.PgError{Code: "42601"}, errors.ErrDbRequestFailed)))
+	assert.False(isConnectionError(ctx, errors.WrapCode(pgx.PgError{Code: serializationFailure}, errors.ErrDbRequestFailed)))
+
+	assert.True(isConnectionError(ctx, errors.WrapCode(pgx.PgError{Code: "08006"}, errors.ErrDbRequestFailed)))
+	assert.True(isConnectionError(ctx, errors.WrapCode(pgx.PgError{Code: adminShutdown}, errors.ErrDbRequestFailed)))
+	assert.True(isConnectionError(ctx, errors.WrapCode(io.EOF, errors.ErrDbRequestFailed)))
+	assert.True(isConnectionError(ctx, errors.WrapCode(pgx.ErrDeadConn, errors.ErrDbTransactionBeginFailed)))
+	assert.True(isConnectionError(ctx, &net.OpError{Op: "read", Err: errDefault}))
+}
+
+func TestIsConnectionError_CancelledContext(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.False(isConnectionError(ctx, errors.WrapCode(io.EOF, errors.ErrDbRequestFailed)))
+}
+
+func TestIsTransientError(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	assert.False(isTransientError(ctx, nil))
+	assert.False(isTransientError(ctx, errDefault))
+	assert.False(isTransientError(ctx, errors.WrapCode(pgx.PgError{Code: "23505"}, errors.ErrDbRequestFailed)))
+	assert.False(isTransientError(ctx, errors.NewCode(errors.ErrDbConnectionInvalid)))
+
+	assert.True(isTransientError(ctx, errors.WrapCode(pgx.PgError{Code: serializationFailure}, errors.ErrDbRequestFailed)))
+	assert.True(isTransientError(ctx, errors.WrapCode(pgx.PgError{Code: deadlockDetected}, errors.ErrDbRequestFailed)))
+	assert.True(isTransientError(ctx, errors.WrapCode(io.ErrUnexpectedEOF, errors.ErrDbRequestFailed)))
+}
@@ -13,15 +13,17 @@ type UpdateQueryBuilder interface {
 	SetTable(table string) error
 	AddUpdate(column string, newValue interface{}) error
 	SetFilter(filter Filter) error
+	AddReturning(column string) error
 	SetVerbose(verbose bool)
 }
 
 type updateQueryBuilder struct {
-	columns map[string]bool
-	props   []sqlProp
-	table   string
-	filter  Filter
-	verbose bool
+	columns   map[string]bool
+	props     []sqlProp
+	table     string
+	filter    Filter
+	returning returningClause
+	verbose   bool
 }
 
 func NewUpdateQueryBuilder() UpdateQueryBuilder {
@@ -66,6 +68,10 @@ func (b *updateQueryBuilder) SetFilter(filter Filter) error {
 	return nil
 }
 
+func (b *updateQueryBuilder) AddReturning(column string) error {
+	return b.returning.add(column)
+}
+
 func (b *updateQueryBuilder) SetVerbose(verbose bool) {
 	b.verbose = verbose
 }
@@ -78,7 +84,8 @@ func (b *updateQueryBuilder) Build() (Query, error) {
 		return queryImpl{}, errors.WrapCode(errors.NewCode(errors.ErrNoColumnInSqlUpdateQuery), errors.ErrSqlTranslationFailed)
 	}
 
-	updates, err := b.updatesToStr()
+	var args sqlArgs
+	updates, err := b.updatesToStr(&args)
 	if err != nil {
 		return queryImpl{}, errors.WrapCode(err, errors.ErrSqlTranslationFailed)
 	}
@@ -86,22 +93,25 @@ func (b *updateQueryBuilder) Build() (Query, error) {
 	// https://www.w3schools.com/sql/sql_update.asp
 	sqlQuery := fmt.Sprintf("UPDATE %s SET %s", b.table, updates)
 	if b.filter != nil {
-		sqlQuery += fmt.Sprintf(" WHERE %s", b.filter.ToSql())
+		sqlQuery += fmt.Sprintf(" WHERE %s", args.addFilter(b.filter))
 	}
 
+	sqlQuery += b.returning.toSql()
+
 	query := queryImpl{
 		sqlCode: sqlQuery,
+		args:    args.values,
 		verbose: b.verbose,
 	}
 
 	return query, nil
 }
 
-func (b *updateQueryBuilder) updatesToStr() (string, error) {
+func (b *updateQueryBuilder) updatesToStr(args *sqlArgs) (string, error) {
 	var updates []string
 
 	for _, prop := range b.props {
-		update, err := sqlPropAsUpdateToStr(prop)
+		update, err := sqlPropAsUpdateToStr(prop, args)
 		if err != nil {
 			return "", err
 		}
@@ -99,7 +99,8 @@ func TestUpdateQueryBuilder_Build(t *testing.T) {
 	query, err := b.Build()
 	assert.Nil(err)
 	assert.True(query.Valid())
-	assert.Equal("UPDATE table SET column = 'prop'", query.ToSql())
+	assert.Equal("UPDATE table SET column = $1", query.ToSql())
+	assert.Equal([]interface{}{"prop"}, query.Args())
 }
 
 func TestUpdateQueryBuilder_Build_MultiColumns(t *testing.T) {
@@ -113,7 +114,8 @@ func TestUpdateQueryBuilder_Build_MultiColumns(t *testing.T) {
 	query, err := b.Build()
 	assert.Nil(err)
 	assert.True(query.Valid())
-	assert.Equal("UPDATE table SET column1 = 'prop1', column2 = 'prop2'", query.ToSql())
+	assert.Equal("UPDATE table SET column1 = $1, column2 = $2", query.ToSql())
+	assert.Equal([]interface{}{"prop1", "prop2"}, query.Args())
 }
 
 func TestUpdateQueryBuilder_Build_WithFilter(t *testing.T) {
@@ -130,7 +132,8 @@ func TestUpdateQueryBuilder_Build_WithFilter(t *testing.T) {
 	query, err := b.Build()
 	assert.Nil(err)
 	assert.True(query.Valid())
-	assert.Equal("UPDATE table SET column = 'prop' WHERE someFilter", query.ToSql())
+	assert.Equal("UPDATE table SET column = $1 WHERE someFilter", query.ToSql())
+	assert.Equal([]interface{}{"prop"}, query.Args())
 }
 
 func TestUpdateQueryBuilder_Build_ArgWithError(t *testing.T) {
@@ -145,3 +148,51 @@ func TestUpdateQueryBuilder_Build_ArgWithError(t *testing.T) {
 	cause := errors.Unwrap(err)
 	assert.True(strings.Contains(cause.Error(), errDefault.Error()))
 }
+
+func TestUpdateQueryBuilder_Build_WithFilterArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewUpdateQueryBuilder()
+	b.SetTable("table")
+	b.AddUpdate("column", "prop")
+	f := filterImpl{
+		sqlCode: "key in ($1)",
+		args:    []interface{}{"value"},
+	}
+	b.SetFilter(f)
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.True(query.Valid())
+	assert.Equal("UPDATE table SET column = $1 WHERE key in ($2)", query.ToSql())
+	assert.Equal([]interface{}{"prop", "value"}, query.Args())
+}
+
+func TestUpdateQueryBuilder_AddReturning(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewUpdateQueryBuilder()
+
+	err := b.AddReturning("")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidSqlColumn))
+
+	err = b.AddReturning("id")
+	assert.Nil(err)
+
+	err = b.AddReturning("id")
+	assert.True(errors.IsErrorWithCode(err, errors.ErrDuplicatedSqlColumn))
+}
+
+func TestUpdateQueryBuilder_Build_WithReturning(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewUpdateQueryBuilder()
+	b.SetTable("table")
+	b.AddUpdate("column", "prop")
+	b.SetFilter(filterImpl{sqlCode: "key = $1", args: []interface{}{"value"}})
+	b.AddReturning("id")
+
+	query, err := b.Build()
+	assert.Nil(err)
+	assert.Equal("UPDATE table SET column = $1 WHERE key = $2 RETURNING id", query.ToSql())
+}
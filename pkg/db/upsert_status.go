@@ -0,0 +1,46 @@
+package db
+
+type UpsertStatus int
+
+const (
+	UpsertSkipped UpsertStatus = iota
+	UpsertInserted
+	UpsertUpdated
+)
+
+// UpsertQueryBuilder is implemented by the builders of the queries which
+// can run with ExecuteUpsert: they only return the UpsertStatusColumn.
+type UpsertQueryBuilder interface {
+	QueryBuilder
+
+	ReturnsUpsertStatus() bool
+}
+
+// UpsertStatusColumn is the column returned by the insert queries built
+// with AddReturningUpsertStatus.
+//
+// It relies on the xmax system column being 0 for a freshly inserted
+// row and set to the id of the current transaction when an ON CONFLICT
+// clause updated it. This is not documented by postgres and might change
+// in a future version: a row locked by a concurrent transaction (e.g.
+// through SELECT FOR SHARE) also has a non-zero xmax.
+// https://stackoverflow.com/questions/34762732/how-to-find-out-if-an-upsert-was-an-update-with-postgresql-9-5-upsert
+const UpsertStatusColumn = "(xmax = 0) AS inserted"
+
+type upsertStatusParser struct {
+	status UpsertStatus
+}
+
+func (p *upsertStatusParser) ScanRow(row Scannable) error {
+	var inserted bool
+	if err := row.Scan(&inserted); err != nil {
+		return err
+	}
+
+	p.status = UpsertUpdated
+	if inserted {
+		p.status = UpsertInserted
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package db
+
+import (
+	"context"
+
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
+)
+
+var traceLog = logger.ScopedTracef
+
+type verboseHook struct{}
+
+// Traces the queries built with the verbose flag.
+func NewVerboseHook() QueryHook {
+	return verboseHook{}
+}
+
+func (h verboseHook) BeforeQuery(ctx context.Context, event QueryEvent) context.Context {
+	if event.Query.Verbose() {
+		traceLog(ctx, "executing: %s", queryToDebugStr(event.Query))
+	}
+
+	return ctx
+}
+
+func (h verboseHook) AfterQuery(ctx context.Context, event QueryEvent) {}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerboseHook(t *testing.T) {
+	assert := assert.New(t)
+	defer func() {
+		traceLog = logger.ScopedTracef
+	}()
+
+	var traces []string
+	traceLog = func(ctx context.Context, format string, args ...interface{}) {
+		traces = append(traces, fmt.Sprintf(format, args...))
+	}
+
+	h := NewVerboseHook()
+	query := queryImpl{sqlCode: "SELECT name FROM t WHERE id = $1", args: []interface{}{12}}
+
+	ctx := h.BeforeQuery(context.Background(), QueryEvent{Query: query})
+	h.AfterQuery(ctx, QueryEvent{Query: query})
+	assert.Empty(traces)
+
+	query.verbose = true
+	h.BeforeQuery(context.Background(), QueryEvent{Query: query})
+	h.AfterQuery(ctx, QueryEvent{Query: query})
+	assert.Equal([]string{"executing: SELECT name FROM t WHERE id = '12'"}, traces)
+}
@@ -61,6 +61,65 @@ const (
 
 	ErrNotImplemented
 
+	// The codes are part of the responses of the server: the new ones
+	// are appended so that the existing values do not change.
+	ErrDbTransactionBeginFailed
+	ErrDbTransactionCommitFailed
+	ErrDbTransactionRollbackFailed
+	ErrDbTransactionClosed
+
+	ErrInvalidSqlComparisonOperator
+	ErrNoFilterInSqlCombination
+
+	ErrInvalidSqlOrdering
+	ErrInvalidSqlLimit
+	ErrInvalidSqlOffset
+	ErrInvalidSqlCursor
+
+	ErrInvalidSqlTableAlias
+	ErrDuplicatedSqlTable
+	ErrInvalidSqlJoin
+
+	ErrInvalidSqlAggregate
+
+	ErrInvalidSqlConflictTarget
+	ErrInvalidSqlConflictAction
+
+	ErrNoRowInSqlBulkInsertQuery
+	ErrInvalidSqlBulkRow
+	ErrTooManyArgsInSqlQuery
+	ErrReturningNotSupportedInSqlCopy
+
+	ErrSqlQueryDidNotAffectAnyRow
+	ErrSqlQueryAffectedUnexpectedRows
+
+	ErrInvalidMigration
+	ErrMigrationFailed
+	ErrMigrationChecksumMismatch
+	ErrMigrationDirty
+	ErrUnknownMigrationVersion
+	ErrSchemaOutdated
+
+	ErrInvalidSqlStructMapping
+	ErrMismatchedSqlColumns
+
+	ErrDbEntityCreationFailure
+	ErrDbEntityGetFailure
+	ErrDbEntityUpdateFailure
+	ErrDbEntityDeletionFailure
+
+	ErrInvalidSqlNotificationChannel
+	ErrDbSubscriptionFailed
+
+	ErrDbUniqueViolation
+	ErrDbForeignKeyViolation
+	ErrDbNotNullViolation
+	ErrDbCheckViolation
+	ErrDbSerializationFailure
+	ErrDbDeadlockDetected
+
+	ErrInvalidDbConfig
+
 	lastErrorCode
 )
 
@@ -87,38 +146,85 @@ var errorsCodeToMessage = map[ErrorCode]string{
 	ErrPostRequestFailed: "post request failed",
 	ErrGetRequestFailed:  "get request failed",
 
-	ErrDbConnectionFailed:        "db connection failed",
-	ErrDbConnectionTimeout:       "db connection timeout",
-	ErrDbConnectionInvalid:       "db connection is invalid",
-	ErrInvalidQuery:              "invalid sql query",
-	ErrInvalidSqlTable:           "invalid table for sql query",
-	ErrInvalidSqlProp:            "invalid property for sql query",
-	ErrDuplicatedSqlProp:         "duplicated property for sql query",
-	ErrInvalidSqlFilter:          "invalid filter for sql query",
-	ErrInvalidSqlScript:          "invalid script for sql query",
-	ErrInvalidSqlScriptArg:       "invalid script argument for sql query",
-	ErrSqlTranslationFailed:      "failed to generate sql query",
-	ErrNoPropInSqlSelectQuery:    "no property set for sql query",
-	ErrInvalidSqlComparisonKey:   "invalid comparison key for sql query",
-	ErrInvalidSqlComparisonValue: "invalid comparison value for sql query",
-	ErrNoValuesInSqlComparison:   "no comparison values set for sql query",
-	ErrInvalidSqlColumn:          "invalid column for sql query",
-	ErrDuplicatedSqlColumn:       "duplicated column for sql query",
-	ErrNoColumnInSqlInsertQuery:  "no column set for sql query",
-	ErrNoColumnInSqlUpdateQuery:  "no column set for sql query",
-
-	ErrDbCorruptedData:               "failed to interpret data from database",
-	ErrDbRequestCreationFailed:       "failed to create database request",
-	ErrDbRequestFailed:               "sql query execution returned error",
-	ErrDbRequestTimeout:              "query to database timed out",
-	ErrMultiValuedDbElement:          "multiple values for expected unique database entry",
-	ErrInvalidSqlQueryReceiverType:   "invalid receiver of a sql query",
-	ErrNoRowsReturnedForSqlQuery:     "sql query returned no rows",
-	ErrSqlRowParsingFailed:           "parsing of sql row failed",
-	ErrInvalidSqlCommandTag:          "invalid sql command tag returned",
-	ErrUnknownSqlCommandTag:          "unknown sql command tag returned",
-	ErrSqlQueryDidNotAffectSingleRow: "sql query did not affect a single row",
-	ErrSqlQueryAffectedMultipleRows:  "sql query affected multiple rows",
+	ErrDbConnectionFailed:             "db connection failed",
+	ErrDbConnectionTimeout:            "db connection timeout",
+	ErrDbConnectionInvalid:            "db connection is invalid",
+	ErrInvalidDbConfig:                "invalid db configuration",
+	ErrInvalidQuery:                   "invalid sql query",
+	ErrInvalidSqlTable:                "invalid table for sql query",
+	ErrInvalidSqlProp:                 "invalid property for sql query",
+	ErrDuplicatedSqlProp:              "duplicated property for sql query",
+	ErrInvalidSqlFilter:               "invalid filter for sql query",
+	ErrInvalidSqlScript:               "invalid script for sql query",
+	ErrInvalidSqlScriptArg:            "invalid script argument for sql query",
+	ErrSqlTranslationFailed:           "failed to generate sql query",
+	ErrInvalidSqlStructMapping:        "invalid struct mapping for sql query",
+	ErrNoPropInSqlSelectQuery:         "no property set for sql query",
+	ErrInvalidSqlComparisonKey:        "invalid comparison key for sql query",
+	ErrInvalidSqlComparisonValue:      "invalid comparison value for sql query",
+	ErrNoValuesInSqlComparison:        "no comparison values set for sql query",
+	ErrInvalidSqlComparisonOperator:   "invalid comparison operator for sql query",
+	ErrNoFilterInSqlCombination:       "no filter set for sql filter combination",
+	ErrInvalidSqlColumn:               "invalid column for sql query",
+	ErrDuplicatedSqlColumn:            "duplicated column for sql query",
+	ErrNoColumnInSqlInsertQuery:       "no column set for sql query",
+	ErrNoColumnInSqlUpdateQuery:       "no column set for sql query",
+	ErrInvalidSqlOrdering:             "invalid ordering for sql query",
+	ErrInvalidSqlLimit:                "invalid limit for sql query",
+	ErrInvalidSqlOffset:               "invalid offset for sql query",
+	ErrInvalidSqlCursor:               "invalid cursor for sql query",
+	ErrInvalidSqlTableAlias:           "invalid table alias for sql query",
+	ErrDuplicatedSqlTable:             "duplicated table for sql query",
+	ErrInvalidSqlJoin:                 "invalid join for sql query",
+	ErrInvalidSqlAggregate:            "invalid aggregate for sql query",
+	ErrInvalidSqlConflictTarget:       "invalid conflict target for sql query",
+	ErrInvalidSqlConflictAction:       "invalid conflict action for sql query",
+	ErrNoRowInSqlBulkInsertQuery:      "no row set for sql query",
+	ErrInvalidSqlBulkRow:              "row does not match the columns of sql query",
+	ErrTooManyArgsInSqlQuery:          "too many arguments for sql query",
+	ErrReturningNotSupportedInSqlCopy: "returning clause is not supported for sql copy",
+	ErrInvalidSqlNotificationChannel:  "invalid channel for sql notification",
+
+	ErrDbCorruptedData:                "failed to interpret data from database",
+	ErrDbRequestCreationFailed:        "failed to create database request",
+	ErrDbRequestFailed:                "sql query execution returned error",
+	ErrDbRequestTimeout:               "query to database timed out",
+	ErrDbUniqueViolation:              "sql query violated a unique constraint",
+	ErrDbForeignKeyViolation:          "sql query violated a foreign key constraint",
+	ErrDbNotNullViolation:             "sql query violated a not null constraint",
+	ErrDbCheckViolation:               "sql query violated a check constraint",
+	ErrDbSerializationFailure:         "sql query failed to serialize with concurrent transactions",
+	ErrDbDeadlockDetected:             "sql query was aborted by a deadlock",
+	ErrMultiValuedDbElement:           "multiple values for expected unique database entry",
+	ErrInvalidSqlQueryReceiverType:    "invalid receiver of a sql query",
+	ErrNoRowsReturnedForSqlQuery:      "sql query returned no rows",
+	ErrSqlRowParsingFailed:            "parsing of sql row failed",
+	ErrMismatchedSqlColumns:           "sql columns do not match struct",
+	ErrInvalidSqlCommandTag:           "invalid sql command tag returned",
+	ErrUnknownSqlCommandTag:           "unknown sql command tag returned",
+	ErrSqlQueryDidNotAffectSingleRow:  "sql query did not affect a single row",
+	ErrSqlQueryAffectedMultipleRows:   "sql query affected multiple rows",
+	ErrSqlQueryDidNotAffectAnyRow:     "sql query did not affect any row",
+	ErrSqlQueryAffectedUnexpectedRows: "sql query affected an unexpected number of rows",
+
+	ErrDbTransactionBeginFailed:    "failed to start database transaction",
+	ErrDbTransactionCommitFailed:   "failed to commit database transaction",
+	ErrDbTransactionRollbackFailed: "failed to rollback database transaction",
+	ErrDbTransactionClosed:         "database transaction is already closed",
+
+	ErrDbSubscriptionFailed: "failed to subscribe to database notifications",
+
+	ErrDbEntityCreationFailure: "error while creating entity",
+	ErrDbEntityGetFailure:      "error while getting entity",
+	ErrDbEntityUpdateFailure:   "error while updating entity",
+	ErrDbEntityDeletionFailure: "error while deleting entity",
+
+	ErrInvalidMigration:          "invalid migration file",
+	ErrMigrationFailed:           "failed to apply migration",
+	ErrMigrationChecksumMismatch: "applied migration does not match its file",
+	ErrMigrationDirty:            "database is in a dirty migration state",
+	ErrUnknownMigrationVersion:   "unknown migration version",
+	ErrSchemaOutdated:            "database schema is not up to date",
 
 	ErrNotImplemented: "not implemented",
 }
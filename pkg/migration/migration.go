@@ -0,0 +1,109 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/KnoblauchPilze/go-game/pkg/db/memory"
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+// Same naming as the migrate tool: <version>_<name>.(up|down).sql.
+// https://github.com/golang-migrate/migrate/blob/master/MIGRATIONS.md
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// The checksum only covers the up script: this is the one which
+// defines the schema.
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+// Reads the migrations at the root of the file system, sorted by
+// version. Each version should define both an up and a down script.
+func Load(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, errors.WrapCode(err, errors.ErrInvalidMigration)
+	}
+
+	migrations := make(map[uint]*Migration)
+	scripts := make(map[string]bool)
+	for _, entry := range entries {
+		matches := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 0)
+		if err != nil || version == 0 {
+			return nil, errors.WrapCode(errors.Newf("invalid version in %s", entry.Name()), errors.ErrInvalidMigration)
+		}
+
+		content, err := fs.ReadFile(files, entry.Name())
+		if err != nil {
+			return nil, errors.WrapCode(err, errors.ErrInvalidMigration)
+		}
+
+		m, ok := migrations[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: matches[2]}
+			migrations[uint(version)] = m
+		}
+		if m.Name != matches[2] {
+			return nil, errors.WrapCode(errors.Newf("conflicting names for version %d", version), errors.ErrInvalidMigration)
+		}
+
+		scripts[strconv.FormatUint(version, 10)+"."+matches[3]] = true
+
+		if matches[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	out := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		prefix := strconv.FormatUint(uint64(m.Version), 10)
+		if !scripts[prefix+".up"] || !scripts[prefix+".down"] {
+			return nil, errors.WrapCode(errors.Newf("missing script for version %d", m.Version), errors.ErrInvalidMigration)
+		}
+
+		out = append(out, *m)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Version < out[j].Version
+	})
+
+	return out, nil
+}
+
+// MemorySchema returns the tables of the in-memory database defined by
+// the up scripts of the migrations, so that it matches the schema of a
+// migrated postgres database.
+func MemorySchema(migrations []Migration) ([]memory.Table, error) {
+	scripts := make([]string, 0, len(migrations))
+	for _, m := range migrations {
+		scripts = append(scripts, m.Up)
+	}
+
+	tables, err := memory.ParseSchema(scripts...)
+	if err != nil {
+		return nil, errors.WrapCode(err, errors.ErrInvalidMigration)
+	}
+
+	return tables, nil
+}
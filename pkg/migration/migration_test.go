@@ -0,0 +1,137 @@
+package migration
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/KnoblauchPilze/go-game/database/users/migrations"
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMigrationFiles = fstest.MapFS{
+	"1_create_t.up.sql":    {Data: []byte("CREATE TABLE t (id integer);")},
+	"1_create_t.down.sql":  {Data: []byte("DROP TABLE t;")},
+	"2_create_u.up.sql":    {Data: []byte("CREATE TABLE u (id integer);")},
+	"2_create_u.down.sql":  {Data: []byte("DROP TABLE u;")},
+	"10_create_v.up.sql":   {Data: []byte("CREATE TABLE v (id integer);")},
+	"10_create_v.down.sql": {Data: []byte("DROP TABLE v;")},
+	"README.md":            {Data: []byte("not a migration")},
+}
+
+func TestLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	actual, err := Load(testMigrationFiles)
+	assert.Nil(err)
+	assert.Equal(3, len(actual))
+
+	assert.Equal(uint(1), actual[0].Version)
+	assert.Equal("create_t", actual[0].Name)
+	assert.Equal("CREATE TABLE t (id integer);", actual[0].Up)
+	assert.Equal("DROP TABLE t;", actual[0].Down)
+	assert.Equal(uint(2), actual[1].Version)
+	assert.Equal(uint(10), actual[2].Version)
+}
+
+func TestLoad_MissingScript(t *testing.T) {
+	assert := assert.New(t)
+
+	files := fstest.MapFS{
+		"1_create_t.up.sql": {Data: []byte("CREATE TABLE t (id integer);")},
+	}
+
+	_, err := Load(files)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidMigration))
+}
+
+func TestLoad_EmptyScript(t *testing.T) {
+	assert := assert.New(t)
+
+	files := fstest.MapFS{
+		"1_create_t.up.sql":   {Data: []byte("CREATE TABLE t (id integer);")},
+		"1_create_t.down.sql": {Data: []byte("")},
+	}
+
+	actual, err := Load(files)
+	assert.Nil(err)
+	assert.Equal("", actual[0].Down)
+}
+
+func TestLoad_ConflictingNames(t *testing.T) {
+	assert := assert.New(t)
+
+	files := fstest.MapFS{
+		"1_create_t.up.sql":   {Data: []byte("CREATE TABLE t (id integer);")},
+		"1_create_u.down.sql": {Data: []byte("DROP TABLE t;")},
+	}
+
+	_, err := Load(files)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidMigration))
+}
+
+func TestLoad_InvalidVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	files := fstest.MapFS{
+		"0_create_t.up.sql":   {Data: []byte("CREATE TABLE t (id integer);")},
+		"0_create_t.down.sql": {Data: []byte("DROP TABLE t;")},
+	}
+
+	_, err := Load(files)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidMigration))
+}
+
+func TestLoad_UsersMigrations(t *testing.T) {
+	assert := assert.New(t)
+
+	actual, err := Load(migrations.Files)
+	assert.Nil(err)
+	assert.Equal(2, len(actual))
+	assert.Equal("create_initial_schema", actual[0].Name)
+	assert.Equal("create_users", actual[1].Name)
+}
+
+func TestMemorySchema(t *testing.T) {
+	assert := assert.New(t)
+
+	ms, err := Load(testMigrationFiles)
+	assert.Nil(err)
+
+	tables, err := MemorySchema(ms)
+	assert.Nil(err)
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		names = append(names, table.Name)
+	}
+	assert.Equal([]string{"t", "u", "v"}, names)
+
+	ms = append(ms, Migration{Version: 11, Up: "ALTER TABLE t ADD COLUMN name text;"})
+	_, err = MemorySchema(ms)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrInvalidMigration))
+}
+
+func TestMemorySchema_UsersMigrations(t *testing.T) {
+	assert := assert.New(t)
+
+	ms, err := Load(migrations.Files)
+	assert.Nil(err)
+
+	tables, err := MemorySchema(ms)
+	assert.Nil(err)
+	assert.Equal(1, len(tables))
+	assert.Equal("users", tables[0].Name)
+}
+
+func TestMigration_Checksum(t *testing.T) {
+	assert := assert.New(t)
+
+	m := Migration{Up: "CREATE TABLE t (id integer);", Down: "DROP TABLE t;"}
+	other := m
+	other.Down = "DROP TABLE IF EXISTS t;"
+	assert.Equal(m.Checksum(), other.Checksum())
+	assert.Equal(64, len(m.Checksum()))
+
+	other.Up = "CREATE TABLE t (id bigint);"
+	assert.NotEqual(m.Checksum(), other.Checksum())
+}
@@ -0,0 +1,316 @@
+package migration
+
+import (
+	"context"
+	"sort"
+
+	"github.com/KnoblauchPilze/go-game/pkg/db"
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/KnoblauchPilze/go-game/pkg/logger"
+)
+
+// Arbitrary key shared by all the runners: only one of them can
+// modify the schema at a time.
+const advisoryLockKey int64 = 7362510294
+
+const (
+	lockSql        = "SELECT pg_advisory_xact_lock($1)"
+	columnsSql     = "SELECT column_name FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'schema_migrations'"
+	createTableSql = `CREATE TABLE IF NOT EXISTS schema_migrations (
+  version bigint NOT NULL,
+  name text NOT NULL,
+  checksum text NOT NULL,
+  applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (version)
+)`
+	dropLegacyTableSql = "DROP TABLE schema_migrations"
+	selectLegacySql    = "SELECT version, dirty FROM schema_migrations"
+	selectAppliedSql   = "SELECT version, name, checksum FROM schema_migrations ORDER BY version"
+	insertAppliedSql   = "INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)"
+	deleteAppliedSql   = "DELETE FROM schema_migrations WHERE version = $1"
+)
+
+// The table created by the migrate tool only stores the last version
+// applied and whether it failed.
+const legacyDirtyColumn = "dirty"
+
+type Runner interface {
+	// Applies the next steps pending migrations, all of them if steps
+	// is 0. A pending migration older than the last applied one is
+	// applied as well: nothing is ever reverted.
+	Up(ctx context.Context, steps uint) error
+	// Reverts the last steps migrations, all of them if steps is 0.
+	Down(ctx context.Context, steps uint) error
+	// Applies or reverts migrations so that exactly the ones up to the
+	// version are applied: 0 reverts all of them.
+	Goto(ctx context.Context, version uint) error
+	Status(ctx context.Context) (Status, error)
+}
+
+type Status struct {
+	// The last version applied, 0 if none.
+	Version uint
+	// The last version available.
+	Latest  uint
+	Applied []uint
+	Pending []uint
+}
+
+func (s Status) UpToDate() bool {
+	return len(s.Pending) == 0
+}
+
+type appliedMigration struct {
+	version  uint
+	name     string
+	checksum string
+}
+
+type runnerImpl struct {
+	qe         db.QueryExecutor
+	migrations []Migration
+}
+
+func NewRunner(database db.Database, migrations []Migration) Runner {
+	return &runnerImpl{
+		qe:         db.NewQueryExecutor(database),
+		migrations: migrations,
+	}
+}
+
+func (r *runnerImpl) Up(ctx context.Context, steps uint) error {
+	status, err := r.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(status.Pending) == 0 {
+		return nil
+	}
+	if steps == 0 || steps > uint(len(status.Pending)) {
+		steps = uint(len(status.Pending))
+	}
+
+	return r.migrate(ctx, status.Pending[steps-1], false)
+}
+
+func (r *runnerImpl) Down(ctx context.Context, steps uint) error {
+	status, err := r.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	count := uint(len(status.Applied))
+	if steps == 0 || steps >= count {
+		return r.Goto(ctx, 0)
+	}
+
+	return r.Goto(ctx, status.Applied[count-steps-1])
+}
+
+// Each migration is run in its own transaction, after acquiring the
+// lock: a concurrent runner waits and then sees the new version.
+func (r *runnerImpl) Goto(ctx context.Context, version uint) error {
+	if version != 0 {
+		if _, ok := r.find(version); !ok {
+			return errors.WrapCode(errors.Newf("no migration with version %d", version), errors.ErrUnknownMigrationVersion)
+		}
+	}
+
+	return r.migrate(ctx, version, true)
+}
+
+// Applies the migrations up to the target and reverts the ones after
+// it if allowed to.
+func (r *runnerImpl) migrate(ctx context.Context, target uint, revertAfter bool) error {
+	ctx = db.WithReadYourWrites(ctx)
+	for {
+		done, err := r.step(ctx, target, revertAfter)
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+func (r *runnerImpl) Status(ctx context.Context) (Status, error) {
+	applied, err := readAppliedMigrations(db.WithReadYourWrites(ctx), r.qe, r.migrations)
+	if err != nil {
+		return Status{}, err
+	}
+	if err := r.verify(applied); err != nil {
+		return Status{}, err
+	}
+
+	var status Status
+	for _, m := range r.migrations {
+		status.Latest = m.Version
+		if _, ok := applied[m.Version]; ok {
+			status.Version = m.Version
+			status.Applied = append(status.Applied, m.Version)
+		} else {
+			status.Pending = append(status.Pending, m.Version)
+		}
+	}
+
+	return status, nil
+}
+
+func (r *runnerImpl) step(ctx context.Context, target uint, revertAfter bool) (bool, error) {
+	done := false
+
+	err := r.qe.WithTransaction(ctx, func(tx db.QueryExecutor) error {
+		if err := execute(ctx, tx, lockSql, advisoryLockKey); err != nil {
+			return err
+		}
+
+		applied, err := r.prepareTable(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if err := r.verify(applied); err != nil {
+			return err
+		}
+
+		// Reverting first keeps the order of the scripts consistent
+		// with the order in which they were applied.
+		last, after := lastAppliedAfter(applied, target)
+		if after && revertAfter {
+			m, _ := r.find(last)
+			return revert(ctx, tx, m)
+		}
+
+		for _, m := range r.migrations {
+			if _, ok := applied[m.Version]; !ok && m.Version <= target {
+				if last > m.Version {
+					logger.ScopedWarnf(ctx, "applying migration %d_%s after migration %d", m.Version, m.Name, last)
+				}
+				return apply(ctx, tx, m)
+			}
+		}
+
+		done = true
+		return nil
+	})
+
+	return done, err
+}
+
+// Creates the table if needed: a table created by the migrate tool is
+// converted, trusting the checksums of the migrations it applied.
+func (r *runnerImpl) prepareTable(ctx context.Context, tx db.QueryExecutor) (map[uint]appliedMigration, error) {
+	columns, err := readMigrationsTableColumns(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var legacy map[uint]appliedMigration
+	if columns[legacyDirtyColumn] {
+		if legacy, err = readLegacyMigrations(ctx, tx, r.migrations); err != nil {
+			return nil, err
+		}
+		if err := execute(ctx, tx, dropLegacyTableSql); err != nil {
+			return nil, err
+		}
+
+		logger.ScopedInfof(ctx, "converting schema_migrations table of migrate tool (%d migration(s) applied)", len(legacy))
+	}
+
+	if err := execute(ctx, tx, createTableSql); err != nil {
+		return nil, err
+	}
+
+	for _, m := range sortedApplied(legacy) {
+		if err := execute(ctx, tx, insertAppliedSql, int64(m.version), m.name, m.checksum); err != nil {
+			return nil, err
+		}
+	}
+
+	if legacy != nil {
+		return legacy, nil
+	}
+
+	return readAppliedMigrationsFromTable(ctx, tx)
+}
+
+func (r *runnerImpl) verify(applied map[uint]appliedMigration) error {
+	for _, a := range applied {
+		m, ok := r.find(a.version)
+		if !ok {
+			return errors.WrapCode(errors.Newf("migration %d is applied but unknown", a.version), errors.ErrUnknownMigrationVersion)
+		}
+		if m.Checksum() != a.checksum {
+			return errors.WrapCode(errors.Newf("migration %d_%s was modified after being applied", m.Version, m.Name), errors.ErrMigrationChecksumMismatch)
+		}
+	}
+
+	return nil
+}
+
+func (r *runnerImpl) find(version uint) (Migration, bool) {
+	for _, m := range r.migrations {
+		if m.Version == version {
+			return m, true
+		}
+	}
+
+	return Migration{}, false
+}
+
+func apply(ctx context.Context, tx db.QueryExecutor, m Migration) error {
+	logger.ScopedInfof(ctx, "applying migration %d_%s", m.Version, m.Name)
+
+	if err := execute(ctx, tx, m.Up); err != nil {
+		return errors.WrapCode(err, errors.ErrMigrationFailed)
+	}
+
+	return execute(ctx, tx, insertAppliedSql, int64(m.Version), m.Name, m.Checksum())
+}
+
+func revert(ctx context.Context, tx db.QueryExecutor, m Migration) error {
+	logger.ScopedInfof(ctx, "reverting migration %d_%s", m.Version, m.Name)
+
+	if err := execute(ctx, tx, m.Down); err != nil {
+		return errors.WrapCode(err, errors.ErrMigrationFailed)
+	}
+
+	return execute(ctx, tx, deleteAppliedSql, int64(m.Version))
+}
+
+func lastAppliedAfter(applied map[uint]appliedMigration, target uint) (uint, bool) {
+	sorted := sortedApplied(applied)
+	if len(sorted) == 0 {
+		return 0, false
+	}
+
+	last := sorted[len(sorted)-1].version
+	return last, last > target
+}
+
+func sortedApplied(applied map[uint]appliedMigration) []appliedMigration {
+	out := make([]appliedMigration, 0, len(applied))
+	for _, a := range applied {
+		out = append(out, a)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].version < out[j].version
+	})
+
+	return out
+}
+
+func execute(ctx context.Context, qe db.QueryExecutor, sqlCode string, args ...interface{}) error {
+	qb := newRawQueryBuilder(sqlCode, args...)
+	_, err := qe.ExecuteQueryAffectingAnyRows(ctx, qb)
+	return err
+}
+
+func newRawQueryBuilder(sqlCode string, args ...interface{}) db.QueryBuilder {
+	qb := db.NewRawQueryBuilder()
+	qb.SetSql(sqlCode)
+	for _, arg := range args {
+		qb.AddArg(arg)
+	}
+
+	return qb
+}
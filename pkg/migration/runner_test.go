@@ -0,0 +1,423 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/pkg/db"
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var errDefault = fmt.Errorf("someError")
+
+var migrationsTableColumns = []string{"version", "name", "checksum", "applied_at"}
+var legacyTableColumns = []string{"version", "dirty"}
+
+type legacyRow struct {
+	version int64
+	dirty   bool
+}
+
+type fakeDbState struct {
+	columns []string
+	legacy  []legacyRow
+	applied map[int64]appliedMigration
+	scripts []string
+}
+
+func (s fakeDbState) clone() fakeDbState {
+	out := s
+	out.columns = append([]string(nil), s.columns...)
+	out.legacy = append([]legacyRow(nil), s.legacy...)
+	out.scripts = append([]string(nil), s.scripts...)
+	out.applied = make(map[int64]appliedMigration)
+	for version, a := range s.applied {
+		out.applied[version] = a
+	}
+	return out
+}
+
+// Understands the queries sent by the runner and records the scripts
+// of the migrations.
+type fakeDb struct {
+	fakeDbState
+	locks    int
+	readCtx  context.Context
+	failOn   string
+	snapshot fakeDbState
+}
+
+func newFakeDb() *fakeDb {
+	return &fakeDb{
+		fakeDbState: fakeDbState{applied: make(map[int64]appliedMigration)},
+	}
+}
+
+func (f *fakeDb) Connect(ctx context.Context) error    { return nil }
+func (f *fakeDb) Disconnect(ctx context.Context) error { return nil }
+
+func (f *fakeDb) Query(ctx context.Context, query db.Query) db.Rows {
+	f.readCtx = ctx
+
+	var rows [][]interface{}
+	switch query.ToSql() {
+	case columnsSql:
+		for _, column := range f.columns {
+			rows = append(rows, []interface{}{column})
+		}
+	case selectLegacySql:
+		for _, row := range f.legacy {
+			rows = append(rows, []interface{}{row.version, row.dirty})
+		}
+	case selectAppliedSql:
+		for _, a := range sortedApplied(f.appliedByVersion()) {
+			rows = append(rows, []interface{}{int64(a.version), a.name, a.checksum})
+		}
+	default:
+		return &fakeRows{err: fmt.Errorf("unexpected query %s", query.ToSql())}
+	}
+
+	return &fakeRows{rows: rows}
+}
+
+func (f *fakeDb) Execute(ctx context.Context, query db.Query) db.Result {
+	args := query.Args()
+
+	switch query.ToSql() {
+	case lockSql:
+		f.locks++
+	case createTableSql:
+		if len(f.columns) == 0 {
+			f.columns = migrationsTableColumns
+		}
+	case dropLegacyTableSql:
+		f.columns = nil
+		f.legacy = nil
+	case insertAppliedSql:
+		version := args[0].(int64)
+		f.applied[version] = appliedMigration{
+			version:  uint(version),
+			name:     args[1].(string),
+			checksum: args[2].(string),
+		}
+	case deleteAppliedSql:
+		delete(f.applied, args[0].(int64))
+	default:
+		if query.ToSql() == f.failOn {
+			return &fakeResult{err: errDefault}
+		}
+		f.scripts = append(f.scripts, query.ToSql())
+	}
+
+	return &fakeResult{}
+}
+
+func (f *fakeDb) CopyFrom(ctx context.Context, copy db.CopyFrom) db.Result {
+	return &fakeResult{err: errDefault}
+}
+
+func (f *fakeDb) Begin(ctx context.Context) (db.Transaction, error) {
+	f.snapshot = f.fakeDbState.clone()
+	return &fakeTx{db: f}, nil
+}
+
+func (f *fakeDb) Subscribe(ctx context.Context, channel string) (db.Subscription, error) {
+	return nil, errors.NewCode(errors.ErrNotImplemented)
+}
+
+func (f *fakeDb) appliedByVersion() map[uint]appliedMigration {
+	out := make(map[uint]appliedMigration)
+	for _, a := range f.applied {
+		out[a.version] = a
+	}
+	return out
+}
+
+func (f *fakeDb) appliedVersions() []uint {
+	var out []uint
+	for _, a := range sortedApplied(f.appliedByVersion()) {
+		out = append(out, a.version)
+	}
+	return out
+}
+
+type fakeTx struct {
+	db *fakeDb
+}
+
+func (t *fakeTx) Query(ctx context.Context, query db.Query) db.Rows {
+	return t.db.Query(ctx, query)
+}
+
+func (t *fakeTx) Execute(ctx context.Context, query db.Query) db.Result {
+	return t.db.Execute(ctx, query)
+}
+
+func (t *fakeTx) CopyFrom(ctx context.Context, copy db.CopyFrom) db.Result {
+	return t.db.CopyFrom(ctx, copy)
+}
+
+func (t *fakeTx) Begin(ctx context.Context) (db.Transaction, error) {
+	return nil, errDefault
+}
+
+func (t *fakeTx) Commit(ctx context.Context) error {
+	return nil
+}
+
+func (t *fakeTx) Rollback(ctx context.Context) error {
+	t.db.fakeDbState = t.db.snapshot
+	return nil
+}
+
+type fakeRows struct {
+	rows [][]interface{}
+	err  error
+}
+
+func (r *fakeRows) Err() error  { return r.err }
+func (r *fakeRows) Close()      {}
+func (r *fakeRows) Empty() bool { return len(r.rows) == 0 }
+
+func (r *fakeRows) GetSingleValue(parser db.RowParser) error {
+	return errDefault
+}
+
+func (r *fakeRows) GetAll(parser db.RowParser) error {
+	for _, row := range r.rows {
+		if err := parser.ScanRow(fakeScannable(row)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeRows) Next() bool                     { return false }
+func (r *fakeRows) Scan(parser db.RowParser) error { return errDefault }
+
+type fakeScannable []interface{}
+
+func (s fakeScannable) Scan(dest ...interface{}) error {
+	for id, d := range dest {
+		switch v := d.(type) {
+		case *int64:
+			*v = s[id].(int64)
+		case *string:
+			*v = s[id].(string)
+		case *bool:
+			*v = s[id].(bool)
+		default:
+			return errDefault
+		}
+	}
+	return nil
+}
+
+type fakeResult struct {
+	err error
+}
+
+func (r *fakeResult) Err() error        { return r.err }
+func (r *fakeResult) AffectedRows() int { return 0 }
+
+func newTestRunner(t *testing.T, f *fakeDb) Runner {
+	migrations, err := Load(testMigrationFiles)
+	assert.Nil(t, err)
+	return NewRunner(f, migrations)
+}
+
+func TestRunner_Status_NoTable(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newFakeDb()
+	r := newTestRunner(t, f)
+
+	status, err := r.Status(context.Background())
+	assert.Nil(err)
+	assert.Equal(Status{Latest: 10, Pending: []uint{1, 2, 10}}, status)
+	assert.False(status.UpToDate())
+	assert.True(db.ReadYourWritesFromContext(f.readCtx))
+	assert.Nil(f.columns)
+}
+
+func TestRunner_Up(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newFakeDb()
+	r := newTestRunner(t, f)
+
+	assert.Nil(r.Up(context.Background(), 0))
+	assert.Equal([]string{
+		"CREATE TABLE t (id integer);",
+		"CREATE TABLE u (id integer);",
+		"CREATE TABLE v (id integer);",
+	}, f.scripts)
+	assert.Equal([]uint{1, 2, 10}, f.appliedVersions())
+	assert.Equal(4, f.locks)
+
+	status, err := r.Status(context.Background())
+	assert.Nil(err)
+	assert.Equal(Status{Version: 10, Latest: 10, Applied: []uint{1, 2, 10}}, status)
+	assert.True(status.UpToDate())
+
+	assert.Nil(r.Up(context.Background(), 0))
+	assert.Equal(3, len(f.scripts))
+}
+
+func TestRunner_Up_Steps(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newFakeDb()
+	r := newTestRunner(t, f)
+
+	assert.Nil(r.Up(context.Background(), 1))
+	assert.Equal([]uint{1}, f.appliedVersions())
+
+	assert.Nil(r.Up(context.Background(), 5))
+	assert.Equal([]uint{1, 2, 10}, f.appliedVersions())
+}
+
+func TestRunner_Up_Gap(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newFakeDb()
+	r := newTestRunner(t, f)
+	assert.Nil(r.Up(context.Background(), 0))
+	delete(f.applied, 2)
+	f.scripts = nil
+
+	status, err := r.Status(context.Background())
+	assert.Nil(err)
+	assert.Equal([]uint{2}, status.Pending)
+
+	assert.Nil(r.Up(context.Background(), 1))
+	assert.Equal([]string{"CREATE TABLE u (id integer);"}, f.scripts)
+	assert.Equal([]uint{1, 2, 10}, f.appliedVersions())
+}
+
+func TestRunner_Down(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newFakeDb()
+	r := newTestRunner(t, f)
+	assert.Nil(r.Up(context.Background(), 0))
+	f.scripts = nil
+
+	assert.Nil(r.Down(context.Background(), 1))
+	assert.Equal([]uint{1, 2}, f.appliedVersions())
+
+	assert.Nil(r.Down(context.Background(), 0))
+	assert.Empty(f.appliedVersions())
+	assert.Equal([]string{
+		"DROP TABLE v;",
+		"DROP TABLE u;",
+		"DROP TABLE t;",
+	}, f.scripts)
+}
+
+func TestRunner_Goto(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newFakeDb()
+	r := newTestRunner(t, f)
+
+	assert.Nil(r.Goto(context.Background(), 2))
+	assert.Equal([]uint{1, 2}, f.appliedVersions())
+
+	assert.Nil(r.Goto(context.Background(), 1))
+	assert.Equal([]uint{1}, f.appliedVersions())
+
+	err := r.Goto(context.Background(), 3)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrUnknownMigrationVersion))
+}
+
+func TestRunner_Goto_Failure(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newFakeDb()
+	f.failOn = "CREATE TABLE u (id integer);"
+	r := newTestRunner(t, f)
+
+	err := r.Up(context.Background(), 0)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrMigrationFailed))
+	assert.Equal([]uint{1}, f.appliedVersions())
+	assert.Equal([]string{"CREATE TABLE t (id integer);"}, f.scripts)
+}
+
+func TestRunner_ChecksumMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newFakeDb()
+	r := newTestRunner(t, f)
+	assert.Nil(r.Up(context.Background(), 1))
+
+	a := f.applied[1]
+	a.checksum = "modified"
+	f.applied[1] = a
+
+	_, err := r.Status(context.Background())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrMigrationChecksumMismatch))
+	err = r.Goto(context.Background(), 2)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrMigrationChecksumMismatch))
+	assert.Equal([]uint{1}, f.appliedVersions())
+}
+
+func TestRunner_UnknownAppliedMigration(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newFakeDb()
+	f.columns = migrationsTableColumns
+	f.applied[11] = appliedMigration{version: 11, name: "future", checksum: "checksum"}
+	r := newTestRunner(t, f)
+
+	_, err := r.Status(context.Background())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrUnknownMigrationVersion))
+}
+
+func TestRunner_LegacyTable(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newFakeDb()
+	f.columns = legacyTableColumns
+	f.legacy = []legacyRow{{version: 2}}
+	r := newTestRunner(t, f)
+
+	status, err := r.Status(context.Background())
+	assert.Nil(err)
+	assert.Equal(Status{Version: 2, Latest: 10, Applied: []uint{1, 2}, Pending: []uint{10}}, status)
+	assert.Equal(legacyTableColumns, f.columns)
+
+	assert.Nil(r.Up(context.Background(), 0))
+	assert.Equal(migrationsTableColumns, f.columns)
+	assert.Equal([]uint{1, 2, 10}, f.appliedVersions())
+	assert.Equal([]string{"CREATE TABLE v (id integer);"}, f.scripts)
+}
+
+func TestRunner_LegacyTable_Dirty(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newFakeDb()
+	f.columns = legacyTableColumns
+	f.legacy = []legacyRow{{version: 2, dirty: true}}
+	r := newTestRunner(t, f)
+
+	_, err := r.Status(context.Background())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrMigrationDirty))
+	err = r.Up(context.Background(), 0)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrMigrationDirty))
+	assert.Equal(legacyTableColumns, f.columns)
+}
+
+func TestRunner_LegacyTable_UnknownVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newFakeDb()
+	f.columns = legacyTableColumns
+	f.legacy = []legacyRow{{version: 3}}
+	r := newTestRunner(t, f)
+
+	_, err := r.Status(context.Background())
+	assert.True(errors.IsErrorWithCode(err, errors.ErrUnknownMigrationVersion))
+}
@@ -0,0 +1,129 @@
+package migration
+
+import (
+	"context"
+
+	"github.com/KnoblauchPilze/go-game/pkg/db"
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+)
+
+type columnsParser struct {
+	columns map[string]bool
+}
+
+func (p *columnsParser) ScanRow(row db.Scannable) error {
+	var column string
+	if err := row.Scan(&column); err != nil {
+		return err
+	}
+
+	p.columns[column] = true
+	return nil
+}
+
+type legacyParser struct {
+	versions []int64
+	dirty    bool
+}
+
+func (p *legacyParser) ScanRow(row db.Scannable) error {
+	var version int64
+	var dirty bool
+	if err := row.Scan(&version, &dirty); err != nil {
+		return err
+	}
+
+	p.versions = append(p.versions, version)
+	p.dirty = p.dirty || dirty
+	return nil
+}
+
+type appliedParser struct {
+	applied map[uint]appliedMigration
+}
+
+func (p *appliedParser) ScanRow(row db.Scannable) error {
+	var version int64
+	var a appliedMigration
+	if err := row.Scan(&version, &a.name, &a.checksum); err != nil {
+		return err
+	}
+
+	a.version = uint(version)
+	p.applied[a.version] = a
+	return nil
+}
+
+// The table does not exist when no column is returned.
+func readMigrationsTableColumns(ctx context.Context, qe db.QueryExecutor) (map[string]bool, error) {
+	parser := &columnsParser{columns: make(map[string]bool)}
+	if err := qe.RunQueryAndScanAllResults(ctx, newRawQueryBuilder(columnsSql), parser); err != nil {
+		return nil, err
+	}
+
+	return parser.columns, nil
+}
+
+func readAppliedMigrations(ctx context.Context, qe db.QueryExecutor, migrations []Migration) (map[uint]appliedMigration, error) {
+	columns, err := readMigrationsTableColumns(ctx, qe)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return map[uint]appliedMigration{}, nil
+	}
+	if columns[legacyDirtyColumn] {
+		return readLegacyMigrations(ctx, qe, migrations)
+	}
+
+	return readAppliedMigrationsFromTable(ctx, qe)
+}
+
+func readAppliedMigrationsFromTable(ctx context.Context, qe db.QueryExecutor) (map[uint]appliedMigration, error) {
+	parser := &appliedParser{applied: make(map[uint]appliedMigration)}
+	if err := qe.RunQueryAndScanAllResults(ctx, newRawQueryBuilder(selectAppliedSql), parser); err != nil {
+		return nil, err
+	}
+
+	return parser.applied, nil
+}
+
+// The migrate tool only keeps the last version: all the migrations up
+// to it are applied.
+func readLegacyMigrations(ctx context.Context, qe db.QueryExecutor, migrations []Migration) (map[uint]appliedMigration, error) {
+	parser := &legacyParser{}
+	if err := qe.RunQueryAndScanAllResults(ctx, newRawQueryBuilder(selectLegacySql), parser); err != nil {
+		return nil, err
+	}
+
+	if parser.dirty {
+		return nil, errors.NewCode(errors.ErrMigrationDirty)
+	}
+
+	var last int64
+	for _, version := range parser.versions {
+		if version > last {
+			last = version
+		}
+	}
+
+	known := last == 0
+	applied := make(map[uint]appliedMigration)
+	for _, m := range migrations {
+		known = known || int64(m.Version) == last
+		if int64(m.Version) <= last {
+			applied[m.Version] = appliedMigration{
+				version:  m.Version,
+				name:     m.Name,
+				checksum: m.Checksum(),
+			}
+		}
+	}
+
+	if !known {
+		return nil, errors.WrapCode(errors.Newf("migration %d is applied but unknown", last), errors.ErrUnknownMigrationVersion)
+	}
+
+	return applied, nil
+}
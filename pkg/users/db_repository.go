@@ -20,6 +20,8 @@ const userNameColumnName = "name"
 const userPasswordColumnName = "password"
 const userCreatedAtColumnName = "created_at"
 
+var userMapper = db.MustNewStructMapper[User]()
+
 var insertQueryBuilderFunc = db.NewInsertQueryBuilder
 var selectQueryBuilderFunc = db.NewSelectQueryBuilder
 var inFilterBuilderFunc = db.NewInFilterBuilder
@@ -31,28 +33,34 @@ func NewDbRepository(qe db.QueryExecutor) Repository {
 	}
 }
 
-func (repo *userDbRepo) Create(ctx context.Context, user User) (uuid.UUID, error) {
-	out := user.Id
+func (repo *userDbRepo) Create(ctx context.Context, user User) (User, error) {
 	if err := user.validate(); err != nil {
-		return out, err
+		return User{}, err
 	}
 
 	qb := insertQueryBuilderFunc()
 
 	qb.SetTable(userTableName)
 
-	qb.AddElement(userIdColumnName, user.Id)
-	qb.AddElement(userMailColumnName, user.Mail)
-	qb.AddElement(userNameColumnName, user.Name)
-	qb.AddElement(userPasswordColumnName, user.Password)
+	if err := userMapper.AddElements(qb, user); err != nil {
+		return User{}, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+	}
+	if err := userMapper.AddReturning(qb); err != nil {
+		return User{}, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+	}
 
 	qb.SetVerbose(true)
 
-	if err := repo.qe.ExecuteQueryAffectingSingleRow(ctx, qb); err != nil {
-		return out, errors.WrapCode(err, errors.ErrUserCreationFailure)
+	scanner := userMapper.NewParser()
+	err := repo.qe.ExecuteQueryAndScanReturnedRow(ctx, qb, scanner)
+	if errors.IsErrorWithCode(err, errors.ErrDbUniqueViolation) {
+		return User{}, errors.WrapCode(err, errors.ErrUserAlreadyExists)
+	}
+	if err != nil {
+		return User{}, errors.WrapCode(err, errors.ErrUserCreationFailure)
 	}
 
-	return out, nil
+	return scanner.Value(), nil
 }
 
 func (repo *userDbRepo) Get(ctx context.Context, id uuid.UUID) (User, error) {
@@ -60,11 +68,9 @@ func (repo *userDbRepo) Get(ctx context.Context, id uuid.UUID) (User, error) {
 
 	qb.SetTable(userTableName)
 
-	qb.AddProp(userIdColumnName)
-	qb.AddProp(userMailColumnName)
-	qb.AddProp(userNameColumnName)
-	qb.AddProp(userPasswordColumnName)
-	qb.AddProp(userCreatedAtColumnName)
+	if err := userMapper.AddProps(qb); err != nil {
+		return User{}, errors.WrapCode(err, errors.ErrDbRequestCreationFailed)
+	}
 
 	fb := inFilterBuilderFunc()
 	fb.SetKey(userIdColumnName)
@@ -78,12 +84,12 @@ func (repo *userDbRepo) Get(ctx context.Context, id uuid.UUID) (User, error) {
 
 	qb.SetVerbose(true)
 
-	scanner := &userRowParser{}
+	scanner := userMapper.NewParser()
 	if err := repo.qe.RunQueryAndScanSingleResult(ctx, qb, scanner); err != nil {
 		return User{}, errors.WrapCode(err, errors.ErrUserGetFailure)
 	}
 
-	return scanner.user, nil
+	return scanner.Value(), nil
 }
 
 func (repo *userDbRepo) Delete(ctx context.Context, id uuid.UUID) error {
@@ -116,6 +122,9 @@ func (repo *userDbRepo) GetAll(ctx context.Context) ([]uuid.UUID, error) {
 
 	qb.AddProp(userIdColumnName)
 
+	qb.AddOrderBy(db.OrderBy{Column: userCreatedAtColumnName})
+	qb.AddOrderBy(db.OrderBy{Column: userIdColumnName})
+
 	qb.SetVerbose(true)
 
 	scanner := &userIdsParser{}
@@ -50,7 +50,7 @@ func TestDbRepository_CreateUser_QueryExecutorError(t *testing.T) {
 	assert := assert.New(t)
 
 	mqe := &mockQueryExecutor{
-		executeQueryErr: errDefault,
+		executeQueryAndScanReturnedRowErr: errDefault,
 	}
 	repo := NewDbRepository(mqe)
 
@@ -60,21 +60,59 @@ func TestDbRepository_CreateUser_QueryExecutorError(t *testing.T) {
 	assert.Equal(errDefault, cause)
 }
 
+func TestDbRepository_CreateUser_AlreadyExists(t *testing.T) {
+	assert := assert.New(t)
+
+	violation := errors.NewCode(errors.ErrDbUniqueViolation)
+	mqe := &mockQueryExecutor{
+		executeQueryAndScanReturnedRowErr: violation,
+	}
+	repo := NewDbRepository(mqe)
+
+	_, err := repo.Create(context.TODO(), defaultTestUser)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrUserAlreadyExists))
+	cause := errors.Unwrap(err)
+	assert.Equal(violation, cause)
+}
+
 func TestDbRepository_CreateUser(t *testing.T) {
 	assert := assert.New(t)
 
+	mqe := &mockQueryExecutor{
+		result:  1,
+		scanner: &mockScannable{},
+	}
+	repo := NewDbRepository(mqe)
+
+	_, err := repo.Create(context.TODO(), defaultTestUser)
+	assert.Nil(err)
+	assert.Equal(1, mqe.executeQueryAndScanReturnedRowCalled)
+	assert.Equal(1, mqe.scanner.scanCalled)
+	assert.Equal(1, len(mqe.queries))
+
+	q, err := mqe.queries[0].Build()
+	assert.Nil(err)
+	expectedQuery := "INSERT INTO users (id, mail, name, password) VALUES ($1, $2, $3, $4) RETURNING id, mail, name, password, created_at"
+	assert.Equal(expectedQuery, q.ToSql())
+	expectedArgs := []interface{}{"08ce96a3-3430-48a8-a3b2-b1c987a207ca", "some@mail", "someName", "somePassword"}
+	assert.Equal(expectedArgs, q.Args())
+}
+
+func TestDbRepository_CreateUser_WithoutId(t *testing.T) {
+	assert := assert.New(t)
+
 	mqe := &mockQueryExecutor{}
 	repo := NewDbRepository(mqe)
 
-	out, err := repo.Create(context.TODO(), defaultTestUser)
+	user := defaultTestUser
+	user.Id = uuid.Nil
+	_, err := repo.Create(context.TODO(), user)
 	assert.Nil(err)
-	assert.Equal(defaultTestUser.Id, out)
-	assert.Equal(1, mqe.executeQueryCalled)
 	assert.Equal(1, len(mqe.queries))
 
 	q, err := mqe.queries[0].Build()
 	assert.Nil(err)
-	expectedQuery := "INSERT INTO users (id, mail, name, password) VALUES ('08ce96a3-3430-48a8-a3b2-b1c987a207ca', 'some@mail', 'someName', 'somePassword')"
+	expectedQuery := "INSERT INTO users (mail, name, password) VALUES ($1, $2, $3) RETURNING id, mail, name, password, created_at"
 	assert.Equal(expectedQuery, q.ToSql())
 }
 
@@ -124,8 +162,10 @@ func TestDbRepository_GetUser(t *testing.T) {
 
 	q, err := mqe.queries[0].Build()
 	assert.Nil(err)
-	expectedQuery := "SELECT id, mail, name, password, created_at FROM users WHERE id in ('08ce96a3-3430-48a8-a3b2-b1c987a207ca')"
+	expectedQuery := "SELECT id, mail, name, password, created_at FROM users WHERE id in ($1)"
 	assert.Equal(expectedQuery, q.ToSql())
+	expectedArgs := []interface{}{"08ce96a3-3430-48a8-a3b2-b1c987a207ca"}
+	assert.Equal(expectedArgs, q.Args())
 }
 
 func TestDbRepository_Delete_QueryExecutorError(t *testing.T) {
@@ -173,8 +213,10 @@ func TestDbRepository_Delete(t *testing.T) {
 
 	q, err := mqe.queries[0].Build()
 	assert.Nil(err)
-	expectedQuery := "DELETE FROM users WHERE id in ('08ce96a3-3430-48a8-a3b2-b1c987a207ca')"
+	expectedQuery := "DELETE FROM users WHERE id in ($1)"
 	assert.Equal(expectedQuery, q.ToSql())
+	expectedArgs := []interface{}{"08ce96a3-3430-48a8-a3b2-b1c987a207ca"}
+	assert.Equal(expectedArgs, q.Args())
 }
 
 func TestDbRepository_GetAll_QueryExecutorError(t *testing.T) {
@@ -208,7 +250,7 @@ func TestDbRepository_GetAll(t *testing.T) {
 
 	q, err := mqe.queries[0].Build()
 	assert.Nil(err)
-	expectedQuery := "SELECT id FROM users"
+	expectedQuery := "SELECT id FROM users ORDER BY created_at, id"
 	assert.Equal(expectedQuery, q.ToSql())
 }
 
@@ -229,6 +271,14 @@ type mockQueryExecutor struct {
 	executeQueryCalled int
 	executeQueryErr    error
 
+	executeQueryAndScanReturnedRowCalled int
+	executeQueryAndScanReturnedRowErr    error
+
+	executeQueryAndScanReturnedRowsCalled int
+	executeQueryAndScanReturnedRowsErr    error
+
+	withTransactionCalled int
+
 	result  int
 	scanner *mockScannable
 
@@ -261,6 +311,11 @@ func (m *mockQueryExecutor) RunQueryAndScanAllResults(ctx context.Context, qb db
 	return m.runQueryAndScanAllResultsErr
 }
 
+func (m *mockQueryExecutor) RunQueryAndIterate(ctx context.Context, qb db.QueryBuilder) (db.Rows, error) {
+	m.queries = append(m.queries, qb)
+	return nil, nil
+}
+
 func (m *mockQueryExecutor) ExecuteQueryAffectingSingleRow(ctx context.Context, qb db.QueryBuilder) error {
 	m.executeQueryCalled++
 	m.queries = append(m.queries, qb)
@@ -268,6 +323,66 @@ func (m *mockQueryExecutor) ExecuteQueryAffectingSingleRow(ctx context.Context,
 	return m.executeQueryErr
 }
 
+func (m *mockQueryExecutor) ExecuteQueryAndScanReturnedRow(ctx context.Context, qb db.QueryBuilder, parser db.RowParser) error {
+	m.executeQueryAndScanReturnedRowCalled++
+	m.queries = append(m.queries, qb)
+
+	if m.scanner != nil && m.result > 0 {
+		for id := 0; id < m.result; id++ {
+			parser.ScanRow(m.scanner)
+		}
+	}
+
+	return m.executeQueryAndScanReturnedRowErr
+}
+
+func (m *mockQueryExecutor) ExecuteQueryAndScanReturnedRows(ctx context.Context, qb db.QueryBuilder, parser db.RowParser) error {
+	m.executeQueryAndScanReturnedRowsCalled++
+	m.queries = append(m.queries, qb)
+
+	if m.scanner != nil && m.result > 0 {
+		for id := 0; id < m.result; id++ {
+			parser.ScanRow(m.scanner)
+		}
+	}
+
+	return m.executeQueryAndScanReturnedRowsErr
+}
+
+func (m *mockQueryExecutor) ExecuteQueryAffectingAtMostOneRow(ctx context.Context, qb db.QueryBuilder) (int, error) {
+	return 0, nil
+}
+
+func (m *mockQueryExecutor) ExecuteQueryAffectingAtLeastOneRow(ctx context.Context, qb db.QueryBuilder) (int, error) {
+	return 0, nil
+}
+
+func (m *mockQueryExecutor) ExecuteQueryAffectingRows(ctx context.Context, qb db.QueryBuilder, expected int) (int, error) {
+	return 0, nil
+}
+
+func (m *mockQueryExecutor) ExecuteQueryAffectingAnyRows(ctx context.Context, qb db.QueryBuilder) (int, error) {
+	return 0, nil
+}
+
+func (m *mockQueryExecutor) ExecuteCopyFrom(ctx context.Context, cb db.CopyFromBuilder) (int, error) {
+	return 0, nil
+}
+
+func (m *mockQueryExecutor) ExecuteUpsert(ctx context.Context, qb db.QueryBuilder) (db.UpsertStatus, error) {
+	m.queries = append(m.queries, qb)
+	return db.UpsertSkipped, nil
+}
+
+func (m *mockQueryExecutor) Notify(ctx context.Context, channel string, payload string) error {
+	return nil
+}
+
+func (m *mockQueryExecutor) WithTransaction(ctx context.Context, fn db.TransactionFunc) error {
+	m.withTransactionCalled++
+	return fn(m)
+}
+
 type mockFilterBuilder struct {
 	buildErr error
 }
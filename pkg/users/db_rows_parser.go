@@ -5,14 +5,6 @@ import (
 	"github.com/google/uuid"
 )
 
-type userRowParser struct {
-	user User
-}
-
-func (p *userRowParser) ScanRow(row db.Scannable) error {
-	return row.Scan(&p.user.Id, &p.user.Mail, &p.user.Name, &p.user.Password, &p.user.CreatedAt)
-}
-
 type userIdsParser struct {
 	ids []uuid.UUID
 }
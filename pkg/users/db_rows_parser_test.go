@@ -6,26 +6,33 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestUserRowParser_ScanRow(t *testing.T) {
+func TestUserMapper_Columns(t *testing.T) {
+	assert := assert.New(t)
+
+	expected := []string{"id", "mail", "name", "password", "created_at"}
+	assert.Equal(expected, userMapper.Columns())
+}
+
+func TestUserMapper_ScanRow(t *testing.T) {
 	assert := assert.New(t)
 
 	m := &mockScannable{}
-	urp := userRowParser{}
+	p := userMapper.NewParser()
 
-	err := urp.ScanRow(m)
+	err := p.ScanRow(m)
 	assert.Nil(err)
 	assert.Equal(1, m.scanCalled)
 }
 
-func TestUserRowParser_ScanRow_Error(t *testing.T) {
+func TestUserMapper_ScanRow_Error(t *testing.T) {
 	assert := assert.New(t)
 
 	m := &mockScannable{
 		scanErr: errDefault,
 	}
-	urp := userRowParser{}
+	p := userMapper.NewParser()
 
-	err := urp.ScanRow(m)
+	err := p.ScanRow(m)
 	assert.Equal(errDefault, err)
 	assert.Equal(1, m.scanCalled)
 }
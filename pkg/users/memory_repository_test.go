@@ -0,0 +1,72 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/KnoblauchPilze/go-game/database/users/migrations"
+	"github.com/KnoblauchPilze/go-game/pkg/db"
+	"github.com/KnoblauchPilze/go-game/pkg/db/memory"
+	"github.com/KnoblauchPilze/go-game/pkg/errors"
+	"github.com/KnoblauchPilze/go-game/pkg/migration"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMemoryRepository(t *testing.T) Repository {
+	ms, err := migration.Load(migrations.Files)
+	assert.Nil(t, err)
+	tables, err := migration.MemorySchema(ms)
+	assert.Nil(t, err)
+
+	database := memory.NewDatabase(db.NewConfig(), tables...)
+	assert.Nil(t, database.Connect(context.Background()))
+	t.Cleanup(func() { database.Disconnect(context.Background()) })
+
+	return NewDbRepository(db.NewQueryExecutor(database))
+}
+
+func TestMemoryRepository(t *testing.T) {
+	assert := assert.New(t)
+
+	repo := newTestMemoryRepository(t)
+
+	created, err := repo.Create(context.Background(), User{Mail: "other@mail", Name: "otherName", Password: "otherPassword"})
+	assert.Nil(err)
+	assert.NotEqual(uuid.Nil, created.Id)
+	assert.False(created.CreatedAt.IsZero())
+
+	other, err := repo.Create(context.Background(), defaultTestUser)
+	assert.Nil(err)
+	assert.Equal(defaultTestUser.Id, other.Id)
+
+	actual, err := repo.Get(context.Background(), created.Id)
+	assert.Nil(err)
+	assert.Equal(created, actual)
+
+	ids, err := repo.GetAll(context.Background())
+	assert.Nil(err)
+	assert.ElementsMatch([]uuid.UUID{created.Id, other.Id}, ids)
+
+	assert.Nil(repo.Delete(context.Background(), created.Id))
+
+	_, err = repo.Get(context.Background(), created.Id)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrUserGetFailure))
+
+	err = repo.Delete(context.Background(), created.Id)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrUserDeletionFailure))
+}
+
+func TestMemoryRepository_DuplicatedMail(t *testing.T) {
+	assert := assert.New(t)
+
+	repo := newTestMemoryRepository(t)
+
+	_, err := repo.Create(context.Background(), defaultTestUser)
+	assert.Nil(err)
+
+	user := defaultTestUser
+	user.Id = uuid.Nil
+	_, err = repo.Create(context.Background(), user)
+	assert.True(errors.IsErrorWithCode(err, errors.ErrUserAlreadyExists))
+}
@@ -8,7 +8,7 @@ import (
 
 // https://threedots.tech/post/repository-pattern-in-go/
 type Repository interface {
-	Create(ctx context.Context, user User) (uuid.UUID, error)
+	Create(ctx context.Context, user User) (User, error)
 	Get(ctx context.Context, id uuid.UUID) (User, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 
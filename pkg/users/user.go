@@ -8,11 +8,11 @@ import (
 )
 
 type User struct {
-	Id        uuid.UUID
-	Mail      string
-	Name      string
-	Password  string
-	CreatedAt time.Time
+	Id        uuid.UUID `db:"id,omitempty"`
+	Mail      string    `db:"mail"`
+	Name      string    `db:"name"`
+	Password  string    `db:"password"`
+	CreatedAt time.Time `db:"created_at,readonly"`
 }
 
 func (u User) validate() error {